@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"net/url"
+	"os"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -57,7 +64,9 @@ func GetDriverPodName(app *v1beta2.SparkApplication) string {
 	}
 	return fmt.Sprintf("%s-driver", app.Name)
 }
-func GetDriverPort(sparkConfKeyValuePairs map[string]string) int {
+
+// GetDriverPort returns the driver RPC port from spark.driver.port, or the default one
+func GetDriverPort(sparkConfKeyValuePairs map[string]string) (int, error) {
 	//Checking if port information is passed in the spec, and using same
 	// or using the default ones
 	driverPortToBeUsed := DefaultDriverPort
@@ -65,12 +74,11 @@ func GetDriverPort(sparkConfKeyValuePairs map[string]string) int {
 	if valueExists {
 		driverPortSupplied, err := strconv.Atoi(driverPort)
 		if err != nil {
-			panic("Driver Port not parseable - hence failing the spark submit" + fmt.Sprint(err))
-		} else {
-			driverPortToBeUsed = driverPortSupplied
+			return 0, fmt.Errorf("invalid value %q for %s: %w", driverPort, SparkDriverPort, err)
 		}
+		driverPortToBeUsed = driverPortSupplied
 	}
-	return driverPortToBeUsed
+	return driverPortToBeUsed, nil
 }
 
 // Helper func to get Owner references to be added to Spark Application resources - pod, service, configmap
@@ -93,10 +101,14 @@ func GetOwnerReference(app *v1beta2.SparkApplication) *metav1.OwnerReference {
 	}
 }
 
-func GetOwnerReferenceFromCluster(app *v1beta2.SparkApplication) *metav1.OwnerReference {
+// Helper func to get the Owner reference of the SparkApplication as stored in the cluster, with its actual UID
+func GetOwnerReferenceFromCluster(app *v1beta2.SparkApplication) (*metav1.OwnerReference, error) {
 	// Register the SparkApplication scheme
 	_ = v1beta2.AddToScheme(scheme.Scheme)
-	dynamicClient := getKubeDynamicClientOrDie()
+	dynamicClient, err := getKubeDynamicClient()
+	if err != nil {
+		return nil, err
+	}
 
 	sparkAppGVR := schema.GroupVersionResource{
 		Group:    "sparkoperator.k8s.io",
@@ -107,21 +119,20 @@ func GetOwnerReferenceFromCluster(app *v1beta2.SparkApplication) *metav1.OwnerRe
 	//var sparkApp unstructured.Unstructured
 	sparkApp, err := dynamicClient.Resource(sparkAppGVR).Namespace(app.GetObjectMeta().GetNamespace()).Get(context.TODO(), app.GetObjectMeta().GetName(), metav1.GetOptions{})
 	if err != nil {
-		log.Fatalf("Error getting SparkApplication: %v", err)
+		return nil, fmt.Errorf("error getting SparkApplication %s in namespace %s: %w", app.Name, app.Namespace, err)
 	}
 
 	// Convert unstructured to SparkApplication
 	var sparkAppTyped v1beta2.SparkApplication
 	err = runtime.DefaultUnstructuredConverter.FromUnstructured(sparkApp.Object, &sparkAppTyped)
 	if err != nil {
-		log.Printf("Error converting to SparkApplication: %v", err)
-		return nil
+		return nil, fmt.Errorf("error converting to SparkApplication: %w", err)
 	}
 
 	ownerRef := GetOwnerReference(&sparkAppTyped)
-	fmt.Printf("Found SparkApplication in cluster - UID: %s, Name: %s, Kind: %s",
+	log.Printf("Found SparkApplication in cluster - UID: %s, Name: %s, Kind: %s",
 		ownerRef.UID, ownerRef.Name, ownerRef.Kind)
-	return ownerRef
+	return ownerRef, nil
 }
 
 // Helper func to get Owner references specifically for Service resources
@@ -187,25 +198,222 @@ func GetMemoryOverheadFactor(app *v1beta2.SparkApplication) string {
 	}
 	return memoryOverheadFactor
 }
+
 func CheckSparkConf(sparkConf map[string]string, configKey string) bool {
 	valueExists := false
 	_, valueExists = sparkConf[configKey]
 	return valueExists
 }
+
+// IsEnabledByAnnotationOrConf reads an optional boolean toggle from the application annotation or, when the
+// annotation is absent, from the sparkConf property. Both default to disabled.
+func IsEnabledByAnnotationOrConf(app *v1beta2.SparkApplication, annotation string, property string) (bool, error) {
+	if value, ok := app.Annotations[annotation]; ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid value %q for annotation %s: %w", value, annotation, err)
+		}
+		return enabled, nil
+	}
+	if CheckSparkConf(app.Spec.SparkConf, property) {
+		value := app.Spec.SparkConf[property]
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid value %q for %s: %w", value, property, err)
+		}
+		return enabled, nil
+	}
+	return false, nil
+}
+
+// GetIngressURL renders an ingress URL format for the Spark Application. Formats without a scheme are parsed as http.
+func GetIngressURL(ingressURLFormat string, app *v1beta2.SparkApplication) (*url.URL, error) {
+	ingressURL := strings.ReplaceAll(ingressURLFormat, IngressURLAppNamePlaceholder, app.Name)
+	ingressURL = strings.ReplaceAll(ingressURL, IngressURLAppNamespacePlaceholder, GetAppNamespace(app))
+	if !strings.Contains(ingressURL, "://") {
+		ingressURL = "http://" + ingressURL
+	}
+	parsedURL, err := url.Parse(ingressURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ingress URL format %q: %w", ingressURLFormat, err)
+	}
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("ingress URL format %q has no host", ingressURLFormat)
+	}
+	return parsedURL, nil
+}
+
+// GetSparkUIIngressURL returns the URL the Spark UI ingress serves, or nil when no UI ingress is created for the
+// Spark Application
+func GetSparkUIIngressURL(app *v1beta2.SparkApplication) (*url.URL, error) {
+	ingressURLFormat := os.Getenv(SparkUIIngressURLFormatEnvVar)
+	if app.Spec.SparkUIOptions == nil || ingressURLFormat == "" {
+		return nil, nil
+	}
+	return GetIngressURL(ingressURLFormat, app)
+}
+
+// IsPrometheusEnabled reports whether the Prometheus JMX exporter is configured for the Spark Application
+func IsPrometheusEnabled(app *v1beta2.SparkApplication) bool {
+	return app.Spec.Monitoring != nil && app.Spec.Monitoring.Prometheus != nil
+}
+
+// ExposesDriverMetrics reports whether the driver runs the Prometheus JMX exporter
+func ExposesDriverMetrics(app *v1beta2.SparkApplication) bool {
+	return IsPrometheusEnabled(app) && app.Spec.Monitoring.ExposeDriverMetrics
+}
+
+// ExposesExecutorMetrics reports whether the executors run the Prometheus JMX exporter
+func ExposesExecutorMetrics(app *v1beta2.SparkApplication) bool {
+	return IsPrometheusEnabled(app) && app.Spec.Monitoring.ExposeExecutorMetrics
+}
+
+// HasMetricsPropertiesContent reports whether the driver ConfigMap carries metrics.properties, which is the case
+// unless a metrics properties file in the image is used
+func HasMetricsPropertiesContent(app *v1beta2.SparkApplication) bool {
+	return IsPrometheusEnabled(app) && app.Spec.Monitoring.MetricsPropertiesFile == nil
+}
+
+// HasPrometheusConfigContent reports whether the driver ConfigMap carries prometheus.yaml, which is the case unless
+// a Prometheus configuration file in the image is used
+func HasPrometheusConfigContent(app *v1beta2.SparkApplication) bool {
+	return IsPrometheusEnabled(app) && app.Spec.Monitoring.Prometheus.ConfigFile == nil
+}
+
+// GetPrometheusPort returns the port of the Prometheus JMX exporter
+func GetPrometheusPort(app *v1beta2.SparkApplication) int32 {
+	if IsPrometheusEnabled(app) && app.Spec.Monitoring.Prometheus.Port != nil {
+		return *app.Spec.Monitoring.Prometheus.Port
+	}
+	return DefaultPrometheusJavaAgentPort
+}
+
+// GetPrometheusPortName returns the port name of the Prometheus JMX exporter
+func GetPrometheusPortName(app *v1beta2.SparkApplication) string {
+	if IsPrometheusEnabled(app) && app.Spec.Monitoring.Prometheus.PortName != nil {
+		return *app.Spec.Monitoring.Prometheus.PortName
+	}
+	return DefaultPrometheusPortName
+}
+
+// GetPrometheusAnnotations returns the prometheus.io scrape annotations of pods running the Prometheus JMX exporter
+func GetPrometheusAnnotations(app *v1beta2.SparkApplication) map[string]string {
+	return map[string]string{
+		PrometheusScrapeAnnotation: "true",
+		PrometheusPortAnnotation:   strconv.Itoa(int(GetPrometheusPort(app))),
+		PrometheusPathAnnotation:   PrometheusMetricsPath,
+	}
+}
+
 func Int64Pointer(a int64) *int64 {
 	return &a
 }
 
-func getKubeDynamicClientOrDie() *dynamic.DynamicClient {
+func getKubeDynamicClient() (*dynamic.DynamicClient, error) {
 	// Get the Kubernetes REST config (from kubeconfig or in-cluster)
 	config, err := ctrl.GetConfig()
 	if err != nil {
-		panic("failed to get kube config: " + err.Error())
+		return nil, fmt.Errorf("failed to get kube config: %w", err)
 	}
-	// Create the clientset
+	// Create the dynamic client
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Error creating dynamicClient: %v", err)
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return dynamicClient, nil
+}
+
+// ServerLocalFile is a sparkConf property naming a file that spark-submit reads on the submitting host.
+type ServerLocalFile struct {
+	// ConfKey is the sparkConf property holding the file path.
+	ConfKey string
+	// Alternative tells how to provide the file without the submission server reading it.
+	Alternative string
+}
+
+// serverLocalFiles lists the properties spark-submit reads files for, with the way to provide them instead.
+var serverLocalFiles = []ServerLocalFile{
+	{ConfKey: SparkKerberosKrb5PathKey, Alternative: "a ConfigMap of the application namespace named in " + SparkKerberosKrb5ConfigMapKey},
+	{ConfKey: SparkKerberosKeytabKey, Alternative: "a local:// path to a keytab mounted from a secret of the application namespace"},
+	{ConfKey: "spark.kubernetes.authenticate.driver.oauthTokenFile", Alternative: SparkDriverOAuthTokenKey + " or " + SparkDriverMountedCredentialsPrefix + "oauthTokenFile with a mounted secret"},
+	{ConfKey: "spark.kubernetes.authenticate.driver.clientKeyFile", Alternative: SparkDriverMountedCredentialsPrefix + "clientKeyFile with a mounted secret"},
+	{ConfKey: "spark.kubernetes.authenticate.driver.clientCertFile", Alternative: SparkDriverMountedCredentialsPrefix + "clientCertFile with a mounted secret"},
+	{ConfKey: "spark.kubernetes.authenticate.driver.caCertFile", Alternative: SparkDriverMountedCredentialsPrefix + "caCertFile with a mounted secret"},
+}
+
+// GetServerLocalFiles returns the sparkConf properties that would make the submission server read one of its own
+// files. The server is shared by every tenant, so these are rejected; a keytab already in the image through a
+// local:// URI is read by the driver and allowed.
+func GetServerLocalFiles(sparkConf map[string]string) []ServerLocalFile {
+	var files []ServerLocalFile
+	for _, file := range serverLocalFiles {
+		value := sparkConf[file.ConfKey]
+		if value == "" || (file.ConfKey == SparkKerberosKeytabKey && strings.HasPrefix(value, LocalScheme+"://")) {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// GetPortProtocol returns the protocol of a user declared port, defaulting to TCP like the Kubernetes API does.
+func GetPortProtocol(port v1beta2.Port) string {
+	if port.Protocol == "" {
+		return DefaultPortProtocol
+	}
+	return strings.ToUpper(port.Protocol)
+}
+
+// timeUnits maps the suffixes of Spark time strings to their unit, as in Spark's JavaUtils.timeStringAs
+var timeUnits = map[string]time.Duration{
+	"us":  time.Microsecond,
+	"ms":  time.Millisecond,
+	"s":   time.Second,
+	"m":   time.Minute,
+	"min": time.Minute,
+	"h":   time.Hour,
+	"d":   24 * time.Hour,
+}
+
+var timeStringRegex = regexp.MustCompile(`^([0-9]+)([a-z]+)?$`)
+
+// ParseTimeString parses a Spark time string, e.g. 60s or 5min, as Spark does. Suffixes are case-insensitive and
+// values without one are in defaultUnit, so a bare 60 is 60 seconds for a setting in seconds.
+func ParseTimeString(value string, defaultUnit time.Duration) (time.Duration, error) {
+	match := timeStringRegex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if match == nil {
+		return 0, fmt.Errorf("invalid time %q: must be a whole number with an optional us, ms, s, m, min, h or d suffix, e.g. 60s or 5min", value)
+	}
+
+	unit := defaultUnit
+	if match[2] != "" {
+		suffixUnit, ok := timeUnits[match[2]]
+		if !ok {
+			return 0, fmt.Errorf("invalid time %q: unknown suffix %q", value, match[2])
+		}
+		unit = suffixUnit
+	}
+	amount, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || amount > int64(math.MaxInt64/unit) {
+		return 0, fmt.Errorf("invalid time %q: value is too large", value)
+	}
+	return time.Duration(amount) * unit, nil
+}
+
+// GetDriverTemplateContainer returns the index and the driver container of spec.driver.template: the container named
+// spark-kubernetes-driver, or else its only container. The index is -1 when the template has no such container.
+func GetDriverTemplateContainer(app *v1beta2.SparkApplication) (int, *apiv1.Container) {
+	if app.Spec.Driver.Template == nil {
+		return -1, nil
+	}
+	containers := app.Spec.Driver.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == SparkDriverContainerName {
+			return i, &containers[i]
+		}
+	}
+	if len(containers) == 1 {
+		return 0, &containers[0]
 	}
-	return dynamicClient
+	return -1, nil
 }
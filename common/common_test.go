@@ -2,9 +2,11 @@ package common
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -187,6 +189,7 @@ func TestGetDriverPort(t *testing.T) {
 		name     string
 		conf     map[string]string
 		expected int
+		wantErr  bool
 	}{
 		{
 			name:     "default port",
@@ -200,11 +203,23 @@ func TestGetDriverPort(t *testing.T) {
 			},
 			expected: 8080,
 		},
+		{
+			name: "unparseable port",
+			conf: map[string]string{
+				SparkDriverPort: "port",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := GetDriverPort(tt.conf)
+			result, err := GetDriverPort(tt.conf)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -355,3 +370,103 @@ func TestCheckSparkConf(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIngressURL(t *testing.T) {
+	app := &v1beta2.SparkApplication{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "spark-jobs"}}
+	tests := []struct {
+		name       string
+		format     string
+		wantScheme string
+		wantHost   string
+		wantPath   string
+		wantErr    bool
+	}{
+		{name: "host template without scheme", format: "{{$appName}}.example.com", wantScheme: "http", wantHost: "my-app.example.com"},
+		{name: "path template with scheme", format: "https://example.com/{{$appNamespace}}/{{$appName}}", wantScheme: "https", wantHost: "example.com", wantPath: "/spark-jobs/my-app"},
+		{name: "missing host", format: "http:///{{$appName}}", wantErr: true},
+		{name: "invalid URL", format: "http://exa mple.com:port", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetIngressURL(tt.format, app)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantScheme, got.Scheme)
+			assert.Equal(t, tt.wantHost, got.Host)
+			assert.Equal(t, tt.wantPath, got.Path)
+		})
+	}
+}
+
+func TestGetSparkUIIngressURL(t *testing.T) {
+	app := &v1beta2.SparkApplication{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "spark-jobs"}}
+	t.Setenv(SparkUIIngressURLFormatEnvVar, "example.com/{{$appName}}")
+
+	got, err := GetSparkUIIngressURL(app)
+	assert.NoError(t, err)
+	assert.Nil(t, got, "no UI ingress without Spark UI options")
+
+	app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{}
+	got, err = GetSparkUIIngressURL(app)
+	assert.NoError(t, err)
+	assert.Equal(t, "/my-app", got.Path)
+}
+
+// Expected values are the ones Spark's JavaUtils.timeStringAs gives for the same strings
+func TestParseTimeString(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr string
+	}{
+		{value: "60", want: 60 * time.Second},
+		{value: "500ms", want: 500 * time.Millisecond},
+		{value: "30s", want: 30 * time.Second},
+		{value: " 2MIN ", want: 2 * time.Minute},
+		{value: "5m", want: 5 * time.Minute},
+		{value: "1h", want: time.Hour},
+		{value: "1d", want: 24 * time.Hour},
+		{value: "100us", want: 100 * time.Microsecond},
+		{value: "", wantErr: "must be a whole number"},
+		{value: "1.5s", wantErr: "must be a whole number"},
+		{value: "-1s", wantErr: "must be a whole number"},
+		{value: "1w", wantErr: "unknown suffix"},
+		{value: "99999999999d", wantErr: "too large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTimeString(tt.value, time.Second)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetDriverTemplateContainer(t *testing.T) {
+	app := &v1beta2.SparkApplication{}
+	index, container := GetDriverTemplateContainer(app)
+	assert.Equal(t, -1, index)
+	assert.Nil(t, container)
+
+	app.Spec.Driver.Template = &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Containers: []apiv1.Container{{Name: "main"}}}}
+	index, container = GetDriverTemplateContainer(app)
+	assert.Equal(t, 0, index)
+	assert.Equal(t, "main", container.Name, "the only container is the driver container")
+
+	app.Spec.Driver.Template.Spec.Containers = []apiv1.Container{{Name: "sidecar"}, {Name: SparkDriverContainerName}}
+	index, container = GetDriverTemplateContainer(app)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, SparkDriverContainerName, container.Name)
+
+	app.Spec.Driver.Template.Spec.Containers = []apiv1.Container{{Name: "a"}, {Name: "b"}}
+	index, container = GetDriverTemplateContainer(app)
+	assert.Equal(t, -1, index)
+	assert.Nil(t, container)
+}
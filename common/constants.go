@@ -7,6 +7,19 @@ const (
 	SparkDriverPort                   = "spark.driver.port"
 	JavaScalaMemoryOverheadFactor     = "0.10"
 	OtherLanguageMemoryOverheadFactor = "0.40"
+	// SparkDriverRole and SparkExecutorRole are the values of the spark-role label, and the role in per-role properties.
+	// SparkApplicationSelectorLabel is the AppID set by the spark-distribution on the driver/executors Pods.
+	SparkApplicationSelectorLabel = "spark-app-selector"
+	// SparkRoleLabel is the driver/executor label set by the operator/spark-distribution on the driver/executors Pods.
+	SparkRoleLabel = "spark-role"
+	// LabelAnnotationPrefix is the prefix of every labels and annotations added by the controller.
+	LabelAnnotationPrefix = "sparkoperator.k8s.io/"
+	// SparkAppNameLabel is the name of the label for the SparkApplication object name.
+	SparkAppNameLabel = LabelAnnotationPrefix + "app-name"
+	SparkDriverRole   = "driver"
+	SparkExecutorRole = "executor"
+	// SparkMemoryOverheadKey is the configuration property for the memory overhead of both the driver and executors.
+	SparkMemoryOverheadKey = "spark.kubernetes.memoryOverhead"
 	// SparkAppNamespaceKey is the configuration property for application namespace.
 	SparkAppNamespaceKey = "spark.kubernetes.namespace"
 	// SparkDriverPodNameKey is the Spark configuration key for driver pod name.
@@ -17,6 +30,9 @@ const (
 	// SparkDriverSecretKeyPrefix is the configuration property prefix for specifying secrets to be mounted into the
 	// driver.
 	SparkDriverSecretKeyPrefix = "spark.kubernetes.driver.secrets."
+	// SparkExecutorSecretKeyPrefix is the configuration property prefix for specifying secrets to be mounted into the
+	// executors.
+	SparkExecutorSecretKeyPrefix = "spark.kubernetes.executor.secrets."
 	// SparkConfDirEnvVar is the environment variable to add to the driver and executor Pods that point
 	// to the directory where the Spark ConfigMap is mounted.
 	SparkConfDirEnvVar = "SPARK_CONF_DIR"
@@ -25,8 +41,63 @@ const (
 	// SparkDriverSecretKeyRefKeyPrefix is the configuration property prefix for specifying environment variables
 	// from SecretKeyRefs for the driver.
 	SparkDriverSecretKeyRefKeyPrefix = "spark.kubernetes.driver.secretKeyRef."
+	// SparkExecutorSecretKeyRefKeyPrefix is the configuration property prefix for specifying environment variables
+	// from SecretKeyRefs for the executors.
+	SparkExecutorSecretKeyRefKeyPrefix = "spark.kubernetes.executor.secretKeyRef."
 	// SparkDriverCoreLimitKey is the configuration property for specifying the hard CPU limit for the driver pod.
 	SparkDriverCoreLimitKey = "spark.kubernetes.driver.limit.cores"
 	// SparkDriverCoreRequestKey is the configuration property for specifying the physical CPU request for the driver.
 	SparkDriverCoreRequestKey = "spark.kubernetes.driver.request.cores"
+	// SparkKerberosKeytabKey is the configuration property for the keytab used to log in to the KDC.
+	SparkKerberosKeytabKey = "spark.kerberos.keytab"
+	// SparkKerberosPrincipalKey is the configuration property for the principal used to log in to the KDC.
+	SparkKerberosPrincipalKey = "spark.kerberos.principal"
+	// SparkKerberosKrb5PathKey is the configuration property for a krb5.conf file local to the submission.
+	SparkKerberosKrb5PathKey = "spark.kubernetes.kerberos.krb5.path"
+	// SparkKerberosKrb5ConfigMapKey is the configuration property for a ConfigMap holding the krb5.conf.
+	SparkKerberosKrb5ConfigMapKey = "spark.kubernetes.kerberos.krb5.configMapName"
+	// KubernetesCredentialsMountPath is the directory where the driver's Kubernetes credentials are mounted.
+	KubernetesCredentialsMountPath = "/mnt/secrets/spark-kubernetes-credentials"
+	// SparkDriverOAuthTokenKey is the configuration property for an OAuth token the driver uses against the API server.
+	SparkDriverOAuthTokenKey = "spark.kubernetes.authenticate.driver.oauthToken"
+	// SparkDriverMountedCredentialsPrefix is the configuration property prefix pointing the driver at its mounted
+	// Kubernetes credentials files.
+	SparkDriverMountedCredentialsPrefix = "spark.kubernetes.authenticate.driver.mounted."
+	// SparkDriverMountedOAuthTokenFileKey points the driver at the OAuth token mounted from the credentials secret.
+	SparkDriverMountedOAuthTokenFileKey = SparkDriverMountedCredentialsPrefix + "oauthTokenFile"
+	// KubernetesOAuthTokenSecretKey is the item key of the OAuth token in the credentials secret, and its file name
+	// under KubernetesCredentialsMountPath.
+	KubernetesOAuthTokenSecretKey = "oauth-token"
+	// LocalScheme is the URI scheme of files that are already present in the container image.
+	LocalScheme = "local"
+	// DefaultPortProtocol is the protocol used for user declared ports that do not set one.
+	DefaultPortProtocol = "TCP"
+	// SparkUIPortKey is the configuration property for the port the Spark UI listens on.
+	SparkUIPortKey = "spark.ui.port"
+	// SparkConfDirPath is the directory the driver ConfigMap files are mounted in, Spark also ships its files to the
+	// executors.
+	SparkConfDirPath = "/opt/spark/conf"
+	// MetricsPropertiesFileName and PrometheusConfigFileName are the driver ConfigMap keys carrying the Spark metrics
+	// and Prometheus JMX exporter configurations.
+	MetricsPropertiesFileName = "metrics.properties"
+	PrometheusConfigFileName  = "prometheus.yaml"
+	// DefaultPrometheusJavaAgentPort is the default port used by the Prometheus JMX exporter.
+	DefaultPrometheusJavaAgentPort int32 = 8090
+	// DefaultPrometheusPortName is the default port name used by the Prometheus JMX exporter.
+	DefaultPrometheusPortName  = "jmx-exporter"
+	PrometheusScrapeAnnotation = "prometheus.io/scrape"
+	PrometheusPortAnnotation   = "prometheus.io/port"
+	PrometheusPathAnnotation   = "prometheus.io/path"
+	PrometheusMetricsPath      = "/metrics"
+	// SparkUIIngressURLFormatEnvVar is the URL format of the Spark UI ingress, e.g. {{$appName}}.spark.example.com or
+	// spark.example.com/{{$appNamespace}}/{{$appName}}. No UI ingress is created when it is unset.
+	SparkUIIngressURLFormatEnvVar = "SPARK_UI_INGRESS_URL_FORMAT"
+	// IngressClassNameEnvVar is the ingress class set on every ingress created by the submitter.
+	IngressClassNameEnvVar = "INGRESS_CLASS_NAME"
+	// IngressURLAppNamePlaceholder and IngressURLAppNamespacePlaceholder are replaced in ingress URL formats.
+	IngressURLAppNamePlaceholder      = "{{$appName}}"
+	IngressURLAppNamespacePlaceholder = "{{$appNamespace}}"
+	// ConfigHashAnnotation is the sha256 of the driver ConfigMap content, set on the ConfigMap and the driver pod to
+	// compare submissions and detect configuration drift.
+	ConfigHashAnnotation = LabelAnnotationPrefix + "config-hash"
 )
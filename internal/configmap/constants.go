@@ -1,9 +1,6 @@
 package configmap
 
 const (
-	ExecutorDefaultMemory          = "1g"
-	DriverDefaultMemory            = "1024m"
-	DriverDefaultCores             = "1"
 	SparkDriverCores               = "spark.driver.cores"
 	SparkFiles                     = "spark.files"
 	SparkPyFiles                   = "spark.pyFiles"
@@ -14,9 +11,7 @@ const (
 	EqualsSign                     = "="
 	SparkMetricConfKey             = "spark.metrics.conf"
 	SparkMetricsNamespaceKey       = "spark.metrics.namespace"
-	DefaultSparkConfFileName       = "spark-defaults.conf"
 	SparkDriverArgPropertyFilePath = "/opt/spark/conf/spark.properties"
-	SparkDefaultsConfigFilePath    = "/opt/spark/conf/"
 	SparkApplicationType           = "spark.kubernetes.resource.type"
 	SparkAppTypeR                  = "r"
 	SparkAppTypeRWithR             = "R"
@@ -32,7 +27,6 @@ const (
 	SparkUIProxyRedirectURI        = "spark.ui.proxyRedirectUri"
 	SparkUIProxyBase               = "spark.ui.proxyBase"
 	ForwardSlash                   = "/"
-	SparkApplicationSubmitTime     = "spark.app.submitTime"
 	SparkDriverBlockManagerPort    = "spark.driver.blockManager.port"
 	True                           = "true"
 	SubmitInDriver                 = "spark.kubernetes.submitInDriver"
@@ -46,8 +40,18 @@ const (
 	SparkAppId                     = "spark.app.id"
 	SparkMaster                    = "spark.master"
 	NewLineString                  = "\n"
+	CommentPrefix                  = "# "
 	SparkDriverHost                = "spark.driver.host"
 	DotSeparator                   = "."
+	// Sections of spark.properties, in the order they are written
+	SparkDefaultsSection = "spark-defaults.conf"
+	SubmissionSection    = "submission"
+	SparkConfSection     = "spec.sparkConf"
+	HadoopConfSection    = "spec.hadoopConf"
+	ResourcesSection     = "resources"
+	DriverSection        = "driver"
+	ExecutorSection      = "executor"
+	ApplicationSection   = "application"
 	// SparkConfDirEnvVar is the environment variable to add to the driver and executor Pods that point
 	// to the directory where the Spark ConfigMap is mounted.
 	SparkConfDirEnvVar = "SPARK_CONF_DIR"
@@ -80,8 +84,6 @@ const (
 	SparkImagePullSecretKey = "spark.kubernetes.container.image.pullSecrets"
 	// SparkContainerImagePullPolicyKey is the configuration property for specifying the container image pull policy.
 	SparkContainerImagePullPolicyKey = "spark.kubernetes.container.image.pullPolicy"
-	// SparkNodeSelectorKeyPrefix is the configuration property prefix for specifying node selector for the pods.
-	SparkNodeSelectorKeyPrefix = "spark.kubernetes.node.selector."
 	//SparkDriverNodeSelectorKeyPrefix is the configuration property prefix for specifying node selector for the driver pods.
 	SparkDriverNodeSelectorKeyPrefix = "spark.kubernetes.driver.node.selector."
 	//SparkExecutorNodeSelectorKeyPrefix is the configuration property prefix for specifying node selector for the driver pods.
@@ -99,6 +101,13 @@ const (
 	SparkDriverCoreLimitKey = "spark.kubernetes.driver.limit.cores"
 	// SparkExecutorCoreLimitKey is the configuration property for specifying the hard CPU limit for the executor pods.
 	SparkExecutorCoreLimitKey = "spark.kubernetes.executor.limit.cores"
+	// SparkDriverMemory and SparkExecutorMemory are the configuration properties for the driver and executor heap.
+	SparkDriverMemory   = "spark.driver.memory"
+	SparkExecutorMemory = "spark.executor.memory"
+	// SparkDriverMemoryOverhead and SparkExecutorMemoryOverhead are the configuration properties for the memory added
+	// on top of the driver and executor heap.
+	SparkDriverMemoryOverhead   = "spark.driver.memoryOverhead"
+	SparkExecutorMemoryOverhead = "spark.executor.memoryOverhead"
 	// SparkDriverSecretKeyPrefix is the configuration property prefix for specifying secrets to be mounted into the
 	// driver.
 	SparkDriverSecretKeyPrefix = "spark.kubernetes.driver.secrets."
@@ -148,6 +157,8 @@ const (
 	SparkExecutorJavaOptions = "spark.executor.extraJavaOptions"
 	// SparkExecutorDeleteOnTermination is the Spark configuration for specifying whether executor pods should be deleted in case of failure or normal termination
 	SparkExecutorDeleteOnTermination = "spark.kubernetes.executor.deleteOnTermination"
+	// SparkExecutorSchedulerName is the Spark configuration for specifying the scheduler of the executor pods
+	SparkExecutorSchedulerName = "spark.kubernetes.executor.scheduler.name"
 	// SparkDriverKubernetesMaster is the Spark configuration key for specifying the Kubernetes master the driver use
 	// to manage executor pods and other Kubernetes resources.
 	SparkDriverKubernetesMaster = "spark.kubernetes.driver.master"
@@ -4,27 +4,29 @@ import (
 	"fmt"
 	"log"
 	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
-	"github.com/magiconair/properties"
+	sparkcommon "github.com/kubeflow/spark-operator/pkg/common"
 	"k8s.io/client-go/kubernetes"
 )
 
 // Function to create Spark Application Configmap
 // Spark Application ConfigMap is pre-requisite for Driver Pod Creation; this configmap is mounted on driver pod
 // Spark Application ConfigMap acts as configuration repository for the Driver, executor pods
-func Create(app *v1beta2.SparkApplication, submissionID string, createdApplicationId string, kubeClient *kubernetes.Clientset, driverConfigMapName string, serviceName string) error {
+// config is the resolved configuration of the submission. Returns the hash of the ConfigMap content, to annotate the
+// driver pod with
+func Create(app *v1beta2.SparkApplication, config resolver.Config, submissionID string, createdApplicationId string, kubeClient kubernetes.Interface, driverConfigMapName string, serviceName string) (string, error) {
 	log.Printf("=== Starting ConfigMap creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
 	log.Printf("ConfigMap name: %s, SubmissionID: %s, ApplicationID: %s", driverConfigMapName, submissionID, createdApplicationId)
 
 	if app == nil {
 		log.Printf("ERROR: Spark application is nil")
-		return fmt.Errorf("spark application cannot be nil")
+		return "", fmt.Errorf("spark application cannot be nil")
 	}
 
 	var errorSubmissionCommandArgs error
@@ -43,26 +45,32 @@ func Create(app *v1beta2.SparkApplication, submissionID string, createdApplicati
 
 	// Utility function buildAltSubmissionCommandArgs to add other key, value configuration pairs
 	log.Printf("Building submission command arguments...")
-	driverConfigMapData[SparkPropertiesFileName], errorSubmissionCommandArgs = buildAltSubmissionCommandArgs(app, common.GetDriverPodName(app), submissionID, createdApplicationId, serviceName)
+	driverConfigMapData[SparkPropertiesFileName], errorSubmissionCommandArgs = buildAltSubmissionCommandArgs(app, config, common.GetDriverPodName(app), submissionID, createdApplicationId, serviceName)
 	if errorSubmissionCommandArgs != nil {
 		log.Printf("ERROR: Failed to build submission command args: %v", errorSubmissionCommandArgs)
-		return fmt.Errorf("failed to create submission command args for the driver configmap %s in namespace %s: %v", driverConfigMapName, app.Namespace, errorSubmissionCommandArgs)
+		return "", fmt.Errorf("failed to create submission command args for the driver configmap %s in namespace %s: %v", driverConfigMapName, app.Namespace, errorSubmissionCommandArgs)
 	}
 
 	log.Printf("Successfully built submission command arguments")
+
+	//Spark metrics and Prometheus JMX exporter configurations, mounted next to spark.properties
+	for fileName, content := range getMonitoringConfigFiles(app) {
+		driverConfigMapData[fileName] = content
+		log.Printf("Added monitoring configuration file: %s", fileName)
+	}
 	log.Printf("ConfigMap data keys: %v", getMapKeys(driverConfigMapData))
 	log.Printf("ConfigMap data size: %d bytes", calculateConfigMapSize(driverConfigMapData))
 
 	//Create Spark Application ConfigMap
 	log.Printf("Calling createConfigMapUtil to create/update ConfigMap...")
-	createErr := createConfigMapUtil(driverConfigMapName, app, driverConfigMapData, kubeClient)
+	contentHash, createErr := createConfigMapUtil(driverConfigMapName, app, driverConfigMapData, kubeClient)
 	if createErr != nil {
 		log.Printf("ERROR: Failed to create/update ConfigMap: %v", createErr)
-		return fmt.Errorf("failed to create/update driver configmap %s in namespace %s: %v", driverConfigMapName, app.Namespace, createErr)
+		return "", fmt.Errorf("failed to create/update driver configmap %s in namespace %s: %v", driverConfigMapName, app.Namespace, createErr)
 	}
 
 	log.Printf("=== Successfully created ConfigMap: %s in namespace: %s ===", driverConfigMapName, app.Namespace)
-	return nil
+	return contentHash, nil
 }
 
 // Helper function to get map keys for logging
@@ -93,20 +101,23 @@ func truncateString(s string, maxLen int) string {
 
 // Helper func to create key/value pairs required for the Spark Application Configmap
 // Majority of the code borrowed from Scala implementation
-func buildAltSubmissionCommandArgs(app *v1beta2.SparkApplication, driverPodName string, submissionID string, createdApplicationId string, serviceName string) (string, error) {
+// The properties are written in sections, each sorted by key, so that the same application always gets the same file
+func buildAltSubmissionCommandArgs(app *v1beta2.SparkApplication, config resolver.Config, driverPodName string, submissionID string, createdApplicationId string, serviceName string) (string, error) {
 	log.Printf("=== Building submission command arguments ===")
 	log.Printf("Driver pod name: %s, Service name: %s", driverPodName, serviceName)
 
-	var sb strings.Builder
+	var file propertiesFile
 	sparkConfKeyValuePairs := app.Spec.SparkConf
 	log.Printf("SparkConf key-value pairs count: %d", len(sparkConfKeyValuePairs))
 
+	// spark-defaults.conf comes first, for the application properties below to override it
+	populateProperties(file.section(SparkDefaultsSection), config.SparkDefaults)
+
 	masterURL, err := getMasterURL()
 	if err != nil {
 		log.Printf("ERROR: Failed to get master URL: %v", err)
-		return sb.String(), err
+		return "", err
 	}
-	masterURL = AddEscapeCharacter(masterURL)
 	log.Printf("Master URL: %s", masterURL)
 
 	//Construct Service Name
@@ -114,398 +125,337 @@ func buildAltSubmissionCommandArgs(app *v1beta2.SparkApplication, driverPodName
 	serviceName = fmt.Sprintf("%s.%s.%s", serviceName, app.Namespace, ServiceShortForm)
 	log.Printf("Constructed service name: %s", serviceName)
 
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverHost, serviceName))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkAppId, createdApplicationId))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkMaster, masterURL))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkSubmitDeploymentMode, string(app.Spec.Mode)))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkAppNamespaceKey, app.Namespace))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkAppNameKey, app.Name))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverPodNameKey, driverPodName))
-	sb.WriteString(NewLineString)
+	// The submission properties come before sparkConf, which can override them
+	submission := file.section(SubmissionSection)
+	submission.set(SparkDriverHost, serviceName)
+	submission.set(SparkAppId, createdApplicationId)
+	submission.set(SparkMaster, masterURL)
+	submission.set(SparkSubmitDeploymentMode, string(app.Spec.Mode))
+	submission.set(SparkAppNamespaceKey, app.Namespace)
+	submission.set(SparkAppNameKey, app.Name)
+	submission.set(SparkDriverPodNameKey, driverPodName)
 
 	log.Printf("Populating artifacts...")
-	sb.WriteString(populateArtifacts(*app))
+	populateArtifacts(submission, *app)
 
 	log.Printf("Populating container image details...")
-	sb.WriteString(populateContainerImageDetails(*app))
+	populateContainerImageDetails(submission, *app, config)
 	if app.Spec.PythonVersion != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkPythonVersion, *app.Spec.PythonVersion))
-		sb.WriteString(NewLineString)
+		submission.set(SparkPythonVersion, *app.Spec.PythonVersion)
 		log.Printf("Added Python version: %s", *app.Spec.PythonVersion)
 	}
-	if app.Spec.MemoryOverheadFactor != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkMemoryOverheadFactor, *app.Spec.MemoryOverheadFactor))
-		sb.WriteString(NewLineString)
-	} else {
-
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkMemoryOverheadFactor, common.GetMemoryOverheadFactor(app)))
-		sb.WriteString(NewLineString)
-	}
+	submission.set(SparkMemoryOverheadFactor, config.MemoryOverheadFactor.Value)
 
 	// Operator triggered spark-submit should never wait for App completion
-	sb.WriteString(fmt.Sprintf("%s=false", SparkWaitAppCompletion))
-	sb.WriteString(NewLineString)
+	submission.set(SparkWaitAppCompletion, "false")
 
+	sparkUIProxyBase, err := getSparkUIProxyBase(app)
+	if err != nil {
+		log.Printf("ERROR: Failed to get Spark UI ingress URL: %v", err)
+		return "", err
+	}
+
+	// The Spark UI proxy properties are generated below and always take precedence over sparkConf
 	log.Printf("Populating Spark configuration properties...")
-	sb.WriteString(populateSparkConfProperties(sparkConfKeyValuePairs))
+	populateSparkConfProperties(file.section(SparkConfSection), withoutKeys(sparkConfKeyValuePairs, SparkUIProxyBase, SparkUIProxyRedirectURI))
 
 	// Add Hadoop configuration properties.
 	log.Printf("Adding Hadoop configuration properties...")
+	hadoopConf := file.section(HadoopConfSection)
 	for key, value := range app.Spec.HadoopConf {
-		sb.WriteString(fmt.Sprintf("spark.hadoop.%s=%s", key, value))
-		sb.WriteString(NewLineString)
+		hadoopConf.set("spark.hadoop."+key, value)
 		log.Printf("Added Hadoop config: %s=%s", key, value)
 	}
 	if app.Spec.HadoopConf != nil || app.Spec.HadoopConfigMap != nil {
 		// Adding Environment variable
-		sb.WriteString(fmt.Sprintf("spark.hadoop.%s=%s", HadoopConfDir, HadoopConfDirPath))
-		sb.WriteString(NewLineString)
-	}
-
-	// Add the driver and executor configuration options.
-	// Note that when the controller submits the application, it expects that all dependencies are local
-	// so init-container is not needed and therefore no init-container image needs to be specified.
-	sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverLabelKeyPrefix, SparkAppNameLabel, app.Name))
-	sb.WriteString(NewLineString)
-	//driverConfOptions = append(driverConfOptions,
-	sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverLabelKeyPrefix, LaunchedBySparkOperatorLabel, "true"))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverLabelKeyPrefix, SubmissionIDLabel, submissionID))
-	sb.WriteString(NewLineString)
-
-	if app.Spec.Driver.Image != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverContainerImageKey, *app.Spec.Driver.Image))
-		sb.WriteString(NewLineString)
+		hadoopConf.set("spark.hadoop."+HadoopConfDir, HadoopConfDirPath)
 	}
 
+	resources := file.section(ResourcesSection)
 	log.Printf("Populating compute information...")
-	sbPtr, err := populateComputeInfo(&sb, *app, sparkConfKeyValuePairs)
-	if err != nil {
+	if err := populateComputeInfo(resources, config); err != nil {
 		log.Printf("ERROR: Failed to populate compute info: %v", err)
-		return "driver cores should be an integer", err
+		return "", err
 	}
-	sb = *sbPtr
 
 	log.Printf("Populating memory information...")
-	sb.WriteString(populateMemoryInfo(*app, sparkConfKeyValuePairs))
+	populateMemoryInfo(resources, config)
 
-	if app.Spec.Driver.ServiceAccount != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverServiceAccountName, *app.Spec.Driver.ServiceAccount))
-		sb.WriteString(NewLineString)
-	}
+	// Add the driver and executor configuration options.
+	// Note that when the controller submits the application, it expects that all dependencies are local
+	// so init-container is not needed and therefore no init-container image needs to be specified.
+	driver := file.section(DriverSection)
+	driver.set(SparkDriverLabelKeyPrefix+SparkAppNameLabel, app.Name)
+	driver.set(SparkDriverLabelKeyPrefix+LaunchedBySparkOperatorLabel, "true")
+	driver.set(SparkDriverLabelKeyPrefix+SubmissionIDLabel, submissionID)
 
-	if app.Spec.Driver.JavaOptions != nil {
-		driverJavaOptionsList := AddEscapeCharacter(*app.Spec.Driver.JavaOptions)
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverJavaOptions, driverJavaOptionsList))
-		sb.WriteString(NewLineString)
+	if config.Driver.Image.IsSet() {
+		driver.set(SparkDriverContainerImageKey, config.Driver.Image.Value)
 	}
 
-	if app.Spec.Driver.KubernetesMaster != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverKubernetesMaster, *app.Spec.Driver.KubernetesMaster))
-		sb.WriteString(NewLineString)
+	if config.Driver.ServiceAccount.IsSet() {
+		driver.set(SparkDriverServiceAccountName, config.Driver.ServiceAccount.Value)
 	}
 
-	//Populate SparkApplication Labels to Driver
-	driverLabels := make(map[string]string, len(app.Labels)+len(app.Spec.Driver.Labels))
-	for key, value := range app.Labels {
-		driverLabels[key] = value
+	if driverJavaOptions := getJavaOptions(app, app.Spec.Driver.JavaOptions, common.ExposesDriverMetrics(app)); driverJavaOptions != "" {
+		driver.set(SparkDriverJavaOptions, driverJavaOptions)
 	}
-	for key, value := range app.Spec.Driver.Labels {
-		driverLabels[key] = value
+
+	if app.Spec.Driver.KubernetesMaster != nil {
+		driver.set(SparkDriverKubernetesMaster, *app.Spec.Driver.KubernetesMaster)
 	}
 
-	for key, value := range driverLabels {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverLabelKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
+	//Populate the resolved labels to Driver, the driver labels overriding the application ones
+	for key, setting := range config.Driver.Labels {
+		driver.set(SparkDriverLabelKeyPrefix+key, setting.Value)
 	}
-	sb.WriteString(populateDriverAnnotations(*app))
+	populateDriverAnnotations(driver, config.Driver.Annotations)
 
 	for key, value := range app.Spec.Driver.EnvSecretKeyRefs {
-		sb.WriteString(fmt.Sprintf("%s%s=%s:%s", SparkDriverSecretKeyRefKeyPrefix, key, value.Name, value.Key))
-		sb.WriteString(NewLineString)
+		driver.set(SparkDriverSecretKeyRefKeyPrefix+key, value.Name+":"+value.Key)
 	}
 
 	for key, value := range app.Spec.Driver.ServiceAnnotations {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverServiceAnnotationKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
+		driver.set(SparkDriverServiceAnnotationKeyPrefix+key, value)
 	}
 
-	sb.WriteString(populateDriverSecrets(*app))
+	populateDriverSecrets(driver, *app)
 
 	for key, value := range app.Spec.Driver.EnvVars {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverEnvVarConfigKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
-
+		driver.set(SparkDriverEnvVarConfigKeyPrefix+key, value)
 	}
 
-	for key, value := range app.Spec.Driver.Env {
-		sb.WriteString(fmt.Sprintf("%s%d=%s", SparkDriverEnvVarConfigKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
+	// Env vars with a valueFrom source are set on the driver pod spec, only plain values can go through the sparkConf
+	for _, envVar := range app.Spec.Driver.Env {
+		if envVar.ValueFrom == nil {
+			driver.set(SparkDriverEnvVarConfigKeyPrefix+envVar.Name, envVar.Value)
+		}
 	}
 
-	sb.WriteString(fmt.Sprintf("%s%s=%s", SparkExecutorLabelKeyPrefix, SparkAppNameLabel, app.Name))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s%s=%s", SparkExecutorLabelKeyPrefix, LaunchedBySparkOperatorLabel, "true"))
-	sb.WriteString(NewLineString)
+	// The resolved node selectors already merge the application wide ones, as the driver pod does
+	log.Printf("Adding node selectors...")
+	populateNodeSelector(driver, SparkDriverNodeSelectorKeyPrefix, config.Driver.NodeSelector)
+
+	// The driver listens on the ports the driver container and service expose
+	for _, driverPort := range []struct{ key, name string }{
+		{SparkDriverBlockManagerPort, resolver.BlockManagerPortName},
+		{common.SparkDriverPort, resolver.DriverPortName},
+	} {
+		port, err := resolver.ParsePort(config.Driver.Ports[driverPort.name])
+		if err != nil {
+			return "", err
+		}
+		driver.set(driverPort.key, fmt.Sprint(port))
+	}
 
-	sb.WriteString(fmt.Sprintf("%s%s=%s", SparkExecutorLabelKeyPrefix, SubmissionIDLabel, submissionID))
-	sb.WriteString(NewLineString)
+	executor := file.section(ExecutorSection)
+	executor.set(SparkExecutorLabelKeyPrefix+SparkAppNameLabel, app.Name)
+	executor.set(SparkExecutorLabelKeyPrefix+LaunchedBySparkOperatorLabel, "true")
+	executor.set(SparkExecutorLabelKeyPrefix+SubmissionIDLabel, submissionID)
 
 	if app.Spec.Executor.Instances != nil {
-		sb.WriteString(fmt.Sprintf("spark.executor.instances=%d", *app.Spec.Executor.Instances))
-		sb.WriteString(NewLineString)
+		executor.set("spark.executor.instances", fmt.Sprint(*app.Spec.Executor.Instances))
 	}
 
-	if app.Spec.Executor.Image != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkExecutorContainerImageKey, *app.Spec.Executor.Image))
-		sb.WriteString(NewLineString)
+	if config.Executor.Image.IsSet() {
+		executor.set(SparkExecutorContainerImageKey, config.Executor.Image.Value)
 	}
 
-	if app.Spec.Executor.ServiceAccount != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkExecutorAccountName, *app.Spec.Executor.ServiceAccount))
-		sb.WriteString(NewLineString)
+	if config.Executor.ServiceAccount.IsSet() {
+		executor.set(SparkExecutorAccountName, config.Executor.ServiceAccount.Value)
 	}
 
+	populateSetting(executor, SparkExecutorSchedulerName, config.Executor.SchedulerName)
+
 	if app.Spec.Executor.DeleteOnTermination != nil {
-		sb.WriteString(fmt.Sprintf("%s=%t", SparkExecutorDeleteOnTermination, *app.Spec.Executor.DeleteOnTermination))
-		sb.WriteString(NewLineString)
+		executor.set(SparkExecutorDeleteOnTermination, fmt.Sprint(*app.Spec.Executor.DeleteOnTermination))
 	}
 
-	//Populate SparkApplication Labels to Executors
-	executorLabels := make(map[string]string, len(app.Labels)+len(app.Spec.Executor.Labels))
-	for key, value := range app.Labels {
-		executorLabels[key] = value
-	}
-	for key, value := range app.Spec.Executor.Labels {
-		executorLabels[key] = value
-	}
-	for key, value := range executorLabels {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkExecutorLabelKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
+	//Populate the resolved labels to Executors, the executor labels overriding the application ones
+	for key, setting := range config.Executor.Labels {
+		executor.set(SparkExecutorLabelKeyPrefix+key, setting.Value)
 	}
 
-	sb.WriteString(populateExecutorAnnotations(*app))
+	populateExecutorAnnotations(executor, *app, config.Executor.Annotations)
 
 	for key, value := range app.Spec.Executor.EnvSecretKeyRefs {
-		sb.WriteString(fmt.Sprintf("%s%s=%s:%s", SparkExecutorSecretKeyRefKeyPrefix, key, value.Name, value.Key))
-		sb.WriteString(NewLineString)
+		executor.set(SparkExecutorSecretKeyRefKeyPrefix+key, value.Name+":"+value.Key)
 	}
 
-	if app.Spec.Executor.JavaOptions != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkExecutorJavaOptions, *app.Spec.Executor.JavaOptions))
-		sb.WriteString(NewLineString)
+	if executorJavaOptions := getJavaOptions(app, app.Spec.Executor.JavaOptions, common.ExposesExecutorMetrics(app)); executorJavaOptions != "" {
+		executor.set(SparkExecutorJavaOptions, executorJavaOptions)
 	}
 
-	sb.WriteString(populateExecutorSecrets(*app))
+	populateExecutorSecrets(executor, *app)
 
 	for key, value := range app.Spec.Executor.EnvVars {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkExecutorEnvVarConfigKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
+		executor.set(SparkExecutorEnvVarConfigKeyPrefix+key, value)
 	}
 
-	log.Printf("Populating dynamic allocation...")
-	sb.WriteString(populateDynamicAllocation("", *app))
-
-	log.Printf("Adding node selectors...")
-	for key, value := range app.Spec.NodeSelector {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkNodeSelectorKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
-		log.Printf("Added node selector: %s=%s", key, value)
-	}
-	for key, value := range app.Spec.Driver.NodeSelector {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkDriverNodeSelectorKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
-	}
-	for key, value := range app.Spec.Executor.NodeSelector {
-		sb.WriteString(fmt.Sprintf("%s%s=%s", SparkExecutorNodeSelectorKeyPrefix, key, value))
-		sb.WriteString(NewLineString)
-	}
+	populateNodeSelector(executor, SparkExecutorNodeSelectorKeyPrefix, config.Executor.NodeSelector)
 
-	sb.WriteString(fmt.Sprintf("%s=%s", SubmitInDriver, True))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%v", SparkDriverBlockManagerPort, common.DefaultBlockManagerPort))
-	sb.WriteString(NewLineString)
-
-	sb.WriteString(fmt.Sprintf("%s=%v", common.SparkDriverPort, common.GetDriverPort(sparkConfKeyValuePairs)))
-	sb.WriteString(NewLineString)
-	sb.WriteString(populateAppSpecType(*app))
-	sb.WriteString(NewLineString)
-	sb.WriteString(fmt.Sprintf("%s=%v", SparkApplicationSubmitTime, time.Now().UnixMilli()))
-	sb.WriteString(NewLineString)
-
-	sparkUIProxyBase := path.Join(ForwardSlash, common.GetAppNamespace(app), app.Name)
-
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkUIProxyBase, sparkUIProxyBase))
-	sb.WriteString(NewLineString)
+	application := file.section(ApplicationSection)
+	log.Printf("Populating dynamic allocation...")
+	populateDynamicAllocation(application, *app)
 
-	sb.WriteString(fmt.Sprintf("%s=%s", SparkUIProxyRedirectURI, ForwardSlash))
-	sb.WriteString(NewLineString)
+	application.set(SubmitInDriver, True)
+	populateAppSpecType(application, *app)
 
-	sb.WriteString(populateProperties())
+	if sparkUIProxyBase != "" {
+		application.set(SparkUIProxyBase, sparkUIProxyBase)
+		application.set(SparkUIProxyRedirectURI, ForwardSlash)
+	}
 
-	sb.WriteString(populateMonitoringInfo(*app))
+	populateMonitoringInfo(application, *app)
 
 	// Volumes
 	if app.Spec.Volumes != nil {
-		options, err := addLocalDirConfOptions(app)
-		if err != nil {
+		if err := addLocalDirConfOptions(application, app); err != nil {
 			return "error occcurred while building configmap", err
 		}
-		for _, option := range options {
-			sb.WriteString(option)
-			sb.WriteString(NewLineString)
-		}
 	}
 
 	if app.Spec.MainApplicationFile != nil {
 		// Add the main application file if it is present.
-		sparkAppJar := AddEscapeCharacter(*app.Spec.MainApplicationFile)
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkJars, sparkAppJar))
-		sb.WriteString(NewLineString)
+		application.set(SparkJars, *app.Spec.MainApplicationFile)
 		log.Printf("Added main application file: %s", *app.Spec.MainApplicationFile)
 	}
-	// Add application arguments.
-	log.Printf("Adding application arguments...")
-	for _, argument := range app.Spec.Arguments {
-		sb.WriteString(argument)
-		sb.WriteString(NewLineString)
-		log.Printf("Added argument: %s", argument)
-	}
+	// The application arguments are the driver container args, not properties
 
-	finalPayload := sb.String()
+	finalPayload := file.String()
 	log.Printf("=== Submission command arguments built successfully ===")
 	log.Printf("Final payload length: %d characters", len(finalPayload))
 	log.Printf("Payload preview (first 500 chars): %s", truncateString(finalPayload, 500))
 
 	return finalPayload, nil
 }
-func populateDriverAnnotations(app v1beta2.SparkApplication) string {
-	args := ""
-	for key, value := range app.Spec.Driver.Annotations {
+func populateDriverAnnotations(section propertiesSection, driverAnnotations map[string]resolver.Setting) {
+	for key, value := range resolver.Values(driverAnnotations) {
 		if key == OpencensusPrometheusTarget {
 			value = strings.Replace(value, "\n", "", -1)
-			value = AddEscapeCharacter(value)
 		}
-		args = args + fmt.Sprintf("%s%s=%s", SparkDriverAnnotationKeyPrefix, key, value) + NewLineString
+		section.set(SparkDriverAnnotationKeyPrefix+key, value)
+	}
+}
+
+// getSparkUIProxyBase returns the path prefix the Spark UI is served under: the path of the Spark UI ingress when
+// there is one, empty for an ingress on the root path, otherwise /<namespace>/<app name>
+func getSparkUIProxyBase(app *v1beta2.SparkApplication) (string, error) {
+	ingressURL, err := common.GetSparkUIIngressURL(app)
+	if err != nil {
+		return "", err
 	}
-	return args
+	if ingressURL == nil {
+		return path.Join(ForwardSlash, common.GetAppNamespace(app), app.Name), nil
+	}
+	return strings.TrimSuffix(ingressURL.Path, ForwardSlash), nil
 }
-func populateSparkConfProperties(sparkConfKeyValuePairs map[string]string) string {
-	args := ""
+
+// withoutKeys returns a copy of the sparkConf without the given keys
+func withoutKeys(sparkConf map[string]string, keys ...string) map[string]string {
+	filtered := make(map[string]string, len(sparkConf))
+	for key, value := range sparkConf {
+		filtered[key] = value
+	}
+	for _, key := range keys {
+		delete(filtered, key)
+	}
+	return filtered
+}
+
+func populateSparkConfProperties(section propertiesSection, sparkConfKeyValuePairs map[string]string) {
 	// Priority wise: Spark Application Specification value, if not, then value in sparkConf, if not, then, defaults that get applied by Spark Environment of Driver pod
 	// Add Spark configuration properties.
 	for key, value := range sparkConfKeyValuePairs {
 		// Configuration property for the driver pod name has already been set.
 		if key != SparkDriverPodNameKey {
-			//Adding escape character for the spark.executor.extraClassPath and spark.driver.extraClassPath
-			if key == SparkDriverExtraClassPath || key == SparkExecutorExtraClassPath {
-				value = AddEscapeCharacter(value)
+			// The OAuth token is shipped in the Kubernetes credentials secret and must not leak into the properties file
+			if key == common.SparkDriverOAuthTokenKey {
+				continue
 			}
-			args = args + fmt.Sprintf("%s=%s", key, value) + NewLineString
+			section.set(key, value)
 		}
 	}
-	return args
+	populateMountedCredentials(section, sparkConfKeyValuePairs)
 }
-func populateDriverSecrets(app v1beta2.SparkApplication) string {
-	args := ""
+
+// populateMountedCredentials points the driver at the OAuth token mounted from the secret created along with the
+// driver pod
+func populateMountedCredentials(section propertiesSection, sparkConfKeyValuePairs map[string]string) {
+	if sparkConfKeyValuePairs[common.SparkDriverOAuthTokenKey] != "" {
+		section.set(common.SparkDriverMountedOAuthTokenFileKey, path.Join(common.KubernetesCredentialsMountPath, common.KubernetesOAuthTokenSecretKey))
+	}
+}
+func populateDriverSecrets(section propertiesSection, app v1beta2.SparkApplication) {
 	for _, s := range app.Spec.Driver.Secrets {
-		args = args + fmt.Sprintf("%s%s=%s", SparkDriverSecretKeyPrefix, s.Name, s.Path) + NewLineString
+		section.set(SparkDriverSecretKeyPrefix+s.Name, s.Path)
 		//secretConfOptions = append(secretConfOptions, conf)
 		if s.Type == v1beta2.SecretTypeGCPServiceAccount {
-			args = args + fmt.Sprintf(
-				"%s%s=%s",
-				SparkDriverEnvVarConfigKeyPrefix,
-				GoogleApplicationCredentialsEnvVar,
-				filepath.Join(s.Path, ServiceAccountJSONKeyFileName)) + NewLineString
+			section.set(SparkDriverEnvVarConfigKeyPrefix+GoogleApplicationCredentialsEnvVar, filepath.Join(s.Path, ServiceAccountJSONKeyFileName))
 
 		} else if s.Type == v1beta2.SecretTypeHadoopDelegationToken {
-			args = args + fmt.Sprintf(
-				"%s%s=%s",
-				SparkDriverEnvVarConfigKeyPrefix,
-				HadoopTokenFileLocationEnvVar,
-				filepath.Join(s.Path, HadoopDelegationTokenFileName)) + NewLineString
+			section.set(SparkDriverEnvVarConfigKeyPrefix+HadoopTokenFileLocationEnvVar, filepath.Join(s.Path, HadoopDelegationTokenFileName))
 
 		}
 	}
-	return args
 }
-func populateExecutorAnnotations(app v1beta2.SparkApplication) string {
-	args := ""
-	for key, value := range app.Spec.Executor.Annotations {
+func populateExecutorAnnotations(section propertiesSection, app v1beta2.SparkApplication, executorAnnotations map[string]resolver.Setting) {
+	// Executors running the Prometheus JMX exporter get the scrape annotations, unless overridden in the spec
+	annotations := make(map[string]string)
+	if common.ExposesExecutorMetrics(&app) {
+		for key, value := range common.GetPrometheusAnnotations(&app) {
+			annotations[key] = value
+		}
+	}
+	for key, setting := range executorAnnotations {
+		annotations[key] = setting.Value
+	}
+	for key, value := range annotations {
 		if key == OpencensusPrometheusTarget {
 			value = strings.Replace(value, "\n", "", -1)
-			value = AddEscapeCharacter(value)
 		}
-		args = args + fmt.Sprintf("%s%s=%s", SparkExecutorAnnotationKeyPrefix, key, value) + NewLineString
+		section.set(SparkExecutorAnnotationKeyPrefix+key, value)
 	}
-	return args
 }
-func populateExecutorSecrets(app v1beta2.SparkApplication) string {
-	args := ""
+func populateExecutorSecrets(section propertiesSection, app v1beta2.SparkApplication) {
 	for _, s := range app.Spec.Executor.Secrets {
-		args = args + fmt.Sprintf("%s%s=%s", SparkExecutorSecretKeyPrefix, s.Name, s.Path) + NewLineString
+		section.set(SparkExecutorSecretKeyPrefix+s.Name, s.Path)
 		if s.Type == v1beta2.SecretTypeGCPServiceAccount {
-			args = args + fmt.Sprintf(
-				"%s%s=%s",
-				SparkExecutorEnvVarConfigKeyPrefix,
-				GoogleApplicationCredentialsEnvVar,
-				filepath.Join(s.Path, ServiceAccountJSONKeyFileName)) + NewLineString
+			section.set(SparkExecutorEnvVarConfigKeyPrefix+GoogleApplicationCredentialsEnvVar, filepath.Join(s.Path, ServiceAccountJSONKeyFileName))
 
 		} else if s.Type == v1beta2.SecretTypeHadoopDelegationToken {
-			args = args + fmt.Sprintf(
-				"%s%s=%s",
-				SparkExecutorEnvVarConfigKeyPrefix,
-				HadoopTokenFileLocationEnvVar,
-				filepath.Join(s.Path, HadoopDelegationTokenFileName)) + NewLineString
+			section.set(SparkExecutorEnvVarConfigKeyPrefix+HadoopTokenFileLocationEnvVar, filepath.Join(s.Path, HadoopDelegationTokenFileName))
 		}
 	}
-	return args
 }
-func populateDynamicAllocation(args string, app v1beta2.SparkApplication) string {
+func populateDynamicAllocation(section propertiesSection, app v1beta2.SparkApplication) {
 	if app.Spec.DynamicAllocation != nil {
 		log.Printf("Dynamic allocation is enabled")
-		args = args + fmt.Sprintf("%s=true", SparkDynamicAllocationEnabled) + NewLineString
+		section.set(SparkDynamicAllocationEnabled, "true")
 		// Turn on shuffle tracking if dynamic allocation is enabled.
-		args = args + fmt.Sprintf("%s=true", SparkDynamicAllocationShuffleTrackingEnabled) + NewLineString
+		section.set(SparkDynamicAllocationShuffleTrackingEnabled, "true")
 		dynamicAllocation := app.Spec.DynamicAllocation
 		if dynamicAllocation.InitialExecutors != nil {
-			args = args + fmt.Sprintf("%s=%d", SparkDynamicAllocationInitialExecutors, *dynamicAllocation.InitialExecutors) + NewLineString
+			section.set(SparkDynamicAllocationInitialExecutors, fmt.Sprint(*dynamicAllocation.InitialExecutors))
 			log.Printf("Added initial executors: %d", *dynamicAllocation.InitialExecutors)
 		}
 		if dynamicAllocation.MinExecutors != nil {
-			args = args + fmt.Sprintf("%s=%d", SparkDynamicAllocationMinExecutors, *dynamicAllocation.MinExecutors) + NewLineString
+			section.set(SparkDynamicAllocationMinExecutors, fmt.Sprint(*dynamicAllocation.MinExecutors))
 			log.Printf("Added min executors: %d", *dynamicAllocation.MinExecutors)
 		}
 		if dynamicAllocation.MaxExecutors != nil {
-			args = args + fmt.Sprintf("%s=%d", SparkDynamicAllocationMaxExecutors, *dynamicAllocation.MaxExecutors) + NewLineString
+			section.set(SparkDynamicAllocationMaxExecutors, fmt.Sprint(*dynamicAllocation.MaxExecutors))
 			log.Printf("Added max executors: %d", *dynamicAllocation.MaxExecutors)
 		}
 		if dynamicAllocation.ShuffleTrackingTimeout != nil {
-			args = args + fmt.Sprintf("%s=%d", SparkDynamicAllocationShuffleTrackingTimeout, *dynamicAllocation.ShuffleTrackingTimeout) + NewLineString
+			section.set(SparkDynamicAllocationShuffleTrackingTimeout, fmt.Sprint(*dynamicAllocation.ShuffleTrackingTimeout))
 			log.Printf("Added shuffle tracking timeout: %d", *dynamicAllocation.ShuffleTrackingTimeout)
 		}
 	} else {
 		log.Printf("Dynamic allocation is not enabled")
 	}
-	return args
 }
-func populateAppSpecType(app v1beta2.SparkApplication) string {
-	args := ""
+func populateAppSpecType(section propertiesSection, app v1beta2.SparkApplication) {
 	appSpecType := app.Spec.Type
 	if appSpecType == SparkAppTypeScala || appSpecType == SparkAppTypeJavaCamelCase {
 		appSpecType = SparkAppTypeJava
@@ -514,168 +464,141 @@ func populateAppSpecType(app v1beta2.SparkApplication) string {
 	} else if appSpecType == SparkAppTypeRWithR {
 		appSpecType = SparkAppTypeR
 	}
-	args = args + fmt.Sprintf("%s=%s", SparkApplicationType, appSpecType) + NewLineString
-	return args
+	section.set(SparkApplicationType, string(appSpecType))
 }
-func populateProperties() string {
-	args := ""
-	sparkDefaultConfFilePath := SparkDefaultsConfigFilePath + DefaultSparkConfFileName
-	propertyPairs, propertyFileReadError := properties.LoadFile(sparkDefaultConfFilePath, properties.UTF8)
-	if propertyFileReadError == nil {
-		keysList := propertyPairs.Keys()
-		for _, key := range keysList {
-			value := propertyPairs.GetString(key, "")
-			args = args + fmt.Sprintf("%s=%s", key, value) + NewLineString
-		}
+
+// populateProperties writes the properties of spark-defaults.conf, as read when the configuration was resolved
+func populateProperties(section propertiesSection, sparkDefaults map[string]string) {
+	for key, value := range sparkDefaults {
+		section.set(key, value)
 	}
-	return args
 }
-func populateMonitoringInfo(app v1beta2.SparkApplication) string {
-	args := ""
+func populateMonitoringInfo(section propertiesSection, app v1beta2.SparkApplication) {
 	//Monitoring Section
 	if app.Spec.Monitoring != nil {
 		SparkMetricsNamespace := common.GetAppNamespace(&app) + DotSeparator + app.Name
-		args = args + fmt.Sprintf("%s=%s", SparkMetricsNamespaceKey, SparkMetricsNamespace) + NewLineString
+		section.set(SparkMetricsNamespaceKey, SparkMetricsNamespace)
 
-		// Spark Metric Properties file
+		// Spark Metric Properties file, either in the image or shipped in the driver ConfigMap
 		if app.Spec.Monitoring.MetricsPropertiesFile != nil {
-			args = args + fmt.Sprintf("%s=%s", SparkMetricConfKey, *app.Spec.Monitoring.MetricsPropertiesFile) + NewLineString
+			section.set(SparkMetricConfKey, *app.Spec.Monitoring.MetricsPropertiesFile)
+		} else if common.HasMetricsPropertiesContent(&app) {
+			section.set(SparkMetricConfKey, path.Join(common.SparkConfDirPath, common.MetricsPropertiesFileName))
 		}
 	}
-	return args
 }
-func populateArtifacts(app v1beta2.SparkApplication) string {
-	args := ""
-	if len(app.Spec.Deps.Jars) > 0 {
-		modifiedJarList := make([]string, 0, len(app.Spec.Deps.Jars))
-		for _, sparkjar := range app.Spec.Deps.Jars {
-			sparkjar = AddEscapeCharacter(sparkjar)
-			modifiedJarList = append(modifiedJarList, sparkjar)
+
+// getMonitoringConfigFiles returns the metrics.properties and prometheus.yaml contents to ship in the driver
+// ConfigMap, falling back to the Spark operator defaults
+func getMonitoringConfigFiles(app *v1beta2.SparkApplication) map[string]string {
+	files := make(map[string]string)
+	if common.HasMetricsPropertiesContent(app) {
+		files[common.MetricsPropertiesFileName] = sparkcommon.DefaultMetricsProperties
+		if app.Spec.Monitoring.MetricsProperties != nil {
+			files[common.MetricsPropertiesFileName] = *app.Spec.Monitoring.MetricsProperties
+		}
+	}
+	if common.HasPrometheusConfigContent(app) {
+		files[common.PrometheusConfigFileName] = sparkcommon.DefaultPrometheusConfiguration
+		if app.Spec.Monitoring.Prometheus.Configuration != nil {
+			files[common.PrometheusConfigFileName] = *app.Spec.Monitoring.Prometheus.Configuration
 		}
-		args = args + SparkJars + EqualsSign + strings.Join(modifiedJarList, CommaSeparator) + NewLineString
+	}
+	return files
+}
+
+// getJavaOptions appends the Prometheus JMX exporter java agent to the JVM options when metrics are exposed
+func getJavaOptions(app *v1beta2.SparkApplication, javaOptions *string, exposeMetrics bool) string {
+	options := ""
+	if javaOptions != nil {
+		options = *javaOptions
+	}
+	if !exposeMetrics {
+		return options
+	}
+
+	prometheus := app.Spec.Monitoring.Prometheus
+	configFile := path.Join(common.SparkConfDirPath, common.PrometheusConfigFileName)
+	if prometheus.ConfigFile != nil {
+		configFile = *prometheus.ConfigFile
+	}
+	javaAgent := fmt.Sprintf("-javaagent:%s=%d:%s", prometheus.JmxExporterJar, common.GetPrometheusPort(app), configFile)
+	if options == "" {
+		return javaAgent
+	}
+	return options + " " + javaAgent
+}
+
+func populateArtifacts(section propertiesSection, app v1beta2.SparkApplication) {
+	if len(app.Spec.Deps.Jars) > 0 {
+		section.set(SparkJars, strings.Join(app.Spec.Deps.Jars, CommaSeparator))
 	}
 	if len(app.Spec.Deps.Files) > 0 {
-		args = args + SparkFiles + EqualsSign + strings.Join(app.Spec.Deps.Files, CommaSeparator) + NewLineString
+		section.set(SparkFiles, strings.Join(app.Spec.Deps.Files, CommaSeparator))
 	}
 	if len(app.Spec.Deps.PyFiles) > 0 {
-		args = args + SparkPyFiles + EqualsSign + strings.Join(app.Spec.Deps.PyFiles, CommaSeparator) + NewLineString
+		section.set(SparkPyFiles, strings.Join(app.Spec.Deps.PyFiles, CommaSeparator))
 	}
 	if len(app.Spec.Deps.Packages) > 0 {
-		args = args + SparkPackages + EqualsSign + strings.Join(app.Spec.Deps.Packages, CommaSeparator) + NewLineString
+		section.set(SparkPackages, strings.Join(app.Spec.Deps.Packages, CommaSeparator))
 	}
 	if len(app.Spec.Deps.ExcludePackages) > 0 {
-		args = args + SparkExcludePackages + EqualsSign + strings.Join(app.Spec.Deps.ExcludePackages, CommaSeparator) + NewLineString
+		section.set(SparkExcludePackages, strings.Join(app.Spec.Deps.ExcludePackages, CommaSeparator))
 	}
 	if len(app.Spec.Deps.Repositories) > 0 {
-		args = args + SparkRepositories + EqualsSign + strings.Join(app.Spec.Deps.Repositories, CommaSeparator) + NewLineString
+		section.set(SparkRepositories, strings.Join(app.Spec.Deps.Repositories, CommaSeparator))
 	}
-	return args
 }
-func populateContainerImageDetails(app v1beta2.SparkApplication) string {
-	args := ""
+func populateContainerImageDetails(section propertiesSection, app v1beta2.SparkApplication, config resolver.Config) {
 	if app.Spec.Image != nil {
-		sparkContainerImage := AddEscapeCharacter(*app.Spec.Image)
-		args = args + fmt.Sprintf("%s=%s", SparkContainerImageKey, sparkContainerImage) + NewLineString
-	}
-	if app.Spec.ImagePullPolicy != nil {
-		args = args + fmt.Sprintf("%s=%s", SparkContainerImagePullPolicyKey, *app.Spec.ImagePullPolicy) + NewLineString
+		section.set(SparkContainerImageKey, *app.Spec.Image)
 	}
+	section.set(SparkContainerImagePullPolicyKey, config.ImagePullPolicy.Value)
 	if len(app.Spec.ImagePullSecrets) > 0 {
 		secretNames := strings.Join(app.Spec.ImagePullSecrets, CommaSeparator)
-		args = args + fmt.Sprintf("%s=%s", SparkImagePullSecretKey, secretNames) + NewLineString
+		section.set(SparkImagePullSecretKey, secretNames)
 	}
-	return args
 }
-func populateComputeInfo(sb *strings.Builder, app v1beta2.SparkApplication, sparkConfKeyValuePairs map[string]string) (*strings.Builder, error) {
-	if app.Spec.Driver.Cores != nil {
-		driverCores := *app.Spec.Driver.Cores
-		sb.WriteString(fmt.Sprintf("%s=%d", SparkDriverCores, driverCores))
-		sb.WriteString(NewLineString)
-	} else if common.CheckSparkConf(sparkConfKeyValuePairs, SparkDriverCores) {
-		driverCores, err := strconv.ParseInt(sparkConfKeyValuePairs[SparkDriverCores], 10, 32)
+
+// populateComputeInfo writes the resolved cores, core requests and limits of the driver and executors
+func populateComputeInfo(section propertiesSection, config resolver.Config) error {
+	// Properties "spark.driver.cores" and "spark.executor.cores" do not allow float values
+	for _, cores := range []struct {
+		key     string
+		setting resolver.Setting
+	}{{SparkDriverCores, config.Driver.Cores}, {SparkExecutorCoreKey, config.Executor.Cores}} {
+		value, err := strconv.ParseInt(strings.TrimSpace(cores.setting.Value), 10, 32)
 		if err != nil {
-			return sb, err
+			return fmt.Errorf("%s must be an integer, got %q in %s", cores.key, cores.setting.Value, cores.setting.Describe())
 		}
-		sb.WriteString(fmt.Sprintf("%s=%d", SparkDriverCores, driverCores))
-		sb.WriteString(NewLineString)
-	} else { // Driver default cores
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverCores, DriverDefaultCores))
-		sb.WriteString(NewLineString)
-	}
-
-	if app.Spec.Driver.CoreRequest != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverCoreRequestKey, *app.Spec.Driver.CoreRequest))
-		sb.WriteString(NewLineString)
-	} else if common.CheckSparkConf(sparkConfKeyValuePairs, SparkDriverCoreRequestKey) {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverCoreRequestKey, sparkConfKeyValuePairs[SparkDriverCoreRequestKey]))
-		sb.WriteString(NewLineString)
+		section.set(cores.key, fmt.Sprint(value))
 	}
 
-	if app.Spec.Driver.CoreLimit != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverCoreLimitKey, *app.Spec.Driver.CoreLimit))
-		sb.WriteString(NewLineString)
-	} else if common.CheckSparkConf(sparkConfKeyValuePairs, SparkDriverCoreLimitKey) {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkDriverCoreLimitKey, sparkConfKeyValuePairs[SparkDriverCoreLimitKey]))
-		sb.WriteString(NewLineString)
-	}
-
-	if app.Spec.Executor.CoreRequest != nil {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkExecutorCoreRequestKey, *app.Spec.Executor.CoreRequest))
-		sb.WriteString(NewLineString)
-	} else if common.CheckSparkConf(sparkConfKeyValuePairs, SparkExecutorCoreRequestKey) {
-		sb.WriteString(fmt.Sprintf("%s=%s", SparkExecutorCoreRequestKey, sparkConfKeyValuePairs[SparkExecutorCoreRequestKey]))
-		sb.WriteString(NewLineString)
-	}
-	return sb, nil
+	populateSetting(section, SparkDriverCoreRequestKey, config.Driver.CoreRequest)
+	populateSetting(section, SparkDriverCoreLimitKey, config.Driver.CoreLimit)
+	populateSetting(section, SparkExecutorCoreRequestKey, config.Executor.CoreRequest)
+	populateSetting(section, SparkExecutorCoreLimitKey, config.Executor.CoreLimit)
+	return nil
 }
-func populateMemoryInfo(app v1beta2.SparkApplication, sparkConfKeyValuePairs map[string]string) string {
-	args := ""
-	if app.Spec.Driver.Memory != nil {
-		args = args + fmt.Sprintf("spark.driver.memory=%s", *app.Spec.Driver.Memory) + NewLineString
-	} else { //Driver default memory
-		args = args + fmt.Sprintf("spark.driver.memory=%s", DriverDefaultMemory) + NewLineString
-	}
-
-	if app.Spec.Driver.MemoryOverhead != nil {
-		args = args + fmt.Sprintf("spark.driver.memoryOverhead=%v", *app.Spec.Driver.MemoryOverhead) + NewLineString
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.driver.memoryOverhead") {
-		memoryOverhead := app.Spec.SparkConf["spark.driver.memoryOverhead"]
-		args = args + fmt.Sprintf("spark.driver.memoryOverhead=%v", memoryOverhead) + NewLineString
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.memoryOverhead") {
-		memoryOverhead := app.Spec.SparkConf["spark.kubernetes.memoryOverhead"]
-		args = args + fmt.Sprintf("spark.driver.memoryOverhead=%v", memoryOverhead) + NewLineString
-	}
 
-	// Property "spark.executor.cores" does not allow float values.
-	if app.Spec.Executor.Cores != nil {
-		args = args + fmt.Sprintf("%s=%d", SparkExecutorCoreKey, *app.Spec.Executor.Cores) + NewLineString
-	} else if common.CheckSparkConf(sparkConfKeyValuePairs, SparkExecutorCoreKey) {
-		val, _ := strconv.ParseInt(sparkConfKeyValuePairs[SparkExecutorCoreKey], 10, 32)
-		args = args + fmt.Sprintf("%s=%d", SparkExecutorCoreKey, val) + NewLineString
-	}
-
-	if app.Spec.Executor.CoreLimit != nil {
-		args = args + fmt.Sprintf("%s=%s", SparkExecutorCoreLimitKey, *app.Spec.Executor.CoreLimit) + NewLineString
-	} else if common.CheckSparkConf(sparkConfKeyValuePairs, SparkExecutorCoreLimitKey) {
-		args = args + fmt.Sprintf("%s=%s", SparkExecutorCoreLimitKey, sparkConfKeyValuePairs[SparkExecutorCoreLimitKey]) + NewLineString
-	}
+// populateMemoryInfo writes the resolved memory and memory overhead of the driver and executors
+func populateMemoryInfo(section propertiesSection, config resolver.Config) {
+	populateSetting(section, SparkDriverMemory, config.Driver.Memory)
+	populateSetting(section, SparkDriverMemoryOverhead, config.Driver.MemoryOverhead)
+	populateSetting(section, SparkExecutorMemory, config.Executor.Memory)
+	populateSetting(section, SparkExecutorMemoryOverhead, config.Executor.MemoryOverhead)
+}
 
-	if app.Spec.Executor.Memory != nil {
-		args = args + fmt.Sprintf("spark.executor.memory=%s", *app.Spec.Executor.Memory) + NewLineString
-	} else { //Setting default 1g
-		//Executor default memory
-		args = args + fmt.Sprintf("spark.executor.memory=%s", ExecutorDefaultMemory) + NewLineString
+// populateSetting writes a resolved setting, unless it has no value
+func populateSetting(section propertiesSection, key string, setting resolver.Setting) {
+	if setting.IsSet() {
+		section.set(key, setting.Value)
 	}
+}
 
-	if app.Spec.Executor.MemoryOverhead != nil {
-		args = args + fmt.Sprintf("spark.executor.memoryOverhead=%v", *app.Spec.Executor.MemoryOverhead) + NewLineString
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.executor.memoryOverhead") {
-		memoryOverhead := app.Spec.SparkConf["spark.executor.memoryOverhead"]
-		args = args + fmt.Sprintf("spark.executor.memoryOverhead=%v", memoryOverhead) + NewLineString
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.memoryOverhead") {
-		memoryOverhead := app.Spec.SparkConf["spark.kubernetes.memoryOverhead"]
-		args = args + fmt.Sprintf("spark.executor.memoryOverhead=%v", memoryOverhead) + NewLineString
+// populateNodeSelector writes a resolved node selector
+func populateNodeSelector(section propertiesSection, keyPrefix string, nodeSelector map[string]resolver.Setting) {
+	for label, setting := range nodeSelector {
+		section.set(keyPrefix+label, setting.Value)
 	}
-
-	return args
 }
@@ -0,0 +1,341 @@
+package configmap
+
+import (
+	"context"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	sparkcommon "github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/magiconair/properties"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPopulateSparkConfPropertiesCredentials(t *testing.T) {
+	section := newPropertiesSection(SparkConfSection)
+	populateSparkConfProperties(section, map[string]string{
+		"spark.kubernetes.authenticate.driver.oauthToken":             "secret-token",
+		"spark.kubernetes.authenticate.driver.mounted.caCertFile":     "/mnt/certs/ca.crt",
+		"spark.kubernetes.authenticate.driver.mounted.oauthTokenFile": "/mnt/token/token",
+	})
+	args := section.String()
+
+	assert.NotContains(t, args, "secret-token")
+	assert.Contains(t, args, "spark.kubernetes.authenticate.driver.mounted.caCertFile=/mnt/certs/ca.crt\n")
+	// The inline token shipped in the credentials secret takes precedence
+	assert.Contains(t, args, "spark.kubernetes.authenticate.driver.mounted.oauthTokenFile=/mnt/secrets/spark-kubernetes-credentials/oauth-token\n")
+	assert.NotContains(t, args, "/mnt/token/token")
+}
+
+func TestPopulateSparkConfPropertiesLocalKeytab(t *testing.T) {
+	section := newPropertiesSection(SparkConfSection)
+	populateSparkConfProperties(section, map[string]string{
+		"spark.kerberos.keytab": "local:///opt/user.keytab",
+	})
+	args := section.String()
+
+	props, err := properties.LoadString(args)
+	require.NoError(t, err)
+	assert.Equal(t, "local:///opt/user.keytab", props.GetString("spark.kerberos.keytab", ""))
+	assert.NotContains(t, args, "mounted")
+}
+
+func TestGetSparkUIProxyBase(t *testing.T) {
+	tests := []struct {
+		name      string
+		urlFormat string
+		uiOptions *v1beta2.SparkUIConfiguration
+		want      string
+	}{
+		{name: "no ingress URL format", uiOptions: &v1beta2.SparkUIConfiguration{}, want: "/default/test-app"},
+		{name: "no Spark UI options", urlFormat: "example.com/{{$appName}}", want: "/default/test-app"},
+		{name: "ingress on the root path", urlFormat: "{{$appName}}.example.com", uiOptions: &v1beta2.SparkUIConfiguration{}},
+		{
+			name:      "ingress on a sub path",
+			urlFormat: "example.com/spark/{{$appNamespace}}/{{$appName}}/",
+			uiOptions: &v1beta2.SparkUIConfiguration{},
+			want:      "/spark/default/test-app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SPARK_UI_INGRESS_URL_FORMAT", tt.urlFormat)
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+				Spec:       v1beta2.SparkApplicationSpec{SparkUIOptions: tt.uiOptions},
+			}
+			got, err := getSparkUIProxyBase(app)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithoutKeys(t *testing.T) {
+	sparkConf := map[string]string{"spark.ui.proxyBase": "/user/value", "spark.executor.cores": "2"}
+	assert.Equal(t, map[string]string{"spark.executor.cores": "2"}, withoutKeys(sparkConf, "spark.ui.proxyBase", "spark.ui.proxyRedirectUri"))
+	assert.Len(t, sparkConf, 2, "the sparkConf of the application is not modified")
+}
+
+func TestGetMonitoringConfigFiles(t *testing.T) {
+	customProperties := "*.sink.prometheusServlet.class=org.apache.spark.metrics.sink.PrometheusServlet"
+	customConfiguration := "lowercaseOutputName: true"
+	imageFile := "/etc/metrics/custom"
+	tests := []struct {
+		name       string
+		monitoring *v1beta2.MonitoringSpec
+		want       map[string]string
+	}{
+		{name: "no monitoring", want: map[string]string{}},
+		{name: "metrics without prometheus", monitoring: &v1beta2.MonitoringSpec{MetricsProperties: &customProperties}, want: map[string]string{}},
+		{
+			name:       "prometheus defaults",
+			monitoring: &v1beta2.MonitoringSpec{Prometheus: &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar"}},
+			want: map[string]string{
+				"metrics.properties": sparkcommon.DefaultMetricsProperties,
+				"prometheus.yaml":    sparkcommon.DefaultPrometheusConfiguration,
+			},
+		},
+		{
+			name: "custom content",
+			monitoring: &v1beta2.MonitoringSpec{
+				MetricsProperties: &customProperties,
+				Prometheus:        &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar", Configuration: &customConfiguration},
+			},
+			want: map[string]string{"metrics.properties": customProperties, "prometheus.yaml": customConfiguration},
+		},
+		{
+			name: "files in the image",
+			monitoring: &v1beta2.MonitoringSpec{
+				MetricsPropertiesFile: &imageFile,
+				Prometheus:            &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar", ConfigFile: &imageFile},
+			},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Monitoring: tt.monitoring})
+			assert.Equal(t, tt.want, getMonitoringConfigFiles(&app))
+		})
+	}
+}
+
+func TestGetJavaOptions(t *testing.T) {
+	port := int32(9100)
+	configFile := "/etc/metrics/prometheus.yaml"
+	userOptions := "-XX:+UseG1GC"
+	tests := []struct {
+		name          string
+		prometheus    *v1beta2.PrometheusSpec
+		javaOptions   *string
+		exposeMetrics bool
+		want          string
+	}{
+		{name: "nothing configured"},
+		{name: "metrics not exposed", prometheus: &v1beta2.PrometheusSpec{JmxExporterJar: "/jmx.jar"}, javaOptions: &userOptions, want: userOptions},
+		{
+			name:          "default port and shipped configuration",
+			prometheus:    &v1beta2.PrometheusSpec{JmxExporterJar: "/jmx.jar"},
+			exposeMetrics: true,
+			want:          "-javaagent:/jmx.jar=8090:/opt/spark/conf/prometheus.yaml",
+		},
+		{
+			name:          "appended to user options",
+			prometheus:    &v1beta2.PrometheusSpec{JmxExporterJar: "/jmx.jar", Port: &port, ConfigFile: &configFile},
+			javaOptions:   &userOptions,
+			exposeMetrics: true,
+			want:          "-XX:+UseG1GC -javaagent:/jmx.jar=9100:/etc/metrics/prometheus.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Monitoring: &v1beta2.MonitoringSpec{Prometheus: tt.prometheus}})
+			assert.Equal(t, tt.want, getJavaOptions(&app, tt.javaOptions, tt.exposeMetrics))
+		})
+	}
+}
+
+func TestPopulateMonitoringInfo(t *testing.T) {
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Monitoring: &v1beta2.MonitoringSpec{
+		ExposeExecutorMetrics: true,
+		Prometheus:            &v1beta2.PrometheusSpec{JmxExporterJar: "/jmx.jar"},
+	}})
+	section := newPropertiesSection(ApplicationSection)
+	populateMonitoringInfo(section, app)
+	args := section.String()
+	assert.Contains(t, args, "spark.metrics.namespace=default.test-app\n")
+	assert.Contains(t, args, "spark.metrics.conf=/opt/spark/conf/metrics.properties\n")
+
+	app.Spec.Executor.Annotations = map[string]string{"prometheus.io/path": "/custom"}
+	section = newPropertiesSection(ExecutorSection)
+	populateExecutorAnnotations(section, app, resolver.Resolve(&app).Executor.Annotations)
+	annotations := section.String()
+	assert.Contains(t, annotations, "spark.kubernetes.executor.annotation.prometheus.io/scrape=true\n")
+	assert.Contains(t, annotations, "spark.kubernetes.executor.annotation.prometheus.io/port=8090\n")
+	assert.Contains(t, annotations, "spark.kubernetes.executor.annotation.prometheus.io/path=/custom\n")
+	assert.NotContains(t, annotations, "prometheus.io/path=/metrics")
+}
+
+func TestBuildAltSubmissionCommandArgsExecutorScheduling(t *testing.T) {
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.Executor.Labels = map[string]string{"team": "data"}
+	// As set by the batch scheduler in the resolved configuration
+	config := resolver.Resolve(&app)
+	config.Executor.SchedulerName = config.Executor.SchedulerName.Override("volcano", resolver.SourceBatchScheduler, "spec.batchScheduler")
+	config.Executor.Annotations = map[string]resolver.Setting{"scheduling.k8s.io/group-name": {Value: "spark-test-app-pg", Source: resolver.SourceBatchScheduler}}
+
+	args, err := buildAltSubmissionCommandArgs(&app, config, "test-app-driver", "submission-id", "spark-app-id", "test-app-driver-svc")
+	require.NoError(t, err)
+	assert.Contains(t, args, "spark.kubernetes.executor.scheduler.name=volcano\n")
+	assert.Contains(t, args, "spark.kubernetes.executor.annotation.scheduling.k8s.io/group-name=spark-test-app-pg\n")
+	assert.Contains(t, args, "spark.kubernetes.executor.label.team=data\n")
+}
+
+func TestBuildAltSubmissionCommandArgsResolvedSettings(t *testing.T) {
+	executorImage := "spark:executor"
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.Type = v1beta2.SparkApplicationTypeScala
+	app.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+	app.Spec.Executor.Image = &executorImage
+	app.Spec.SparkConf = map[string]string{
+		"spark.driver.memory":                                               "4g",
+		"spark.kubernetes.memoryOverheadFactor":                             "0.2",
+		"spark.kubernetes.node.selector.disktype":                           "hdd",
+		"spark.kubernetes.driver.node.selector.topology.kubernetes.io/zone": "zone-a",
+	}
+
+	args, err := buildAltSubmissionCommandArgs(&app, resolver.Resolve(&app), "test-app-driver", "submission-id", "spark-app-id", "test-app-driver-svc")
+	require.NoError(t, err)
+	// Later sections override earlier ones, as when Spark loads the file
+	props, err := properties.LoadString(args)
+	require.NoError(t, err)
+	assert.Equal(t, "4g", props.GetString("spark.driver.memory", ""))
+	assert.Equal(t, "1g", props.GetString("spark.executor.memory", ""))
+	assert.Equal(t, "0.2", props.GetString("spark.kubernetes.memoryOverheadFactor", ""))
+	assert.Equal(t, "1", props.GetString("spark.executor.cores", ""))
+	assert.Equal(t, "IfNotPresent", props.GetString("spark.kubernetes.container.image.pullPolicy", ""))
+	assert.Equal(t, "spark:executor", props.GetString("spark.kubernetes.executor.container.image", ""))
+	assert.Equal(t, "ssd", props.GetString("spark.kubernetes.driver.node.selector.disktype", ""))
+	assert.Equal(t, "zone-a", props.GetString("spark.kubernetes.driver.node.selector.topology.kubernetes.io/zone", ""))
+	assert.Equal(t, "ssd", props.GetString("spark.kubernetes.executor.node.selector.disktype", ""))
+	assert.Empty(t, props.GetString("spark.kubernetes.executor.node.selector.topology.kubernetes.io/zone", ""))
+}
+
+func TestBuildAltSubmissionCommandArgsSparkDefaults(t *testing.T) {
+	defer func(file string) { resolver.SparkDefaultsFile = file }(resolver.SparkDefaultsFile)
+	resolver.SparkDefaultsFile = "testdata/spark-defaults.conf"
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.SparkConf = map[string]string{"spark.driver.memory": "4g"}
+
+	args, err := buildAltSubmissionCommandArgs(&app, resolver.Resolve(&app), "test-app-driver", "submission-id", "spark-app-id", "test-app-driver-svc")
+	require.NoError(t, err)
+	props, err := properties.LoadString(args)
+	require.NoError(t, err)
+	// The application overrides spark-defaults.conf, which applies to what the application leaves unset
+	assert.Equal(t, "4g", props.GetString("spark.driver.memory", ""))
+	assert.Equal(t, "2g", props.GetString("spark.executor.memory", ""))
+	assert.Equal(t, "true", props.GetString("spark.eventLog.enabled", ""))
+	assert.Equal(t, "7200", props.GetString("spark.driver.blockManager.port", ""))
+	assert.Equal(t, "7078", props.GetString("spark.driver.port", ""))
+}
+
+func TestBuildAltSubmissionCommandArgsEscapesProperties(t *testing.T) {
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Labels = map[string]string{"example.com/team": "data eng"}
+	app.Spec.SparkConf = map[string]string{"spark.driver.extraJavaOptions": "-Dsep=a:b -Dmsg=héllo"}
+	app.Spec.Driver.Env = []apiv1.EnvVar{
+		{Name: "GREETING", Value: "key=value"},
+		{Name: "FROM_SECRET", ValueFrom: &apiv1.EnvVarSource{}},
+	}
+
+	args, err := buildAltSubmissionCommandArgs(&app, resolver.Resolve(&app), "test-app-driver", "submission-id", "spark-app-id", "test-app-driver-svc")
+	require.NoError(t, err)
+	props, err := properties.LoadString(args)
+	require.NoError(t, err)
+	assert.Equal(t, "k8s://https://localhost:443", props.GetString("spark.master", ""))
+	assert.Equal(t, "data eng", props.GetString("spark.kubernetes.driver.label.example.com/team", ""))
+	assert.Equal(t, "-Dsep=a:b -Dmsg=héllo", props.GetString("spark.driver.extraJavaOptions", ""))
+	assert.Equal(t, "key=value", props.GetString("spark.kubernetes.driverEnv.GREETING", ""))
+	_, ok := props.Get("spark.kubernetes.driverEnv.FROM_SECRET")
+	assert.False(t, ok)
+}
+
+func TestBuildAltSubmissionCommandArgsDeterministic(t *testing.T) {
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Labels = map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"}
+	app.Spec.SparkConf = map[string]string{"spark.z": "1", "spark.y": "2", "spark.x": "3", "spark.w": "4"}
+	app.Spec.HadoopConf = map[string]string{"fs.b": "1", "fs.a": "2"}
+	app.Spec.Executor.EnvVars = map[string]string{"B": "1", "A": "2", "C": "3"}
+	app.Spec.Arguments = []string{"--input", "s3a://bucket/input"}
+
+	args, err := buildAltSubmissionCommandArgs(&app, resolver.Resolve(&app), "test-app-driver", "submission-id", "spark-app-id", "test-app-driver-svc")
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := buildAltSubmissionCommandArgs(&app, resolver.Resolve(&app), "test-app-driver", "submission-id", "spark-app-id", "test-app-driver-svc")
+		require.NoError(t, err)
+		require.Equal(t, args, again)
+	}
+	assert.NotContains(t, args, "spark.app.submitTime")
+	assert.NotContains(t, args, "--input", "the arguments are passed to the driver container")
+	assert.Contains(t, args, "# spec.sparkConf\nspark.w=4\nspark.x=3\nspark.y=2\nspark.z=1\n")
+	assert.Contains(t, args, "# spec.hadoopConf\nspark.hadoop.HADOOP_CONF_DIR=/opt/hadoop/conf\nspark.hadoop.fs.a=2\nspark.hadoop.fs.b=1\n")
+}
+
+func TestGetContentHash(t *testing.T) {
+	data := map[string]string{"spark.properties": "spark.a=1\n", "spark-env.sh": "export A=1\n"}
+	hash := getContentHash(data)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, getContentHash(map[string]string{"spark-env.sh": "export A=1\n", "spark.properties": "spark.a=1\n"}))
+	assert.NotEqual(t, hash, getContentHash(map[string]string{"spark.properties": "spark.a=2\n", "spark-env.sh": "export A=1\n"}))
+	// Moving content from a value to its key changes the hash
+	assert.NotEqual(t, getContentHash(map[string]string{"ab": "c"}), getContentHash(map[string]string{"a": "bc"}))
+	// The application and submission IDs are not part of the content
+	assert.Equal(t, hash, getContentHash(map[string]string{
+		"spark.properties": "spark.a=1\nspark.app.id=uid-1\nspark.kubernetes.driver.label.sparkoperator.k8s.io/submission-id=one\n",
+		"spark-env.sh":     "export A=1\n",
+	}))
+}
+
+func TestCreateAnnotatesContentHash(t *testing.T) {
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	kubeClient := fake.NewSimpleClientset(&apiv1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+
+	hash, err := Create(&app, resolver.Resolve(&app), "submission-id", "spark-app-id", kubeClient, "test-app-driver-conf-map", "test-app-driver-svc")
+	require.NoError(t, err)
+	configMap, err := kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), "test-app-driver-conf-map", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, hash, configMap.Annotations["sparkoperator.k8s.io/config-hash"])
+	assert.Equal(t, getContentHash(configMap.Data), hash)
+
+	// Submitting the same application again, under a new submission ID and UID, gives the same hash
+	again, err := Create(&app, resolver.Resolve(&app), "other-submission-id", "other-spark-app-id", kubeClient, "test-app-driver-conf-map", "test-app-driver-svc")
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+	configMap, err = kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), "test-app-driver-conf-map", metav1.GetOptions{})
+	require.NoError(t, err)
+	properties := configMap.Data[SparkPropertiesFileName]
+	assert.Contains(t, properties, "spark.app.id=other-spark-app-id\n")
+	assert.Contains(t, properties, "spark.kubernetes.driver.label.sparkoperator.k8s.io/submission-id=other-submission-id\n")
+	assert.Contains(t, properties, "spark.kubernetes.executor.label.sparkoperator.k8s.io/submission-id=other-submission-id\n")
+
+	// Any other change of the content changes the hash
+	app.Spec.SparkConf = map[string]string{"spark.a": "1"}
+	changed, err := Create(&app, resolver.Resolve(&app), "other-submission-id", "other-spark-app-id", kubeClient, "test-app-driver-conf-map", "test-app-driver-svc")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+}
+
+func TestPopulateComputeInfoRejectsFractionalCores(t *testing.T) {
+	app := *testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.SparkConf = map[string]string{"spark.executor.cores": "1.5"}
+	err := populateComputeInfo(newPropertiesSection(ResourcesSection), resolver.Resolve(&app))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spark.executor.cores must be an integer")
+	assert.Contains(t, err.Error(), "spec.sparkConf[spark.executor.cores]")
+}
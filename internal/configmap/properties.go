@@ -0,0 +1,110 @@
+package configmap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// formatProperty formats a key=value line of the properties file, the key and value escaped the way
+// java.util.Properties.store does, so that Spark loads them back as is
+func formatProperty(key string, value string) string {
+	return escapeProperty(key, true) + EqualsSign + escapeProperty(value, false) + NewLineString
+}
+
+// escapeProperty escapes a property key or value like java.util.Properties.store: backslashes, the key and comment
+// separators and the whitespace control characters are escaped with a backslash, spaces only when leading or in a
+// key, and characters outside printable ASCII are written as UTF-16 \uXXXX escapes
+func escapeProperty(s string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case ' ':
+			if i == 0 || isKey {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(' ')
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				// Supplementary characters are written as their surrogate pair, as Java strings hold UTF-16
+				for _, unit := range utf16.Encode([]rune{r}) {
+					sb.WriteString(fmt.Sprintf(`\u%04X`, unit))
+				}
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// propertiesSection holds the properties of a section of the properties file, a key set twice keeps its last value
+type propertiesSection struct {
+	name       string
+	properties map[string]string
+}
+
+func newPropertiesSection(name string) propertiesSection {
+	return propertiesSection{name: name, properties: make(map[string]string)}
+}
+
+func (s propertiesSection) set(key string, value string) {
+	s.properties[key] = value
+}
+
+// String formats the properties of the section, sorted by key
+func (s propertiesSection) String() string {
+	keys := make([]string, 0, len(s.properties))
+	for key := range s.properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(formatProperty(key, s.properties[key]))
+	}
+	return sb.String()
+}
+
+// propertiesFile builds a properties file out of sections written in order, each under a comment naming it. As Spark
+// loads the file in order, a key of a later section overrides the same key of an earlier one
+type propertiesFile struct {
+	sections []propertiesSection
+}
+
+// section starts a new section of the file
+func (f *propertiesFile) section(name string) propertiesSection {
+	section := newPropertiesSection(name)
+	f.sections = append(f.sections, section)
+	return section
+}
+
+// String formats the non empty sections of the file, separated by a blank line
+func (f *propertiesFile) String() string {
+	var sb strings.Builder
+	for _, section := range f.sections {
+		if len(section.properties) == 0 {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(NewLineString)
+		}
+		sb.WriteString(CommentPrefix + section.name + NewLineString)
+		sb.WriteString(section.String())
+	}
+	return sb.String()
+}
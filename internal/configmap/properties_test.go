@@ -0,0 +1,99 @@
+package configmap
+
+import (
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+
+	"github.com/magiconair/properties"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatProperty(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  string
+	}{
+		{name: "plain", key: "spark.app.name", value: "test-app", want: "spark.app.name=test-app\n"},
+		{name: "separators", key: "a=b:c", value: "k8s://https://host:443", want: "a\\=b\\:c=k8s\\://https\\://host\\:443\n"},
+		{name: "comment characters", key: "#key", value: "!value#", want: "\\#key=\\!value\\#\n"},
+		{name: "spaces", key: "my key", value: " leading and inner ", want: "my\\ key=\\ leading and inner \n"},
+		{name: "backslashes", key: `C:\dir`, value: `\\share`, want: "C\\:\\\\dir=\\\\\\\\share\n"},
+		{name: "control characters", key: "key", value: "a\tb\nc\rd\fe\x01", want: "key=a\\tb\\nc\\rd\\fe\\u0001\n"},
+		{name: "non ASCII", key: "clé", value: "日本", want: "cl\\u00E9=\\u65E5\\u672C\n"},
+		{name: "supplementary character", key: "emoji", value: "😀", want: "emoji=\\uD83D\\uDE00\n"},
+		{name: "empty value", key: "key", value: "", want: "key=\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatProperty(tt.key, tt.value))
+		})
+	}
+}
+
+func TestFormatPropertyRoundTrip(t *testing.T) {
+	// magiconair/properties decodes each \uXXXX on its own, so the round trip is limited to the Basic Multilingual
+	// Plane, the surrogate pairs of supplementary characters are covered by TestFormatProperty
+	roundTrip := func(key string, value string) bool {
+		key, value = toBMP(key), toBMP(value)
+		if key == "" {
+			return true
+		}
+		props, err := properties.LoadString(formatProperty(key, value))
+		if err != nil {
+			t.Logf("failed to load %q=%q: %v", key, value, err)
+			return false
+		}
+		got, ok := props.Get(key)
+		return ok && got == value && props.Len() == 1
+	}
+	require.NoError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 2000}))
+
+	for _, kv := range [][2]string{
+		{"spark.master", "k8s://https://10.0.0.1:443"},
+		{"spark.kubernetes.driver.label.app.kubernetes.io/name", "my app"},
+		{"spark.driver.extraJavaOptions", "-Dkey=value -Dother=\"a b\""},
+		{" ", " "},
+		{"key\\", "value\\"},
+		{"multi\nline", "first\nsecond\r\n"},
+	} {
+		props, err := properties.LoadString(formatProperty(kv[0], kv[1]))
+		require.NoError(t, err)
+		got, ok := props.Get(kv[0])
+		assert.True(t, ok, kv[0])
+		assert.Equal(t, kv[1], got)
+	}
+}
+
+// toBMP replaces the invalid UTF-8 and the characters outside the Basic Multilingual Plane
+func toBMP(s string) string {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		if r > 0xFFFF || (r >= 0xD800 && r <= 0xDFFF) {
+			r = utf8.RuneError
+		}
+		runes = append(runes, r)
+	}
+	return string(runes)
+}
+
+func TestPropertiesFile(t *testing.T) {
+	var file propertiesFile
+	defaults := file.section("defaults")
+	defaults.set("spark.b", "1")
+	defaults.set("spark.a", "1")
+	file.section("empty")
+	overrides := file.section("overrides")
+	overrides.set("spark.b", "2")
+	overrides.set("spark.c", "first")
+	overrides.set("spark.c", "last")
+
+	assert.Equal(t, "# defaults\nspark.a=1\nspark.b=1\n\n# overrides\nspark.b=2\nspark.c=last\n", file.String())
+	props, err := properties.LoadString(file.String())
+	require.NoError(t, err)
+	assert.Equal(t, "2", props.GetString("spark.b", ""))
+	assert.Equal(t, "last", props.GetString("spark.c", ""))
+}
@@ -2,10 +2,13 @@ package configmap
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"nativesubmit/common"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
@@ -21,8 +24,8 @@ const (
 	kubernetesServicePortEnvVar = "KUBERNETES_SERVICE_PORT"
 )
 
-// CreateConfigMapUtil Helper func to create Spark Application configmap
-func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, configMapData map[string]string, kubeClient *kubernetes.Clientset) error {
+// CreateConfigMapUtil Helper func to create Spark Application configmap, returns the hash of its content
+func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, configMapData map[string]string, kubeClient kubernetes.Interface) (string, error) {
 	log.Printf("=== Starting createConfigMapUtil ===")
 	log.Printf("ConfigMap name: %s, Namespace: %s", configMapName, app.Namespace)
 
@@ -30,7 +33,7 @@ func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, co
 	_, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), app.Namespace, metav1.GetOptions{})
 	if err != nil {
 		log.Printf("ERROR: Cannot access namespace %s: %v", app.Namespace, err)
-		return fmt.Errorf("cannot access namespace %s: %w", app.Namespace, err)
+		return "", fmt.Errorf("cannot access namespace %s: %w", app.Namespace, err)
 	}
 	log.Printf("Kubernetes client connectivity verified - can access namespace: %s", app.Namespace)
 
@@ -69,6 +72,10 @@ func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, co
 		}
 	}
 
+	// Hashed once the content is final, identical submissions get the same hash
+	contentHash := getContentHash(configMapData)
+	log.Printf("ConfigMap content hash: %s", contentHash)
+
 	ownerRef := common.GetConfigMapOwnerReference(app)
 
 	// Log ConfigMap creation details for debugging
@@ -85,6 +92,7 @@ func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, co
 			Name:            configMapName,
 			Namespace:       app.Namespace,
 			OwnerReferences: []metav1.OwnerReference{*ownerRef},
+			Annotations:     map[string]string{common.ConfigHashAnnotation: contentHash},
 		},
 		Data: configMapData,
 	}
@@ -117,6 +125,10 @@ func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, co
 		}
 		log.Printf("ConfigMap exists, updating...")
 		cm.Data = configMapData
+		if cm.Annotations == nil {
+			cm.Annotations = make(map[string]string)
+		}
+		cm.Annotations[common.ConfigHashAnnotation] = contentHash
 		_, updateErr := kubeClient.CoreV1().ConfigMaps(app.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
 		if updateErr != nil {
 			log.Printf("ERROR: Failed to update ConfigMap: %v", updateErr)
@@ -132,12 +144,47 @@ func createConfigMapUtil(configMapName string, app *v1beta2.SparkApplication, co
 		log.Printf("=== Successfully completed createConfigMapUtil ===")
 	}
 
-	return createConfigMapErr
+	return contentHash, createConfigMapErr
 }
-func AddEscapeCharacter(configMapArg string) string {
-	configMapArg = strings.ReplaceAll(configMapArg, ":", "\\:")
-	configMapArg = strings.ReplaceAll(configMapArg, "=", "\\=")
-	return configMapArg
+
+// getContentHash returns the hex encoded sha256 of the ConfigMap data, hashed in key order. The properties identifying
+// the submission are left out, so that resubmitting the same application gives the same hash.
+func getContentHash(configMapData map[string]string) string {
+	keys := make([]string, 0, len(configMapData))
+	for key := range configMapData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		value := configMapData[key]
+		if key == SparkPropertiesFileName {
+			value = withoutSubmissionProperties(value)
+		}
+		// The lengths keep the boundaries between keys and values unambiguous
+		fmt.Fprintf(hash, "%d:%s%d:%s", len(key), key, len(value), value)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// withoutSubmissionProperties drops the application ID and the submission ID labels from a properties file. Values
+// are escaped on a single line, so each property is a line starting with its escaped key.
+func withoutSubmissionProperties(properties string) string {
+	var prefixes []string
+	for _, key := range []string{SparkAppId, SparkDriverLabelKeyPrefix + SubmissionIDLabel, SparkExecutorLabelKeyPrefix + SubmissionIDLabel} {
+		prefixes = append(prefixes, escapeProperty(key, true)+EqualsSign)
+	}
+	var sb strings.Builder
+lines:
+	for _, line := range strings.SplitAfter(properties, NewLineString) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(line, prefix) {
+				continue lines
+			}
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
 }
 func getMasterURL() (string, error) {
 	kubernetesServiceHost := os.Getenv(kubernetesServiceHostEnvVar)
@@ -152,10 +199,8 @@ func getMasterURL() (string, error) {
 	return fmt.Sprintf("k8s://https://%s:%s", kubernetesServiceHost, kubernetesServicePort), nil
 }
 
-// addLocalDirConfOptions excludes local dir volumes, update SparkApplication and returns local dir config options
-func addLocalDirConfOptions(app *v1beta2.SparkApplication) ([]string, error) {
-	var localDirConfOptions []string
-
+// addLocalDirConfOptions excludes local dir volumes, update SparkApplication and writes the local dir config options
+func addLocalDirConfOptions(section propertiesSection, app *v1beta2.SparkApplication) error {
 	sparkLocalVolumes := map[string]apiv1.Volume{}
 	var mutateVolumes []apiv1.Volume
 
@@ -171,52 +216,49 @@ func addLocalDirConfOptions(app *v1beta2.SparkApplication) ([]string, error) {
 
 	// Filter local dir volumeMounts and set mutate volume mounts to driver and executor
 	if app.Spec.Driver.VolumeMounts != nil {
-		driverMutateVolumeMounts, driverLocalDirConfConfOptions := filterMutateMountVolumes(app.Spec.Driver.VolumeMounts, SparkDriverVolumesPrefix, sparkLocalVolumes)
-		app.Spec.Driver.VolumeMounts = driverMutateVolumeMounts
-		localDirConfOptions = append(localDirConfOptions, driverLocalDirConfConfOptions...)
+		app.Spec.Driver.VolumeMounts = filterMutateMountVolumes(section, app.Spec.Driver.VolumeMounts, SparkDriverVolumesPrefix, sparkLocalVolumes)
 	}
 
 	if app.Spec.Executor.VolumeMounts != nil {
-		executorMutateVolumeMounts, executorLocalDirConfConfOptions := filterMutateMountVolumes(app.Spec.Executor.VolumeMounts, SparkExecutorVolumesPrefix, sparkLocalVolumes)
-		app.Spec.Executor.VolumeMounts = executorMutateVolumeMounts
-		localDirConfOptions = append(localDirConfOptions, executorLocalDirConfConfOptions...)
+		app.Spec.Executor.VolumeMounts = filterMutateMountVolumes(section, app.Spec.Executor.VolumeMounts, SparkExecutorVolumesPrefix, sparkLocalVolumes)
 	}
 
-	return localDirConfOptions, nil
+	return nil
 }
 
-func filterMutateMountVolumes(volumeMounts []apiv1.VolumeMount, prefix string, sparkLocalVolumes map[string]apiv1.Volume) ([]apiv1.VolumeMount, []string) {
+func filterMutateMountVolumes(section propertiesSection, volumeMounts []apiv1.VolumeMount, prefix string, sparkLocalVolumes map[string]apiv1.Volume) []apiv1.VolumeMount {
 	var mutateMountVolumes []apiv1.VolumeMount
-	var localDirConfOptions []string
 	for _, volumeMount := range volumeMounts {
 		if volume, ok := sparkLocalVolumes[volumeMount.Name]; ok {
-			options := buildLocalVolumeOptions(prefix, volume, volumeMount)
-			localDirConfOptions = append(localDirConfOptions, options...)
+			buildLocalVolumeOptions(section, prefix, volume, volumeMount)
 		} else {
 			mutateMountVolumes = append(mutateMountVolumes, volumeMount)
 		}
 	}
 
-	return mutateMountVolumes, localDirConfOptions
+	return mutateMountVolumes
 }
-func buildLocalVolumeOptions(prefix string, volume apiv1.Volume, volumeMount apiv1.VolumeMount) []string {
-	VolumeMountPathTemplate := prefix + "%s.%s.mount.path=%s"
-	VolumeMountOptionTemplate := prefix + "%s.%s.options.%s=%s"
 
-	var options []string
+// buildLocalVolumeOptions writes the properties mounting a local dir volume
+func buildLocalVolumeOptions(section propertiesSection, prefix string, volume apiv1.Volume, volumeMount apiv1.VolumeMount) {
+	mountPathKey := func(volumeType string) string {
+		return fmt.Sprintf("%s%s.%s.mount.path", prefix, volumeType, volume.Name)
+	}
+	optionKey := func(volumeType string, option string) string {
+		return fmt.Sprintf("%s%s.%s.options.%s", prefix, volumeType, volume.Name, option)
+	}
+
 	switch {
 	case volume.HostPath != nil:
-		options = append(options, fmt.Sprintf(VolumeMountPathTemplate, "hostPath", volume.Name, volumeMount.MountPath))
-		options = append(options, fmt.Sprintf(VolumeMountOptionTemplate, "hostPath", volume.Name, "path", volume.HostPath.Path))
+		section.set(mountPathKey("hostPath"), volumeMount.MountPath)
+		section.set(optionKey("hostPath", "path"), volume.HostPath.Path)
 		if volume.HostPath.Type != nil {
-			options = append(options, fmt.Sprintf(VolumeMountOptionTemplate, "hostPath", volume.Name, "type", *volume.HostPath.Type))
+			section.set(optionKey("hostPath", "type"), string(*volume.HostPath.Type))
 		}
 	case volume.EmptyDir != nil:
-		options = append(options, fmt.Sprintf(VolumeMountPathTemplate, "emptyDir", volume.Name, volumeMount.MountPath))
+		section.set(mountPathKey("emptyDir"), volumeMount.MountPath)
 	case volume.PersistentVolumeClaim != nil:
-		options = append(options, fmt.Sprintf(VolumeMountPathTemplate, "persistentVolumeClaim", volume.Name, volumeMount.MountPath))
-		options = append(options, fmt.Sprintf(VolumeMountOptionTemplate, "persistentVolumeClaim", volume.Name, "claimName", volume.PersistentVolumeClaim.ClaimName))
+		section.set(mountPathKey("persistentVolumeClaim"), volumeMount.MountPath)
+		section.set(optionKey("persistentVolumeClaim", "claimName"), volume.PersistentVolumeClaim.ClaimName)
 	}
-
-	return options
 }
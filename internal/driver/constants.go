@@ -1,25 +1,20 @@
 package driver
 
+import "time"
+
 const (
 	SparkDriverCores          = "spark.driver.cores"
-	SparkDriverMemory         = "spark.driver.memory"
 	ForwardSlash              = "/"
-	KerberosPath              = "spark.kubernetes.kerberos.krb5.path"
-	KerberosConfigMapName     = "spark.kubernetes.kerberos.krb5.configMapName"
 	KerberosFileVolume        = "krb5-file"
 	KerberosFileDirectoryPath = "/etc"
 	KerberosFileName          = "krb5.conf"
 
-	SparkDriverEnvPrefix             = "spark.kubernetes.driverEnv"
-	All                              = "ALL"
-	SparkUserId                      = "185"
-	SparkBlockManagerPort            = "spark.blockManager.port"
-	SparkDriverBlockManagerPort      = "spark.driver.blockManager.port"
-	DotSeparator                     = "."
-	SparkDriverDNSPolicy             = "ClusterFirst"
-	SparkNodeSelectorPrefix          = "spark.kubernetes.node.selector."
-	SparkDriverPodNodeSelectorPrefix = "spark.kubernetes.driver.node.selector."
-	DriverPodRestartPolicyNever      = "Never"
+	SparkDriverEnvPrefix        = "spark.kubernetes.driverEnv"
+	All                         = "ALL"
+	SparkUserId                 = "185"
+	DotSeparator                = "."
+	SparkDriverDNSPolicy        = "ClusterFirst"
+	DriverPodRestartPolicyNever = "Never"
 	// LabelAnnotationPrefix is the prefix of every labels and annotations added by the controller.
 	LabelAnnotationPrefix = "sparkoperator.k8s.io/"
 	// SparkAppNameLabel is the name of the label for the SparkApplication object name.
@@ -52,18 +47,50 @@ const (
 	KerberosTokenSecretItemKey           = "spark.kubernetes.kerberos.tokenSecret.itemKey"
 	KerberosHadoopSecretFilePathKey      = "HADOOP_TOKEN_FILE_LOCATION"
 	KerberosHadoopSecretFilePath         = "/mnt/secrets/hadoop-credentials/"
-	ImagePullPolicyIfNotPresent          = "IfNotPresent"
 	DriverPortName                       = "driver-rpc-port"
 	BlockManagerPortName                 = "blockmanager"
 	Protocol                             = "TCP"
 	UiPortName                           = "spark-ui"
-	UiPort                               = 4040
 	DriverPodTerminationLogPath          = "/dev/termination-log"
 	DriverPodTerminationMessagePolicy    = "File"
-	OAuthTokenConfFile                   = "spark.kubernetes.authenticate.driver.oauthTokenFile"
-	ClientKeyFile                        = "spark.kubernetes.authenticate.driver.clientKeyFile"
-	ClientCertFile                       = "spark.kubernetes.authenticate.driver.clientCertFile"
-	CaCertFile                           = "spark.kubernetes.authenticate.driver.caCertFile"
 	KubernetesCredentials                = "kubernetes-credentials"
 	KubernetesCredentialsVolumeMountPath = "/mnt/secrets/spark-kubernetes-credentials"
+	KubernetesCredentialsSecretExtension = "-kubernetes-credentials"
+	KerberosTokenSecretName              = "spark.kubernetes.kerberos.tokenSecret.name"
+	HadoopSecretVolume                   = "hadoop-secret"
+	ConfigMapVolumeExtension             = "-vol"
+	// SecretKeyRefOptional marks a spark.kubernetes.driver.secretKeyRef.[EnvName]=[SecretName]:[Key]:optional
+	// reference whose secret or key may be missing.
+	SecretKeyRefOptional = "optional"
+	// ConfigMapScheme is the URI scheme of pod templates read from a ConfigMap key, configmap://namespace/name/key.
+	ConfigMapScheme = "configmap"
+	// PodTemplateCacheTTLEnvVar is the environment variable for how long a downloaded pod template is reused, 0
+	// disables the cache.
+	PodTemplateCacheTTLEnvVar  = "POD_TEMPLATE_CACHE_TTL"
+	DefaultPodTemplateCacheTTL = 5 * time.Minute
+	// PodTemplateCacheMaxEntries bounds the number of pod templates kept in memory.
+	PodTemplateCacheMaxEntries = 100
+	S3Endpoint                 = "spark.hadoop.fs.s3a.endpoint"
+	S3Region                   = "spark.hadoop.fs.s3a.endpoint.region"
+	S3AccessKey                = "spark.hadoop.fs.s3a.access.key"
+	S3SecretKey                = "spark.hadoop.fs.s3a.secret.key"
+	S3SessionToken             = "spark.hadoop.fs.s3a.session.token"
+	S3PathStyleAccess          = "spark.hadoop.fs.s3a.path.style.access"
+	DefaultS3Region            = "us-east-1"
+	SparkFilesFetchTimeout     = "spark.files.fetchTimeout"
+	// DefaultFetchTimeout is the default of spark.files.fetchTimeout in Spark.
+	DefaultFetchTimeout = 60 * time.Second
+	// PodTemplateAllowedHostsEnvVar is the environment variable listing the comma-separated hosts pod templates may be
+	// downloaded from, e.g. "templates.example.com,*.s3.amazonaws.com".
+	PodTemplateAllowedHostsEnvVar = "POD_TEMPLATE_ALLOWED_HOSTS"
+	// PodTemplateAllowedDirsEnvVar is the environment variable listing the comma-separated directories of the server
+	// local pod templates may be read from.
+	PodTemplateAllowedDirsEnvVar = "POD_TEMPLATE_ALLOWED_DIRS"
+	// PodTemplateMaxSizeEnvVar is the environment variable for the maximum size in bytes of a pod template.
+	PodTemplateMaxSizeEnvVar  = "POD_TEMPLATE_MAX_SIZE_BYTES"
+	DefaultPodTemplateMaxSize = 1024 * 1024
+	// PodTemplateS3ServerCredentialsEnvVar lets S3 pod templates of applications without s3a credentials be
+	// downloaded with the AWS_* credentials of the server when set to "true".
+	PodTemplateS3ServerCredentialsEnvVar = "POD_TEMPLATE_S3_USE_SERVER_CREDENTIALS"
+	MaxPodTemplateRedirects              = 3
 )
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math"
 	"nativesubmit/common"
+	"nativesubmit/internal/memory"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/scheduler"
+	"nativesubmit/internal/validation"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
@@ -20,7 +22,8 @@ import (
 )
 
 // Helper func to create Driver Pod of the Spark Application
-func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driverConfigMapName string, kubeClient *kubernetes.Clientset, appSpecVolumeMounts []apiv1.VolumeMount, appSpecVolumes []apiv1.Volume) (string, error) {
+// config is the resolved configuration of the submission, configHash the hash of the driver ConfigMap content annotated on the pod
+func Create(app *v1beta2.SparkApplication, config resolver.Config, serviceLabels map[string]string, driverConfigMapName string, configHash string, kubeClient kubernetes.Interface, appSpecVolumeMounts []apiv1.VolumeMount, appSpecVolumes []apiv1.Volume) (string, error) {
 	log.Printf("=== Starting Driver Pod creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
 	log.Printf("Driver ConfigMap name: %s", driverConfigMapName)
 	log.Printf("Service labels count: %d", len(serviceLabels))
@@ -30,14 +33,31 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 		return "", fmt.Errorf("spark application cannot be nil")
 	}
 
+	//User declared driver ports must not clash with the ports reserved by the driver
+	reservedPorts, err := getReservedDriverPorts(app, config.Driver)
+	if err != nil {
+		log.Printf("ERROR: Invalid driver ports: %v", err)
+		return "", fmt.Errorf("invalid ports for the driver pod %s in namespace %s: %w", common.GetDriverPodName(app), app.Namespace, err)
+	}
+	if err := validation.ValidatePorts(app, config).ToAggregate(); err != nil {
+		log.Printf("ERROR: Invalid driver ports: %v", err)
+		return "", fmt.Errorf("invalid ports for the driver pod %s in namespace %s: %w", common.GetDriverPodName(app), app.Namespace, err)
+	}
+
+	//Env vars referencing secret keys, validated before any resource is created
+	secretKeyRefEnvVars, err := getDriverSecretKeyRefEnvVars(app)
+	if err != nil {
+		log.Printf("ERROR: Invalid secretKeyRef env vars: %v", err)
+		return "", fmt.Errorf("invalid secretKeyRef env vars for the driver pod %s in namespace %s: %w", common.GetDriverPodName(app), app.Namespace, err)
+	}
+
 	//Load template file, if one supplied
 	var initialPod apiv1.Pod
-	var err error
 	driverPodtemplateFile, templateFileExists := app.Spec.SparkConf["spark.kubernetes.driver.podTemplateFile"]
 	if templateFileExists {
 		log.Printf("Pod template file specified: %s", driverPodtemplateFile)
 		podTemplateDriverContainerName := app.Spec.SparkConf["spark.kubernetes.driver.podTemplateContainerName"]
-		initialPod, err = loadPodFromTemplate(driverPodtemplateFile, podTemplateDriverContainerName, app.Spec.SparkConf)
+		initialPod, err = loadPodFromTemplate(driverPodtemplateFile, podTemplateDriverContainerName, app.Namespace, app.Spec.SparkConf, kubeClient)
 		if err != nil {
 			log.Printf("ERROR: Failed to load template file: %v", err)
 			return "", fmt.Errorf("failed to load template file for the driver pod %s in namespace %s: %v", common.GetDriverPodName(app), app.Namespace, err)
@@ -45,14 +65,8 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 		log.Printf("Successfully loaded pod template")
 	}
 
-	//Driver pod spec instance
+	//Driver pod spec instance, merged into the pod template once complete
 	var driverPodSpec apiv1.PodSpec
-	if templateFileExists {
-		driverPodSpec = initialPod.Spec
-		log.Printf("Using pod spec from template")
-	} else {
-		log.Printf("Using default pod spec")
-	}
 
 	// Spark Application Driver Pod schema populating with specific values/data
 	var podObjectMetadata metav1.ObjectMeta
@@ -62,24 +76,25 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 	podObjectMetadata.Namespace = common.GetAppNamespace(app)
 	//Driver Pod labels
 	podObjectMetadata.Labels = serviceLabels
-	//Driver pod annotations
-	if app.Spec.Driver.Annotations != nil {
-		podObjectMetadata.Annotations = app.Spec.Driver.Annotations
-		log.Printf("Using driver annotations from spec")
-	} else {
-		log.Printf("No driver annotations in spec, checking sparkConf")
-		annotations := make(map[string]string)
-		for sparkConfKey, sparkConfValue := range app.Spec.SparkConf {
-			if strings.Contains(sparkConfKey, "spark.kubernetes.driver.annotation.") {
-				lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
-				annotationKey := sparkConfKey[lastDotIndex+1:]
-				annotations[annotationKey] = sparkConfValue
-			}
+	//Driver pod annotations, as resolved with the spec annotations overriding the ones passed in sparkConf
+	if annotations := resolver.Values(config.Driver.Annotations); len(annotations) > 0 {
+		podObjectMetadata.Annotations = annotations
+		log.Printf("Added %d driver annotations", len(annotations))
+	}
+	//Prometheus scrape annotations when the driver runs the JMX exporter, unless overridden by the driver annotations
+	if common.ExposesDriverMetrics(app) {
+		annotations := common.GetPrometheusAnnotations(app)
+		for key, value := range podObjectMetadata.Annotations {
+			annotations[key] = value
 		}
-		if len(annotations) > 0 {
-			podObjectMetadata.Annotations = annotations
-			log.Printf("Added %d annotations from sparkConf", len(annotations))
+		podObjectMetadata.Annotations = annotations
+	}
+	//Hash of the driver ConfigMap content the pod starts with, to compare submissions
+	if configHash != "" {
+		if podObjectMetadata.Annotations == nil {
+			podObjectMetadata.Annotations = make(map[string]string)
 		}
+		podObjectMetadata.Annotations[common.ConfigHashAnnotation] = configHash
 	}
 	//Driver Pod Owner Reference
 	podObjectMetadata.OwnerReferences = []metav1.OwnerReference{*common.GetOwnerReference(app)}
@@ -91,34 +106,8 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 	//Driver pod enable service link
 	driverPodSpec.EnableServiceLinks = common.BoolPointer(true)
 
-	//Priority wise, values mentioned in Spec i.e. app.Spec.NodeSelector or/and app.Spec.Driver.NodeSelector will be higher than
-	// ones specified in sparkConf
-
-	if app.Spec.NodeSelector != nil {
-		driverPodSpec.NodeSelector = app.Spec.NodeSelector
-	} else if app.Spec.Driver.NodeSelector != nil {
-		driverPodSpec.NodeSelector = app.Spec.Driver.NodeSelector
-	} else {
-		nodeSelectorList := make(map[string]string)
-		//Driver pod node selector
-		for sparkConfKey, sparkConfValue := range app.Spec.SparkConf {
-			if strings.Contains(sparkConfKey, SparkNodeSelectorPrefix) {
-				lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
-				driverPodNodeSelectorKey := sparkConfKey[lastDotIndex+1:]
-				nodeSelectorList[driverPodNodeSelectorKey] = sparkConfValue
-			}
-		}
-		for sparkConfKey, sparkConfValue := range app.Spec.SparkConf {
-			if strings.Contains(sparkConfKey, SparkDriverPodNodeSelectorPrefix) {
-				lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
-				driverPodNodeSelectorKey := sparkConfKey[lastDotIndex+1:]
-				nodeSelectorList[driverPodNodeSelectorKey] = sparkConfValue
-			}
-		}
-		if len(nodeSelectorList) > 0 {
-			driverPodSpec.NodeSelector = nodeSelectorList
-		}
-	}
+	//Node selector, the driver ones overriding the application ones, the spec overriding sparkConf
+	driverPodSpec.NodeSelector = resolver.Values(config.Driver.NodeSelector)
 
 	//Image pull Secrets
 	var imagePullSecrets []string
@@ -153,7 +142,7 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 
 			driverPodSpec.SecurityContext = &podSecurityContext
 		}
-	} else {
+	} else if initialPod.Spec.SecurityContext == nil {
 		driverPodSpec.SecurityContext = &apiv1.PodSecurityContext{
 			RunAsUser: common.Int64Pointer(DriverPodSecurityContextID),
 			FSGroup:   common.Int64Pointer(DriverPodSecurityContextID),
@@ -165,30 +154,24 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 	//Service Account
 	log.Printf("Service account: %v", app.Spec.Driver.ServiceAccount)
 
-	if app.Spec.Driver.ServiceAccount != nil {
-		driverPodSpec.ServiceAccountName = *app.Spec.Driver.ServiceAccount
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.authenticate.driver.serviceAccountName") {
-		driverPodSpec.ServiceAccountName = app.Spec.SparkConf["spark.kubernetes.authenticate.driver.serviceAccountName"]
-	}
-	//Pod Scheduler Name
-	driverPodSchedulerName, driverPodSchedulerValueExists := app.Spec.SparkConf["spark.kubernetes.driver.scheduler.name"]
-	podSchedulerName, podSchedulerValueExists := app.Spec.SparkConf["spark.kubernetes.scheduler.name"]
-	if driverPodSchedulerValueExists {
-		driverPodSpec.SchedulerName = driverPodSchedulerName
-	} else if podSchedulerValueExists {
-		driverPodSpec.SchedulerName = podSchedulerName
+	driverPodSpec.ServiceAccountName = config.Driver.ServiceAccount.Value
+	//Pod Scheduler Name, from the batch scheduler, the spec or else sparkConf
+	driverPodSpec.SchedulerName = config.Driver.SchedulerName.Value
+	//Kueue admits the driver pod by removing its scheduling gate
+	if scheduler.IsKueue(app) {
+		driverPodSpec.SchedulingGates = append(driverPodSpec.SchedulingGates, apiv1.PodSchedulingGate{Name: scheduler.KueueAdmissionSchedulingGate})
 	}
 
 	//Termination grace period
 	if app.Spec.Driver.TerminationGracePeriodSeconds != nil {
 		driverPodSpec.TerminationGracePeriodSeconds = app.Spec.Driver.TerminationGracePeriodSeconds
-	} else {
+	} else if initialPod.Spec.TerminationGracePeriodSeconds == nil {
 		driverPodSpec.TerminationGracePeriodSeconds = common.Int64Pointer(DefaultTerminationGracePeriodSeconds)
 	}
 	//Tolerations
 	if app.Spec.Driver.Tolerations != nil {
 		driverPodSpec.Tolerations = app.Spec.Driver.Tolerations
-	} else {
+	} else if len(initialPod.Spec.Tolerations) == 0 {
 		//Assigning default toleration
 		var tolerations []apiv1.Toleration
 		tolerations = []apiv1.Toleration{
@@ -234,11 +217,41 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 		},
 	}
 
+	//Spark metrics and Prometheus JMX exporter configurations shipped in the driver ConfigMap
+	var monitoringConfigFiles []string
+	if common.HasMetricsPropertiesContent(app) {
+		monitoringConfigFiles = append(monitoringConfigFiles, common.MetricsPropertiesFileName)
+	}
+	if common.HasPrometheusConfigContent(app) {
+		monitoringConfigFiles = append(monitoringConfigFiles, common.PrometheusConfigFileName)
+	}
+	for _, fileName := range monitoringConfigFiles {
+		sparkConfVolume.ConfigMap.Items = append(sparkConfVolume.ConfigMap.Items, apiv1.KeyToPath{
+			Key:  fileName,
+			Mode: Int32Pointer(420),
+			Path: fileName,
+		})
+	}
+
 	driverPodVolumes = append(driverPodVolumes, sparkConfVolume)
 
-	driverPodContainerSpec, resolvedLocalDirs := CreateDriverPodContainerSpec(app)
+	// Volumes backing the Kerberos and Kubernetes credentials mounts added by handleKerberoCreds
+	kerberosResources, err := buildKerberosResources(app, podObjectMetadata.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to setup kerberos configuration for the driver pod %s in namespace %s: %v", common.GetDriverPodName(app), app.Namespace, err)
+	}
+	if err = createKerberosResources(kerberosResources, kubeClient); err != nil {
+		return "", fmt.Errorf("failed to create kerberos resources for the driver pod %s in namespace %s: %v", common.GetDriverPodName(app), app.Namespace, err)
+	}
+	driverPodVolumes = append(driverPodVolumes, kerberosResources.volumes...)
+
+	driverPodContainerSpec, err := CreateDriverPodContainerSpec(app, config, reservedPorts)
+	if err != nil {
+		return "", fmt.Errorf("failed to build the container of the driver pod %s in namespace %s: %w", common.GetDriverPodName(app), app.Namespace, err)
+	}
+	driverPodContainerSpec.Env = append(driverPodContainerSpec.Env, secretKeyRefEnvVars...)
 	var containerSpecList []apiv1.Container
-	localDirFeatureSetupError := handleLocalDirsFeatureStep(app, resolvedLocalDirs, &driverPodVolumes, &driverPodContainerSpec.VolumeMounts, &driverPodContainerSpec.Env, appSpecVolumeMounts, appSpecVolumes)
+	localDirFeatureSetupError := handleLocalDirsFeatureStep(app, config.Driver, &driverPodVolumes, &driverPodContainerSpec.VolumeMounts, &driverPodContainerSpec.Env, appSpecVolumeMounts, appSpecVolumes)
 	if localDirFeatureSetupError != nil {
 		return "", fmt.Errorf("failed to setup local directory for the driver pod %s in namespace %s: %v", common.GetDriverPodName(app), app.Namespace, localDirFeatureSetupError)
 	}
@@ -260,9 +273,17 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 			driverPodVolumes, driverPodContainerSpec = addSecret(secret, volumeExtension, driverPodVolumes, driverPodContainerSpec)
 		}
 	}
+	//ConfigMaps listed in the driver spec
+	existingVolumes := append(append([]apiv1.Volume{}, driverPodVolumes...), appSpecVolumes...)
+	configMapVolumes, driverPodContainerSpec, err := addConfigMaps(app.Spec.Driver.ConfigMaps, existingVolumes, driverPodContainerSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to mount configmaps for the driver pod %s in namespace %s: %v", common.GetDriverPodName(app), app.Namespace, err)
+	}
+	driverPodVolumes = append(driverPodVolumes, configMapVolumes...)
 	containerSpecList = append(containerSpecList, driverPodContainerSpec)
 
-	containerSpecList = handleSideCars(app, containerSpecList, appSpecVolumes)
+	sideCarVolumes := append(append([]apiv1.Volume{}, appSpecVolumes...), configMapVolumes...)
+	containerSpecList = handleSideCars(app, containerSpecList, sideCarVolumes)
 
 	if app.Spec.Driver.InitContainers != nil {
 		driverPodSpec.InitContainers = app.Spec.Driver.InitContainers
@@ -277,6 +298,15 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 		ObjectMeta: podObjectMetadata,
 		Spec:       driverPodSpec,
 	}
+	if templateFileExists {
+		mergedPod, mergeErr := mergePodTemplate(initialPod, *driverPod)
+		if mergeErr != nil {
+			log.Printf("ERROR: Failed to merge pod template: %v", mergeErr)
+			return "", fmt.Errorf("failed to merge pod template for the driver pod %s in namespace %s: %v", common.GetDriverPodName(app), app.Namespace, mergeErr)
+		}
+		driverPod = &mergedPod
+		log.Printf("Merged driver pod into pod template")
+	}
 
 	//Check existence of pod
 	createPodErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -294,8 +324,8 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 			return fmt.Errorf("error while retrieving driver pod: %w", err)
 			//return err
 		}
-		existingDriverPod.ObjectMeta = podObjectMetadata
-		existingDriverPod.Spec = driverPodSpec
+		existingDriverPod.ObjectMeta = driverPod.ObjectMeta
+		existingDriverPod.Spec = driverPod.Spec
 		_, updateErr := kubeClient.CoreV1().Pods(app.Namespace).Update(context.TODO(), existingDriverPod, metav1.UpdateOptions{})
 		if updateErr != nil {
 			return fmt.Errorf("error while updating driver pod: %w", updateErr)
@@ -319,38 +349,20 @@ func Create(app *v1beta2.SparkApplication, serviceLabels map[string]string, driv
 	return string(pod.UID), nil
 }
 
-func handleSideCars(app *v1beta2.SparkApplication, containerSpecList []apiv1.Container, appSpecVolumes []apiv1.Volume) []apiv1.Container {
-	if app.Spec.Driver.Sidecars != nil {
+// handleSideCars appends the driver sidecars, keeping only the volume mounts that refer to a volume of the pod
+func handleSideCars(app *v1beta2.SparkApplication, containerSpecList []apiv1.Container, podVolumes []apiv1.Volume) []apiv1.Container {
+	for _, sideCarContainer := range app.Spec.Driver.Sidecars {
+		//Handle volume mounts
 		var sideCarVolumeMounts []apiv1.VolumeMount
-		for _, configMap := range app.Spec.Driver.ConfigMaps {
-			//Volume mount for the configmap
-			volumeMount := apiv1.VolumeMount{
-				Name:      configMap.Name + "-vol",
-				MountPath: configMap.Path,
+		for _, volumeTobeMounted := range sideCarContainer.VolumeMounts {
+			//Volume mount for the volumes
+			if checkVolumeMountIsVolume(podVolumes, volumeTobeMounted.Name) {
+				sideCarVolumeMounts = append(sideCarVolumeMounts, volumeTobeMounted)
 			}
-			sideCarVolumeMounts = append(sideCarVolumeMounts, volumeMount)
 		}
+		sideCarContainer.VolumeMounts = sideCarVolumeMounts
 
-		for _, sideCarContainer := range app.Spec.Driver.Sidecars {
-			//Handle volume mounts
-			for _, volumeTobeMounted := range sideCarContainer.VolumeMounts {
-				//Volume mount for the volumes
-				if checkVolumeMountIsVolume(appSpecVolumes, volumeTobeMounted.Name) {
-					volumeMount := apiv1.VolumeMount{
-						Name:      volumeTobeMounted.Name,
-						MountPath: volumeTobeMounted.MountPath,
-						ReadOnly:  volumeTobeMounted.ReadOnly,
-					}
-					sideCarVolumeMounts = append(sideCarVolumeMounts, volumeMount)
-				}
-			}
-			sideCarContainer.VolumeMounts = nil
-			if len(sideCarVolumeMounts) > 0 {
-				sideCarContainer.VolumeMounts = sideCarVolumeMounts
-			}
-
-			containerSpecList = append(containerSpecList, sideCarContainer)
-		}
+		containerSpecList = append(containerSpecList, sideCarContainer)
 	}
 	return containerSpecList
 }
@@ -364,8 +376,9 @@ func checkVolumeMountIsVolume(appSpecVolumes []apiv1.Volume, volumeName string)
 	return false
 }
 
-// CreateDriverPodContainerSpec Helper func to create Driver Pod Driver contianer spec creation
-func CreateDriverPodContainerSpec(app *v1beta2.SparkApplication) (apiv1.Container, []string) {
+// CreateDriverPodContainerSpec Helper func to create Driver Pod Driver contianer spec creation, the reserved ports
+// being those returned by getReservedDriverPorts
+func CreateDriverPodContainerSpec(app *v1beta2.SparkApplication, config resolver.Config, reservedPorts map[string]int32) (apiv1.Container, error) {
 	var driverPodContainerSpec apiv1.Container
 	mainClass := ""
 	mainApplicationFile := ""
@@ -417,15 +430,14 @@ func CreateDriverPodContainerSpec(app *v1beta2.SparkApplication) (apiv1.Containe
 	driverPodContainerEnvVars = append(driverPodContainerEnvVars, driverPodContainerEnvVarBindAddress)
 
 	// Add all the spark.kubernetes.driverEnv. prefixed sparkConf key value pairs
-	var resolvedLocalDirs []string
-	resolvedLocalDirs, driverPodContainerEnvVars = processSparkConfEnv(app, driverPodContainerEnvVars)
+	driverPodContainerEnvVars = processSparkConfEnv(app, driverPodContainerEnvVars)
 	sparkConfKeyValuePairs := app.Spec.SparkConf
 
 	// Addition of the spark.kubernetes.kerberos.tokenSecret.itemKey
 	// Handling https://spark.apache.org/docs/3.0.0-preview2/security.html#long-running-applications
 	//spark.kubernetes.kerberos.tokenSecret.name spark.kubernetes.kerberos.tokenSecret.itemKey
 	sparkConfValue, valueExists := sparkConfKeyValuePairs[KerberosTokenSecretItemKey]
-	if valueExists {
+	if valueExists && sparkConfKeyValuePairs[KerberosTokenSecretName] != "" {
 		var driverPodContainerEnvVar apiv1.EnvVar
 		driverPodContainerEnvVar.Name = KerberosHadoopSecretFilePathKey
 		driverPodContainerEnvVar.Value = KerberosHadoopSecretFilePath + sparkConfValue
@@ -440,26 +452,9 @@ func CreateDriverPodContainerSpec(app *v1beta2.SparkApplication) (apiv1.Containe
 	//Assign the Driver Pod Container Environment variables to Container Spec
 	driverPodContainerSpec.Env = driverPodContainerEnvVars
 
-	//Assign Driver Pod container image from Spec or from sparkConf
-	if app.Spec.Driver.Image != nil {
-		driverPodContainerSpec.Image = *app.Spec.Driver.Image
-	} else if app.Spec.Image != nil {
-		driverPodContainerSpec.Image = *app.Spec.Image
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.driver.container.image") {
-		driverPodContainerSpec.Image = app.Spec.SparkConf["spark.kubernetes.driver.container.image"]
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.container.image") {
-		driverPodContainerSpec.Image = app.Spec.SparkConf["spark.kubernetes.container.image"]
-	}
-
-	if app.Spec.ImagePullPolicy != nil {
-		driverPodContainerSpec.ImagePullPolicy = apiv1.PullPolicy(*app.Spec.ImagePullPolicy)
-	} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.container.image.pullPolicy") {
-		pullPolicy := app.Spec.SparkConf["spark.kubernetes.container.image.pullPolicy"]
-		driverPodContainerSpec.ImagePullPolicy = apiv1.PullPolicy(pullPolicy)
-	} else {
-		//Default value
-		driverPodContainerSpec.ImagePullPolicy = ImagePullPolicyIfNotPresent
-	}
+	//Driver Pod container image and pull policy from the spec, or else from sparkConf
+	driverPodContainerSpec.Image = config.Driver.Image.Value
+	driverPodContainerSpec.ImagePullPolicy = apiv1.PullPolicy(config.ImagePullPolicy.Value)
 
 	//Driver Pod Container Name
 	driverPodContainerSpec.Name = common.SparkDriverContainerName
@@ -467,24 +462,60 @@ func CreateDriverPodContainerSpec(app *v1beta2.SparkApplication) (apiv1.Containe
 	//Driver pod contianer ports
 	driverPodContainerSpec.Ports = []apiv1.ContainerPort{
 		{
-			ContainerPort: int32(common.GetDriverPort(sparkConfKeyValuePairs)),
+			ContainerPort: reservedPorts[DriverPortName],
 			Name:          DriverPortName,
 			Protocol:      Protocol,
 		},
 		{
-			ContainerPort: int32(getBlockManagerPort(sparkConfKeyValuePairs)),
+			ContainerPort: reservedPorts[BlockManagerPortName],
 			Name:          BlockManagerPortName,
 			Protocol:      Protocol,
 		},
 		{
-			ContainerPort: UiPort,
+			ContainerPort: reservedPorts[UiPortName],
 			Name:          UiPortName,
 			Protocol:      Protocol,
 		},
 	}
+	//Prometheus JMX exporter port
+	if common.ExposesDriverMetrics(app) {
+		driverPodContainerSpec.Ports = append(driverPodContainerSpec.Ports, apiv1.ContainerPort{
+			ContainerPort: common.GetPrometheusPort(app),
+			Name:          common.GetPrometheusPortName(app),
+			Protocol:      Protocol,
+		})
+	}
+	//Additional ports declared in the driver spec, e.g. a Thrift server
+	for _, port := range app.Spec.Driver.Ports {
+		driverPodContainerSpec.Ports = append(driverPodContainerSpec.Ports, apiv1.ContainerPort{
+			ContainerPort: port.ContainerPort,
+			Name:          port.Name,
+			Protocol:      apiv1.Protocol(common.GetPortProtocol(port)),
+		})
+	}
+
+	//Environment variables sourced from ConfigMaps and Secrets
+	if len(app.Spec.Driver.EnvFrom) > 0 {
+		driverPodContainerSpec.EnvFrom = append(driverPodContainerSpec.EnvFrom, app.Spec.Driver.EnvFrom...)
+	}
+
+	//Driver container lifecycle hooks, e.g. a preStop hook flushing event logs
+	if app.Spec.Driver.Lifecycle != nil {
+		driverPodContainerSpec.Lifecycle = app.Spec.Driver.Lifecycle.DeepCopy()
+	}
+	//Driver container probes from spec.driver.template, e.g. a readiness probe on the driver-rpc-port
+	if _, templateContainer := common.GetDriverTemplateContainer(app); templateContainer != nil {
+		driverPodContainerSpec.ReadinessProbe = templateContainer.ReadinessProbe.DeepCopy()
+		driverPodContainerSpec.LivenessProbe = templateContainer.LivenessProbe.DeepCopy()
+		driverPodContainerSpec.StartupProbe = templateContainer.StartupProbe.DeepCopy()
+	}
 
 	//Driver pod container cpu and memory requests and limits populating
-	driverPodContainerSpec.Resources = handleResources(app)
+	resources, err := GetResourceRequirements(config.Driver)
+	if err != nil {
+		return driverPodContainerSpec, err
+	}
+	driverPodContainerSpec.Resources = resources
 
 	//Security Context
 	driverPodContainerSpec.SecurityContext = &apiv1.SecurityContext{
@@ -511,93 +542,26 @@ func CreateDriverPodContainerSpec(app *v1beta2.SparkApplication) (apiv1.Containe
 
 	driverPodContainerSpec.VolumeMounts = volumeMounts
 
-	return driverPodContainerSpec, resolvedLocalDirs
+	return driverPodContainerSpec, nil
 }
 
 func checkMountingKubernetesCredentials(sparkConfKeyValuePairs map[string]string) bool {
 	if sparkConfKeyValuePairs != nil {
-		OAuthTokenConfFileExists := sparkConfKeyValuePairs[OAuthTokenConfFile] != ""
-		ClientKeyFileExists := sparkConfKeyValuePairs[ClientKeyFile] != ""
-		ClientCertFileExists := sparkConfKeyValuePairs[ClientCertFile] != ""
-		CaCertFileExists := sparkConfKeyValuePairs[CaCertFile] != ""
-		if OAuthTokenConfFileExists || ClientKeyFileExists || ClientCertFileExists || CaCertFileExists {
+		// Credential files are mounted by the application itself, only the inline OAuth token is shipped in a secret
+		if sparkConfKeyValuePairs[common.SparkDriverOAuthTokenKey] != "" {
 			return true
 		}
 	}
 	return false
 }
 
-func incorporateMemoryOvehead(memoryNumber int, app *v1beta2.SparkApplication, memoryUnit string) string {
-	//Memory Overhead or Memory OverheadFactor incorporating
-	var memory string
-	if app.Spec.Driver.MemoryOverhead != nil || common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.memoryOverhead") || common.CheckSparkConf(app.Spec.SparkConf, "spark.driver.memoryOverhead") {
-		var memoryOverhead string
-		if app.Spec.Driver.MemoryOverhead != nil {
-			memoryOverhead = *app.Spec.Driver.MemoryOverhead
-		} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.driver.memoryOverhead") {
-			memoryOverhead = app.Spec.SparkConf["spark.driver.memoryOverhead"]
-		} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.kubernetes.memoryOverhead") {
-			memoryOverhead = app.Spec.SparkConf["spark.kubernetes.memoryOverhead"]
-		}
-		//both memory and memoryOverhead are in same Memory Unit(MiB or GiB)
-		// Amount of memory to use for the driver process, i.e. where SparkContext is initialized, in the same format as JVM memory strings with a size unit suffix ("k", "m", "g" or "t") (e.g. 512m, 2g).
-		//https://spark.apache.org/docs/latest/configuration.html
-		memoryOverheadInMiB := processMemoryUnit(memoryOverhead)
-
-		memoryWithOverhead := memoryNumber + memoryOverheadInMiB
-		memory = fmt.Sprintf("%d%s", memoryWithOverhead, memoryUnit)
-	} else {
-		MemoryOveheadMinInMiB := 384.0
-		var memoryOverheadFactor float64
-		if app.Spec.MemoryOverheadFactor != nil {
-			memoryOverheadFactor, _ = strconv.ParseFloat(*app.Spec.MemoryOverheadFactor, 64)
-		} else if common.CheckSparkConf(app.Spec.SparkConf, "spark.driver.memoryOverheadFactor") {
-			memOverheadFactorString := app.Spec.SparkConf["spark.driver.memoryOverheadFactor"]
-			memoryOverheadFactor, _ = strconv.ParseFloat(memOverheadFactorString, 64)
-		} else {
-			memoryOverheadFactor, _ = strconv.ParseFloat(common.GetMemoryOverheadFactor(app), 64)
-		}
-		memoryWithOverhead := memoryOverheadFactor * float64(memoryNumber)
-		memoryOverheadFactorInclusion := math.Max(MemoryOveheadMinInMiB, memoryWithOverhead)
-		memoryWithOverheadFactor := float64(memoryNumber) + memoryOverheadFactorInclusion
-		memory = fmt.Sprintf("%F%s", memoryWithOverheadFactor, memoryUnit)
-	}
-	return memory
-}
-
-func processMemoryUnit(memoryData string) int {
-
-	memoryData = strings.TrimSpace(memoryData)
-	memoryData = strings.ToUpper(memoryData)
-	units := map[string]float64{
-		"K": 0.0009765625,
-		"M": 1,
-		"G": 1024,
-		"T": 1024 * 1024,
-	}
-	for unit, multiplier := range units {
-		if strings.Contains(memoryData, unit) {
-			memoryNumber := memoryData[:strings.Index(memoryData, unit)]
-			memoryConverted, _ := strconv.Atoi(memoryNumber)
-			return int(float64(memoryConverted) * multiplier)
-		}
-	}
-	//handling default case
-	memoryInMiB, _ := strconv.Atoi(memoryData)
-	return memoryInMiB
-}
-
-func processSparkConfEnv(app *v1beta2.SparkApplication, driverPodContainerEnvVars []apiv1.EnvVar) ([]string, []apiv1.EnvVar) {
-	var resolvedLocalDirs []string
+func processSparkConfEnv(app *v1beta2.SparkApplication, driverPodContainerEnvVars []apiv1.EnvVar) []apiv1.EnvVar {
 	sparkConfKeyValuePairs := app.Spec.SparkConf
 	var driverPodContainerEnvConfigVars apiv1.EnvVar
 	for sparkConfKey, sparkConfValue := range sparkConfKeyValuePairs {
 		if strings.Contains(sparkConfKey, SparkDriverEnvPrefix) {
 			lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
 			driverPodContainerEnvConfigVarKey := sparkConfKey[lastDotIndex+1:]
-			if driverPodContainerEnvConfigVarKey == "SPARK_LOCAL_DIRS" {
-				resolvedLocalDirs = strings.Split(sparkConfValue, ",")
-			}
 			driverPodContainerEnvConfigVars.Name = driverPodContainerEnvConfigVarKey
 			driverPodContainerEnvConfigVars.Value = sparkConfValue
 			driverPodContainerEnvVars = append(driverPodContainerEnvVars, driverPodContainerEnvConfigVars)
@@ -614,26 +578,13 @@ func processSparkConfEnv(app *v1beta2.SparkApplication, driverPodContainerEnvVar
 			driverPodContainerEnvVars = append(driverPodContainerEnvVars, driverPodContainerEnvVar)
 		}
 	}
-	//spark.kubernetes.driver.secretKeyRef.
-	var driverPodContainerEnvVar apiv1.EnvVar
-	for sparkConfKey, sparkConfValue := range app.Spec.SparkConf {
-		if strings.Contains(sparkConfKey, common.SparkDriverSecretKeyRefKeyPrefix) {
-			lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
-			driverPodSecretKeyRef := sparkConfKey[lastDotIndex+1:]
-			driverPodContainerEnvVar.Name = driverPodSecretKeyRef
-			driverPodContainerEnvVar.Value = sparkConfValue
-			driverPodContainerEnvVars = append(driverPodContainerEnvVars, driverPodContainerEnvVar)
-		}
-	}
-	return resolvedLocalDirs, driverPodContainerEnvVars
+	return driverPodContainerEnvVars
 }
 
 func handleKerberoCreds(app *v1beta2.SparkApplication, volumeMounts []apiv1.VolumeMount) []apiv1.VolumeMount {
 	sparkConfKeyValuePairs := app.Spec.SparkConf
 	if sparkConfKeyValuePairs != nil {
-		_, kerberosPatheExists := sparkConfKeyValuePairs[KerberosPath]
-		_, kerberosConfigMapNameExists := sparkConfKeyValuePairs[KerberosConfigMapName]
-		if kerberosPatheExists || kerberosConfigMapNameExists {
+		if sparkConfKeyValuePairs[common.SparkKerberosKrb5ConfigMapKey] != "" {
 			kerberosConfigMapVolumeMount := apiv1.VolumeMount{
 				Name:      KerberosFileVolume,
 				MountPath: KerberosFileDirectoryPath + ForwardSlash + KerberosFileName,
@@ -641,6 +592,13 @@ func handleKerberoCreds(app *v1beta2.SparkApplication, volumeMounts []apiv1.Volu
 			}
 			volumeMounts = append(volumeMounts, kerberosConfigMapVolumeMount)
 		}
+		if sparkConfKeyValuePairs[KerberosTokenSecretName] != "" && sparkConfKeyValuePairs[KerberosTokenSecretItemKey] != "" {
+			hadoopSecretVolumeMount := apiv1.VolumeMount{
+				Name:      HadoopSecretVolume,
+				MountPath: strings.TrimSuffix(KerberosHadoopSecretFilePath, ForwardSlash),
+			}
+			volumeMounts = append(volumeMounts, hadoopSecretVolumeMount)
+		}
 	}
 
 	if checkMountingKubernetesCredentials(app.Spec.SparkConf) {
@@ -653,33 +611,20 @@ func handleKerberoCreds(app *v1beta2.SparkApplication, volumeMounts []apiv1.Volu
 	return volumeMounts
 }
 
-func handleResources(app *v1beta2.SparkApplication) apiv1.ResourceRequirements {
+// GetResourceRequirements returns the requests and limits of the driver container, or an error for unparseable values
+func GetResourceRequirements(driverConfig resolver.RoleConfig) (apiv1.ResourceRequirements, error) {
 	var driverPodResourceRequirement apiv1.ResourceRequirements
-	var memoryQuantity resource.Quantity
-	var memoryInBytes string
-	memoryValExists := false
-	cpuValExists := false
-	//Memory Request
-	if app.Spec.Driver.Memory != nil {
-		memoryData := *app.Spec.Driver.Memory
-		// Identify memory unit and convert everything in MiB for uniformity
-		memoryInMiB := processMemoryUnit(memoryData)
-		memoryInBytes = incorporateMemoryOvehead(memoryInMiB, app, "Mi")
-		memoryQuantity = resource.MustParse(memoryInBytes)
-		memoryValExists = true
-	} else if common.CheckSparkConf(app.Spec.SparkConf, SparkDriverMemory) {
-		memoryQuantity = resource.MustParse(app.Spec.SparkConf[SparkDriverMemory])
-		memoryValExists = true
-	} else { //setting default value
-		memoryQuantity = resource.MustParse("512Mi")
-	}
-
-	var cpuQuantity resource.Quantity
-	if app.Spec.Driver.CoreLimit != nil || common.CheckSparkConf(app.Spec.SparkConf, common.SparkDriverCoreLimitKey) {
-		if app.Spec.Driver.CoreLimit != nil {
-			cpuQuantity = resource.MustParse(*app.Spec.Driver.CoreLimit)
-		} else {
-			cpuQuantity = resource.MustParse(app.Spec.SparkConf[common.SparkDriverCoreLimitKey])
+	//Memory Request and Limit, the heap with its overhead as Spark computes it
+	memoryMiB, err := memory.GetContainerMemoryMiB(driverConfig)
+	if err != nil {
+		return driverPodResourceRequirement, fmt.Errorf("invalid driver memory: %w", err)
+	}
+	memoryQuantity := *resource.NewQuantity(memoryMiB*memory.MiB, resource.BinarySI)
+
+	if driverConfig.CoreLimit.IsSet() {
+		cpuQuantity, err := parseQuantity(driverConfig.CoreLimit.Describe(), driverConfig.CoreLimit.Value)
+		if err != nil {
+			return driverPodResourceRequirement, err
 		}
 
 		driverPodResourceRequirement.Limits = apiv1.ResourceList{
@@ -693,40 +638,27 @@ func handleResources(app *v1beta2.SparkApplication) apiv1.ResourceRequirements {
 	}
 	//Cores OR Cores Request - https://github.com/GoogleCloudPlatform/spark-on-k8s-operator/issues/581
 	//spark.kubernetes.driver.request.cores takes precedence over spark.driver.cores for specifying the driver pod cpu request if set.
-	//Priority sequence is - if value supplied in app spec directly, if not, then if supplied in sparkConf or default
-	if app.Spec.Driver.CoreRequest != nil {
-		cpuQuantity = resource.MustParse(*app.Spec.Driver.CoreRequest)
-		cpuValExists = true
-	} else if common.CheckSparkConf(app.Spec.SparkConf, common.SparkDriverCoreRequestKey) {
-		cpuQuantity = resource.MustParse(app.Spec.SparkConf[common.SparkDriverCoreRequestKey])
-		cpuValExists = true
-	} else if app.Spec.Driver.Cores != nil {
-		cpuQuantity = resource.MustParse(fmt.Sprint(*app.Spec.Driver.Cores))
-		cpuValExists = true
-	} else if common.CheckSparkConf(app.Spec.SparkConf, SparkDriverCores) {
-		cpuQuantity = resource.MustParse(app.Spec.SparkConf[SparkDriverCores])
-		cpuValExists = true
-	} else {
-		//Setting default value as cores or coreLimit is not passed
-		cpuValExists = true
-		cpuQuantity = resource.MustParse("1")
+	cores := driverConfig.Cores
+	if driverConfig.CoreRequest.IsSet() {
+		cores = driverConfig.CoreRequest
+	}
+	cpuQuantity, err := parseQuantity(cores.Describe(), cores.Value)
+	if err != nil {
+		return driverPodResourceRequirement, err
 	}
 
-	if cpuValExists && memoryValExists {
-		driverPodResourceRequirement.Requests = apiv1.ResourceList{
-			apiv1.ResourceMemory: memoryQuantity,
-			apiv1.ResourceCPU:    cpuQuantity,
-		}
-
-	} else if memoryValExists {
-		driverPodResourceRequirement.Requests = apiv1.ResourceList{
-			apiv1.ResourceMemory: memoryQuantity,
-		}
-	} else if cpuValExists {
-		driverPodResourceRequirement.Requests = apiv1.ResourceList{
-			apiv1.ResourceCPU: cpuQuantity,
-		}
+	driverPodResourceRequirement.Requests = apiv1.ResourceList{
+		apiv1.ResourceMemory: memoryQuantity,
+		apiv1.ResourceCPU:    cpuQuantity,
 	}
+	return driverPodResourceRequirement, nil
+}
 
-	return driverPodResourceRequirement
+// parseQuantity parses a resource quantity, naming the setting it comes from in the error
+func parseQuantity(setting string, value string) (resource.Quantity, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return quantity, fmt.Errorf("invalid %s %q: %w", setting, value, err)
+	}
+	return quantity, nil
 }
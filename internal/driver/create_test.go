@@ -0,0 +1,458 @@
+package driver
+
+import (
+	"context"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// renderDriverPod runs Create against a fake clientset and returns the created driver pod
+func renderDriverPod(t *testing.T, app *v1beta2.SparkApplication) (*apiv1.Pod, *fake.Clientset) {
+	t.Helper()
+	kubeClient := fake.NewSimpleClientset()
+	_, err := Create(app, resolver.Resolve(app), map[string]string{"spark-role": "driver"}, "test-app-driver-conf-map", "", kubeClient, app.Spec.Driver.VolumeMounts, app.Spec.Volumes)
+	require.NoError(t, err)
+	pod, err := kubeClient.CoreV1().Pods("default").Get(context.TODO(), "test-app-driver", metav1.GetOptions{})
+	require.NoError(t, err)
+	return pod, kubeClient
+}
+
+func findVolume(pod *apiv1.Pod, name string) *apiv1.Volume {
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == name {
+			return &pod.Spec.Volumes[i]
+		}
+	}
+	return nil
+}
+
+func findVolumeMount(container apiv1.Container, name string) *apiv1.VolumeMount {
+	for i := range container.VolumeMounts {
+		if container.VolumeMounts[i].Name == name {
+			return &container.VolumeMounts[i]
+		}
+	}
+	return nil
+}
+
+func findEnvVar(container apiv1.Container, name string) *apiv1.EnvVar {
+	for i := range container.Env {
+		if container.Env[i].Name == name {
+			return &container.Env[i]
+		}
+	}
+	return nil
+}
+
+// assertMountsHaveVolumes checks that every volume mount of every container is backed by a pod volume
+func assertMountsHaveVolumes(t *testing.T, pod *apiv1.Pod) {
+	t.Helper()
+	for _, container := range pod.Spec.Containers {
+		for _, volumeMount := range container.VolumeMounts {
+			assert.NotNil(t, findVolume(pod, volumeMount.Name), "container %s mounts %s without a matching volume", container.Name, volumeMount.Name)
+		}
+	}
+}
+
+func TestCreateDriverLifecycle(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	app.Spec.Driver.Lifecycle = &apiv1.Lifecycle{
+		PreStop: &apiv1.LifecycleHandler{
+			Exec: &apiv1.ExecAction{Command: []string{"/opt/spark/flush-event-logs.sh"}},
+		},
+		PostStart: &apiv1.LifecycleHandler{
+			Sleep: &apiv1.SleepAction{Seconds: 5},
+		},
+	}
+
+	pod, _ := renderDriverPod(t, app)
+
+	driverContainer := pod.Spec.Containers[0]
+	assert.Equal(t, app.Spec.Driver.Lifecycle, driverContainer.Lifecycle)
+	assert.Nil(t, driverContainer.ReadinessProbe)
+}
+
+func TestCreateDriverProbesFromTemplate(t *testing.T) {
+	readinessProbe := &apiv1.Probe{
+		ProbeHandler:  apiv1.ProbeHandler{TCPSocket: &apiv1.TCPSocketAction{Port: intstr.FromString(DriverPortName)}},
+		PeriodSeconds: 10,
+	}
+	startupProbe := &apiv1.Probe{
+		ProbeHandler:     apiv1.ProbeHandler{TCPSocket: &apiv1.TCPSocketAction{Port: intstr.FromString(DriverPortName)}},
+		FailureThreshold: 30,
+	}
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	app.Spec.Driver.Template = &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Containers: []apiv1.Container{
+		{Name: "log-shipper", ReadinessProbe: &apiv1.Probe{PeriodSeconds: 99}},
+		{Name: "spark-kubernetes-driver", ReadinessProbe: readinessProbe, StartupProbe: startupProbe},
+	}}}
+
+	pod, _ := renderDriverPod(t, app)
+
+	driverContainer := pod.Spec.Containers[0]
+	assert.Equal(t, readinessProbe, driverContainer.ReadinessProbe)
+	assert.Equal(t, startupProbe, driverContainer.StartupProbe)
+	assert.Nil(t, driverContainer.LivenessProbe)
+	var portNames []string
+	for _, port := range driverContainer.Ports {
+		portNames = append(portNames, port.Name)
+	}
+	assert.Contains(t, portNames, driverContainer.ReadinessProbe.TCPSocket.Port.StrVal)
+}
+
+func TestCreateDriverExtraPorts(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	app.Spec.Driver.Ports = []v1beta2.Port{
+		{Name: "thrift", ContainerPort: 10000},
+		{Name: "rest", Protocol: "TCP", ContainerPort: 8090},
+	}
+
+	pod, _ := renderDriverPod(t, app)
+
+	ports := pod.Spec.Containers[0].Ports
+	require.Len(t, ports, 5)
+	assert.Equal(t, apiv1.ContainerPort{Name: "thrift", ContainerPort: 10000, Protocol: apiv1.ProtocolTCP}, ports[3])
+	assert.Equal(t, apiv1.ContainerPort{Name: "rest", ContainerPort: 8090, Protocol: apiv1.ProtocolTCP}, ports[4])
+}
+
+func TestCreateDriverPortConflict(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{"spark.driver.port": "10000"}})
+	app.Spec.Driver.Ports = []v1beta2.Port{{Name: "thrift", ContainerPort: 10000}}
+	kubeClient := fake.NewSimpleClientset()
+
+	_, err := Create(app, resolver.Resolve(app), map[string]string{}, "test-app-driver-conf-map", "", kubeClient, nil, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), DriverPortName)
+	pods, listErr := kubeClient.CoreV1().Pods("default").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, listErr)
+	assert.Empty(t, pods.Items)
+}
+
+func TestCreateDriverUIPortConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    int32
+		wantErr bool
+	}{
+		{name: "configured UI port", port: 4041, wantErr: true},
+		{name: "default UI port", port: 4040, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{"spark.ui.port": "4041"}})
+			app.Spec.Driver.Ports = []v1beta2.Port{{Name: "extra", ContainerPort: tt.port}}
+
+			_, err := Create(app, resolver.Resolve(app), map[string]string{}, "test-app-driver-conf-map", "", fake.NewSimpleClientset(), nil, nil)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), resolver.UIPortName)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateDriverPrometheusExporter(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	app.Spec.Driver.Annotations = map[string]string{"prometheus.io/path": "/custom"}
+	app.Spec.Monitoring = &v1beta2.MonitoringSpec{
+		ExposeDriverMetrics: true,
+		Prometheus:          &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar"},
+	}
+
+	pod, _ := renderDriverPod(t, app)
+
+	assert.Equal(t, map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "8090",
+		"prometheus.io/path":   "/custom",
+	}, pod.Annotations)
+	assert.Equal(t, map[string]string{"prometheus.io/path": "/custom"}, app.Spec.Driver.Annotations, "the application spec is not modified")
+
+	ports := pod.Spec.Containers[0].Ports
+	require.Len(t, ports, 4)
+	assert.Equal(t, apiv1.ContainerPort{Name: "jmx-exporter", ContainerPort: 8090, Protocol: apiv1.ProtocolTCP}, ports[3])
+
+	confVolume := findVolume(pod, SparkConfVolumeDriver)
+	require.NotNil(t, confVolume)
+	var keys []string
+	for _, item := range confVolume.ConfigMap.Items {
+		keys = append(keys, item.Key)
+	}
+	assert.Equal(t, []string{SparkEnvScriptFileName, SparkPropertiesFileName, "metrics.properties", "prometheus.yaml"}, keys)
+}
+
+func TestCreateDriverPrometheusPortConflict(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	app.Spec.Driver.Ports = []v1beta2.Port{{Name: "rest", ContainerPort: 8090}}
+	app.Spec.Monitoring = &v1beta2.MonitoringSpec{
+		ExposeDriverMetrics: true,
+		Prometheus:          &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar"},
+	}
+
+	_, err := Create(app, resolver.Resolve(app), map[string]string{}, "test-app-driver-conf-map", "", fake.NewSimpleClientset(), nil, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jmx-exporter")
+}
+
+func TestCreateDriverConfigMapsAndEnvFrom(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	app.Spec.Driver.ConfigMaps = []v1beta2.NamePath{
+		{Name: "app-config", Path: "/etc/app"},
+		{Name: "app-config", Path: "/etc/app-copy"},
+		{Name: "log4j", Path: "/etc/log4j"},
+	}
+	app.Spec.Driver.EnvFrom = []apiv1.EnvFromSource{
+		{ConfigMapRef: &apiv1.ConfigMapEnvSource{LocalObjectReference: apiv1.LocalObjectReference{Name: "app-env"}}},
+		{Prefix: "DB_", SecretRef: &apiv1.SecretEnvSource{LocalObjectReference: apiv1.LocalObjectReference{Name: "db-credentials"}}},
+	}
+	app.Spec.Driver.Sidecars = []apiv1.Container{
+		{Name: "log-shipper", VolumeMounts: []apiv1.VolumeMount{{Name: "log4j-vol", MountPath: "/config"}, {Name: "unknown", MountPath: "/unknown"}}},
+		{Name: "proxy"},
+	}
+
+	pod, _ := renderDriverPod(t, app)
+
+	appConfigVolume := findVolume(pod, "app-config-vol")
+	require.NotNil(t, appConfigVolume)
+	assert.Equal(t, "app-config", appConfigVolume.ConfigMap.Name)
+	require.NotNil(t, findVolume(pod, "log4j-vol"))
+	volumeCount := 0
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == "app-config-vol" {
+			volumeCount++
+		}
+	}
+	assert.Equal(t, 1, volumeCount)
+
+	driverContainer := pod.Spec.Containers[0]
+	var mountPaths []string
+	for _, volumeMount := range driverContainer.VolumeMounts {
+		mountPaths = append(mountPaths, volumeMount.MountPath)
+	}
+	assert.Subset(t, mountPaths, []string{"/etc/app", "/etc/app-copy", "/etc/log4j"})
+	assert.Equal(t, app.Spec.Driver.EnvFrom, driverContainer.EnvFrom)
+
+	require.Len(t, pod.Spec.Containers, 3)
+	assert.Equal(t, []apiv1.VolumeMount{{Name: "log4j-vol", MountPath: "/config"}}, pod.Spec.Containers[1].VolumeMounts)
+	assert.Empty(t, pod.Spec.Containers[2].VolumeMounts)
+	assertMountsHaveVolumes(t, pod)
+}
+
+func TestCreateDriverConfigMapVolumeClash(t *testing.T) {
+	tests := []struct {
+		name       string
+		configMaps []v1beta2.NamePath
+		volumes    []apiv1.Volume
+	}{
+		{
+			name:       "clash with application volume",
+			configMaps: []v1beta2.NamePath{{Name: "app-config", Path: "/etc/app"}},
+			volumes:    []apiv1.Volume{{Name: "app-config-vol", VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}}}},
+		},
+		{
+			name:       "missing mount path",
+			configMaps: []v1beta2.NamePath{{Name: "app-config"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+			app.Spec.Driver.ConfigMaps = tt.configMaps
+			kubeClient := fake.NewSimpleClientset()
+
+			_, err := Create(app, resolver.Resolve(app), map[string]string{}, "test-app-driver-conf-map", "", kubeClient, nil, tt.volumes)
+
+			assert.Error(t, err)
+			pods, listErr := kubeClient.CoreV1().Pods("default").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, listErr)
+			assert.Empty(t, pods.Items)
+		})
+	}
+}
+
+func TestCreateDriverSecretKeyRefEnvVars(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		"spark.kubernetes.driver.secretKeyRef.DB_PASSWORD": "db-credentials:password",
+		"spark.kubernetes.driver.secretKeyRef.API_TOKEN":   "api:token:optional",
+		"spark.kubernetes.driver.secretKeyRef.OVERRIDDEN":  "old-secret:old-key",
+	}})
+	app.Spec.Driver.EnvSecretKeyRefs = map[string]v1beta2.NameKey{
+		"OVERRIDDEN": {Name: "new-secret", Key: "new-key"},
+	}
+
+	pod, _ := renderDriverPod(t, app)
+
+	driverContainer := pod.Spec.Containers[0]
+	dbPassword := findEnvVar(driverContainer, "DB_PASSWORD")
+	require.NotNil(t, dbPassword)
+	assert.Empty(t, dbPassword.Value)
+	assert.Equal(t, &apiv1.EnvVarSource{SecretKeyRef: &apiv1.SecretKeySelector{
+		LocalObjectReference: apiv1.LocalObjectReference{Name: "db-credentials"},
+		Key:                  "password",
+	}}, dbPassword.ValueFrom)
+
+	apiToken := findEnvVar(driverContainer, "API_TOKEN")
+	require.NotNil(t, apiToken)
+	require.NotNil(t, apiToken.ValueFrom.SecretKeyRef.Optional)
+	assert.True(t, *apiToken.ValueFrom.SecretKeyRef.Optional)
+
+	overridden := findEnvVar(driverContainer, "OVERRIDDEN")
+	require.NotNil(t, overridden)
+	assert.Equal(t, "new-secret", overridden.ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "new-key", overridden.ValueFrom.SecretKeyRef.Key)
+}
+
+func TestGetDriverSecretKeyRefEnvVarsInvalid(t *testing.T) {
+	tests := []struct {
+		name             string
+		sparkConf        map[string]string
+		envSecretKeyRefs map[string]v1beta2.NameKey
+	}{
+		{"missing key", map[string]string{"spark.kubernetes.driver.secretKeyRef.DB_PASSWORD": "db-credentials"}, nil},
+		{"unknown modifier", map[string]string{"spark.kubernetes.driver.secretKeyRef.DB_PASSWORD": "db-credentials:password:required"}, nil},
+		{"empty secret name", map[string]string{"spark.kubernetes.driver.secretKeyRef.DB_PASSWORD": ":password"}, nil},
+		{"invalid env var name", map[string]string{"spark.kubernetes.driver.secretKeyRef.1DB": "db-credentials:password"}, nil},
+		{"invalid key in spec", nil, map[string]v1beta2.NameKey{"DB_PASSWORD": {Name: "db-credentials", Key: "pass word"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: tt.sparkConf})
+			app.Spec.Driver.EnvSecretKeyRefs = tt.envSecretKeyRefs
+
+			envVars, err := getDriverSecretKeyRefEnvVars(app)
+
+			assert.Error(t, err)
+			assert.Nil(t, envVars)
+		})
+	}
+}
+
+func TestCreateDriverSchedulerNameAndAnnotations(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		"spark.kubernetes.scheduler.name":          "default-scheduler",
+		"spark.kubernetes.driver.annotation.owner": "conf",
+		"spark.kubernetes.driver.annotation.team":  "conf",
+	}})
+	app.Spec.Driver.Annotations = map[string]string{"team": "spec"}
+	// As set by the batch scheduler in the resolved configuration
+	config := resolver.Resolve(app)
+	config.Driver.SchedulerName = config.Driver.SchedulerName.Override("volcano", resolver.SourceBatchScheduler, "spec.batchScheduler")
+	config.Driver.Annotations["scheduling.k8s.io/group-name"] = resolver.Setting{Value: "spark-test-app-pg", Source: resolver.SourceBatchScheduler}
+
+	kubeClient := fake.NewSimpleClientset()
+	_, err := Create(app, config, map[string]string{}, "test-app-driver-conf-map", "", kubeClient, nil, nil)
+	require.NoError(t, err)
+	pod, err := kubeClient.CoreV1().Pods("default").Get(context.TODO(), "test-app-driver", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "volcano", pod.Spec.SchedulerName)
+	assert.Equal(t, map[string]string{
+		"owner":                        "conf",
+		"team":                         "spec",
+		"scheduling.k8s.io/group-name": "spark-test-app-pg",
+	}, pod.Annotations)
+}
+
+func TestCreateDriverConfigHashAnnotation(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	kubeClient := fake.NewSimpleClientset()
+	_, err := Create(app, resolver.Resolve(app), map[string]string{}, "test-app-driver-conf-map", "0123abcd", kubeClient, nil, nil)
+	require.NoError(t, err)
+	pod, err := kubeClient.CoreV1().Pods("default").Get(context.TODO(), "test-app-driver", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"sparkoperator.k8s.io/config-hash": "0123abcd"}, pod.Annotations)
+}
+
+func TestCreateDriverResolvedSettings(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		"spark.kubernetes.node.selector.disktype":                           "hdd",
+		"spark.kubernetes.node.selector.pool":                               "spark",
+		"spark.kubernetes.driver.node.selector.topology.kubernetes.io/zone": "zone-a",
+		"spark.kubernetes.driver.container.image":                           "spark:conf",
+		"spark.kubernetes.authenticate.driver.serviceAccountName":           "conf-sa",
+	}})
+	image := "spark:spec"
+	app.Spec.Image = &image
+	app.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+
+	pod, _ := renderDriverPod(t, app)
+
+	// The application and driver node selectors are merged, as in the properties file, instead of replacing each other
+	assert.Equal(t, map[string]string{"disktype": "ssd", "pool": "spark", "topology.kubernetes.io/zone": "zone-a"}, pod.Spec.NodeSelector)
+	assert.Equal(t, "spark:spec", pod.Spec.Containers[0].Image)
+	assert.Equal(t, apiv1.PullIfNotPresent, pod.Spec.Containers[0].ImagePullPolicy)
+	assert.Equal(t, "conf-sa", pod.Spec.ServiceAccountName)
+}
+
+func TestCreateDriverKueueSchedulingGate(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+	pod, _ := renderDriverPod(t, app)
+	assert.Empty(t, pod.Spec.SchedulingGates)
+
+	kueue := "kueue"
+	app.Spec.BatchScheduler = &kueue
+	pod, _ = renderDriverPod(t, app)
+	assert.Equal(t, []apiv1.PodSchedulingGate{{Name: "kueue.x-k8s.io/admission"}}, pod.Spec.SchedulingGates)
+	assert.Empty(t, pod.Spec.SchedulerName)
+}
+
+func TestCreateDriverInvalidValuesReturnErrors(t *testing.T) {
+	coreLimit := "two"
+	tests := []struct {
+		name       string
+		app        *v1beta2.SparkApplication
+		wantErrMsg string
+	}{
+		{
+			name:       "unparseable driver port",
+			app:        testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{"spark.driver.port": "rpc"}}),
+			wantErrMsg: `invalid port "rpc" in spec.sparkConf[spark.driver.port]`,
+		},
+		{
+			name:       "unparseable block manager port",
+			app:        testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{"spark.driver.blockManager.port": "7079x"}}),
+			wantErrMsg: `invalid port "7079x" in spec.sparkConf[spark.driver.blockManager.port]`,
+		},
+		{
+			name: "unparseable core limit",
+			app: func() *v1beta2.SparkApplication {
+				app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala})
+				app.Spec.Driver.CoreLimit = &coreLimit
+				return app
+			}(),
+			wantErrMsg: `invalid spec.driver.coreLimit "two"`,
+		},
+		{
+			name:       "unparseable cores",
+			app:        testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{"spark.driver.cores": "many"}}),
+			wantErrMsg: `invalid spec.sparkConf[spark.driver.cores] "many"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			_, err := Create(tt.app, resolver.Resolve(tt.app), map[string]string{}, "test-app-driver-conf-map", "", kubeClient, nil, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErrMsg)
+			pods, listErr := kubeClient.CoreV1().Pods("default").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, listErr)
+			assert.Empty(t, pods.Items)
+		})
+	}
+}
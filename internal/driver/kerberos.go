@@ -0,0 +1,107 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// kerberosResources holds the volumes mounted by handleKerberoCreds together with the Secrets that back them and
+// have to exist before the driver pod is created
+type kerberosResources struct {
+	secrets []*apiv1.Secret
+	volumes []apiv1.Volume
+}
+
+// buildKerberosResources mirrors the KerberosConfDriverFeatureStep and DriverKubernetesCredentialsFeatureStep of
+// spark-submit: krb5.conf from a ConfigMap, the delegation token secret and the driver's OAuth token. Properties naming
+// files of the submission server are rejected by validation, it is shared by every tenant
+func buildKerberosResources(app *v1beta2.SparkApplication, driverPodName string) (kerberosResources, error) {
+	var resources kerberosResources
+	sparkConf := app.Spec.SparkConf
+	namespace := common.GetAppNamespace(app)
+
+	//krb5.conf from an existing ConfigMap of the application namespace
+	if krb5ConfigMapName := sparkConf[common.SparkKerberosKrb5ConfigMapKey]; krb5ConfigMapName != "" {
+		resources.volumes = append(resources.volumes, apiv1.Volume{
+			Name: KerberosFileVolume,
+			VolumeSource: apiv1.VolumeSource{
+				ConfigMap: &apiv1.ConfigMapVolumeSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: krb5ConfigMapName},
+				},
+			},
+		})
+	}
+
+	//Delegation tokens stored in an existing secret
+	tokenSecretName := sparkConf[KerberosTokenSecretName]
+	tokenSecretItemKey := sparkConf[KerberosTokenSecretItemKey]
+	if (tokenSecretName == "") != (tokenSecretItemKey == "") {
+		return resources, fmt.Errorf("%s and %s must be specified together", KerberosTokenSecretName, KerberosTokenSecretItemKey)
+	}
+	if tokenSecretName != "" {
+		resources.volumes = append(resources.volumes, apiv1.Volume{
+			Name: HadoopSecretVolume,
+			VolumeSource: apiv1.VolumeSource{
+				Secret: &apiv1.SecretVolumeSource{SecretName: tokenSecretName},
+			},
+		})
+	}
+
+	//Keytab, only from the image or a mounted secret through a local:// URI
+	if (sparkConf[common.SparkKerberosKeytabKey] == "") != (sparkConf[common.SparkKerberosPrincipalKey] == "") {
+		return resources, fmt.Errorf("%s and %s must be specified together", common.SparkKerberosKeytabKey, common.SparkKerberosPrincipalKey)
+	}
+
+	//Kubernetes credentials of the driver, passed inline in sparkConf
+	if checkMountingKubernetesCredentials(sparkConf) {
+		secret := &apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            driverPodName + KubernetesCredentialsSecretExtension,
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{*common.GetConfigMapOwnerReference(app)},
+			},
+			Data: map[string][]byte{common.KubernetesOAuthTokenSecretKey: []byte(sparkConf[common.SparkDriverOAuthTokenKey])},
+		}
+		resources.secrets = append(resources.secrets, secret)
+		resources.volumes = append(resources.volumes, apiv1.Volume{
+			Name: KubernetesCredentials,
+			VolumeSource: apiv1.VolumeSource{
+				Secret: &apiv1.SecretVolumeSource{SecretName: secret.Name},
+			},
+		})
+	}
+	return resources, nil
+}
+
+// createKerberosResources creates or updates the Secrets returned by buildKerberosResources
+func createKerberosResources(resources kerberosResources, kubeClient kubernetes.Interface) error {
+	for _, secret := range resources.secrets {
+		log.Printf("Creating credentials Secret %s in namespace %s", secret.Name, secret.Namespace)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			existing, err := kubeClient.CoreV1().Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+			if apiErrors.IsNotFound(err) {
+				_, createErr := kubeClient.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+				return createErr
+			}
+			if err != nil {
+				return err
+			}
+			existing.Data = secret.Data
+			_, updateErr := kubeClient.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), existing, metav1.UpdateOptions{})
+			return updateErr
+		})
+		if err != nil {
+			return fmt.Errorf("error while creating secret %s: %w", secret.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"context"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateKerberosConfigMapVolume(t *testing.T) {
+	pod, _ := renderDriverPod(t, testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		common.SparkKerberosKrb5ConfigMapKey: "krb5-conf",
+	}}))
+
+	volume := findVolume(pod, KerberosFileVolume)
+	require.NotNil(t, volume)
+	require.NotNil(t, volume.ConfigMap)
+	assert.Equal(t, "krb5-conf", volume.ConfigMap.Name)
+
+	volumeMount := findVolumeMount(pod.Spec.Containers[0], KerberosFileVolume)
+	require.NotNil(t, volumeMount)
+	assert.Equal(t, "/etc/krb5.conf", volumeMount.MountPath)
+	assert.Equal(t, KerberosFileName, volumeMount.SubPath)
+	assertMountsHaveVolumes(t, pod)
+}
+
+func TestCreateKerberosDelegationToken(t *testing.T) {
+	pod, _ := renderDriverPod(t, testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		KerberosTokenSecretName:    "hadoop-tokens",
+		KerberosTokenSecretItemKey: "hadoop.token",
+	}}))
+	driverContainer := pod.Spec.Containers[0]
+
+	tokenVolume := findVolume(pod, HadoopSecretVolume)
+	require.NotNil(t, tokenVolume)
+	assert.Equal(t, "hadoop-tokens", tokenVolume.Secret.SecretName)
+	tokenMount := findVolumeMount(driverContainer, HadoopSecretVolume)
+	require.NotNil(t, tokenMount)
+	assert.Equal(t, "/mnt/secrets/hadoop-credentials", tokenMount.MountPath)
+	tokenEnv := findEnvVar(driverContainer, KerberosHadoopSecretFilePathKey)
+	require.NotNil(t, tokenEnv)
+	assert.Equal(t, "/mnt/secrets/hadoop-credentials/hadoop.token", tokenEnv.Value)
+	assertMountsHaveVolumes(t, pod)
+}
+
+func TestCreateKerberosLocalKeytab(t *testing.T) {
+	_, kubeClient := renderDriverPod(t, testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		"spark.kerberos.keytab":    "local:///opt/keytabs/user.keytab",
+		"spark.kerberos.principal": "user@EXAMPLE.COM",
+	}}))
+
+	secrets, err := kubeClient.CoreV1().Secrets("default").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, secrets.Items)
+}
+
+func TestCreateKubernetesCredentials(t *testing.T) {
+	pod, kubeClient := renderDriverPod(t, testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: map[string]string{
+		"spark.kubernetes.authenticate.driver.oauthToken": "token-data",
+	}}))
+
+	secret, err := kubeClient.CoreV1().Secrets("default").Get(context.TODO(), "test-app-driver"+KubernetesCredentialsSecretExtension, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"oauth-token": []byte("token-data")}, secret.Data)
+
+	volume := findVolume(pod, KubernetesCredentials)
+	require.NotNil(t, volume)
+	assert.Equal(t, secret.Name, volume.Secret.SecretName)
+	volumeMount := findVolumeMount(pod.Spec.Containers[0], KubernetesCredentials)
+	require.NotNil(t, volumeMount)
+	assert.Equal(t, KubernetesCredentialsVolumeMountPath, volumeMount.MountPath)
+	assertMountsHaveVolumes(t, pod)
+}
+
+func TestCreateKerberosInvalidConfiguration(t *testing.T) {
+	tests := []struct {
+		name      string
+		sparkConf map[string]string
+		wantErr   string
+	}{
+		{
+			name:      "token secret without item key",
+			sparkConf: map[string]string{KerberosTokenSecretName: "hadoop-tokens"},
+			wantErr:   "must be specified together",
+		},
+		{
+			name:      "keytab without principal",
+			sparkConf: map[string]string{"spark.kerberos.keytab": "local:///opt/user.keytab"},
+			wantErr:   "must be specified together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: tt.sparkConf})
+			_, err := Create(app, resolver.Resolve(app), map[string]string{}, "test-app-driver-conf-map", "", kubeClient, nil, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			pods, listErr := kubeClient.CoreV1().Pods("default").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, listErr)
+			assert.Empty(t, pods.Items)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package driver
 
 import (
 	"bytes"
+	"nativesubmit/internal/resolver"
 	"strconv"
 	"strings"
 
@@ -10,7 +11,9 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 )
 
-func handleLocalDirsFeatureStep(app *v1beta2.SparkApplication, resolvedLocalDirs []string, driverPodVolumes *[]apiv1.Volume, volumeMounts *[]apiv1.VolumeMount, envVariables *[]apiv1.EnvVar, appSpecVolumeMounts []apiv1.VolumeMount, appSpecVolumes []apiv1.Volume) error {
+// handleLocalDirsFeatureStep mounts the local directories of the driver: the spark-local-dir- volume mounts, or else
+// those of the SPARK_LOCAL_DIRS driver environment variable or spark.local.dir, or else a default emptyDir
+func handleLocalDirsFeatureStep(app *v1beta2.SparkApplication, driverConfig resolver.RoleConfig, driverPodVolumes *[]apiv1.Volume, volumeMounts *[]apiv1.VolumeMount, envVariables *[]apiv1.EnvVar, appSpecVolumeMounts []apiv1.VolumeMount, appSpecVolumes []apiv1.Volume) error {
 	//Value of this variable is set to SPARK_LOCAL_DIRS environment variable
 	var localDirsList bytes.Buffer
 	sparkConfKeyValuePairs := app.Spec.SparkConf
@@ -23,7 +26,7 @@ func handleLocalDirsFeatureStep(app *v1beta2.SparkApplication, resolvedLocalDirs
 	if len(appSpecVolumeMounts) > 0 {
 		length := len(appSpecVolumeMounts)
 		for index, driverContainerVolumeMount := range appSpecVolumeMounts {
-			if strings.Contains(driverContainerVolumeMount.Name, LocalStoragePrefix) {
+			if strings.HasPrefix(driverContainerVolumeMount.Name, LocalStoragePrefix) {
 				//Mount the volume
 				mountLocalDir(localDirTmpFSFlagExists, localDirTmpFSFlag, driverPodVolumes, volumeMounts, driverContainerVolumeMount)
 				//concatenate to SPARK_LOCAL_DIRS list and increment the index
@@ -37,22 +40,12 @@ func handleLocalDirsFeatureStep(app *v1beta2.SparkApplication, resolvedLocalDirs
 		}
 
 	}
-	//Only if there are no local directory volume mounts
+	//Only if there are no local directory volume mounts, the local directories of SPARK_LOCAL_DIRS or spark.local.dir
+	//are mounted, as resolved from sparkConf and spark-defaults.conf
 	if localDirIndex == 1 {
-		if len(resolvedLocalDirs) > 0 {
-			//Logic is to check if local directories specified in SPARK_LOCAL_DIRS or spark.local.dir of SparkConf section
-			localDirsList, localDirIndex = handleLocalDirsMounting(resolvedLocalDirs, localDirIndex, localDirTmpFSFlagExists, localDirTmpFSFlag, driverPodVolumes, volumeMounts, localDirsList, mountedPaths)
-		} else {
-			if sparkConfKeyValuePairs != nil {
-				sparkLocalDirectoryList, sparkLocalDirectoryListExists := sparkConfKeyValuePairs["spark.local.dir"]
-				if sparkLocalDirectoryListExists {
-					localDirs := strings.Split(sparkLocalDirectoryList, ",")
-					if len(localDirs) > 0 {
-						localDirsList, localDirIndex = handleLocalDirsMounting(localDirs, localDirIndex, localDirTmpFSFlagExists, localDirTmpFSFlag, driverPodVolumes, volumeMounts, localDirsList, mountedPaths)
-					}
-				}
-			}
-
+		localDirs := driverConfig.LocalDirs
+		if localDirs.Source == resolver.SourceSparkConf || localDirs.Source == resolver.SourceSparkDefaults {
+			localDirsList, localDirIndex = handleLocalDirsMounting(strings.Split(localDirs.Value, ","), localDirIndex, localDirTmpFSFlagExists, localDirTmpFSFlag, driverPodVolumes, volumeMounts, localDirsList, mountedPaths)
 		}
 	}
 	//No local directory exists, creating default local directory
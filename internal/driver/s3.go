@@ -0,0 +1,121 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Credentials are the static credentials used to sign requests to S3 compatible object storage
+type s3Credentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// getS3Credentials reads the s3a credentials from sparkConf. Only when the operator opted in with
+// PodTemplateS3ServerCredentialsEnvVar does it fall back to the standard AWS environment variables of the server.
+func getS3Credentials(sparkConf map[string]string) s3Credentials {
+	credentials := s3Credentials{
+		accessKey:    sparkConf[S3AccessKey],
+		secretKey:    sparkConf[S3SecretKey],
+		sessionToken: sparkConf[S3SessionToken],
+	}
+	if credentials.accessKey == "" && os.Getenv(PodTemplateS3ServerCredentialsEnvVar) == "true" {
+		credentials = s3Credentials{
+			accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	return credentials
+}
+
+func getS3Region(sparkConf map[string]string) string {
+	if region := sparkConf[S3Region]; region != "" {
+		return region
+	}
+	return DefaultS3Region
+}
+
+// getS3Endpoint returns the s3a endpoint, by default the AWS one of the region
+func getS3Endpoint(sparkConf map[string]string) (*url.URL, error) {
+	endpoint := sparkConf[S3Endpoint]
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("s3.%s.amazonaws.com", getS3Region(sparkConf))
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid %s %q", S3Endpoint, endpoint)
+	}
+	return endpointURL, nil
+}
+
+// getS3BucketHost returns the host a bucket is addressed at, the endpoint itself with path style access as most S3
+// compatible stores such as MinIO expect, or else the bucket sub-domain of the endpoint
+func getS3BucketHost(bucket string, endpointURL *url.URL, sparkConf map[string]string) *url.URL {
+	if sparkConf[S3PathStyleAccess] == "true" {
+		return &url.URL{Scheme: endpointURL.Scheme, Host: endpointURL.Host}
+	}
+	return &url.URL{Scheme: endpointURL.Scheme, Host: bucket + DotSeparator + endpointURL.Host}
+}
+
+// newS3Client returns a client of the s3a endpoint, signing requests with the s3a credentials when there are some
+func newS3Client(endpointURL *url.URL, sparkConf map[string]string) (*minio.Client, error) {
+	bucketLookup := minio.BucketLookupDNS
+	if sparkConf[S3PathStyleAccess] == "true" {
+		bucketLookup = minio.BucketLookupPath
+	}
+	s3Credentials := getS3Credentials(sparkConf)
+	return minio.New(endpointURL.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(s3Credentials.accessKey, s3Credentials.secretKey, s3Credentials.sessionToken),
+		Secure:       endpointURL.Scheme == "https",
+		Region:       getS3Region(sparkConf),
+		BucketLookup: bucketLookup,
+	})
+}
+
+// s3TemplateFetcher downloads pod templates addressed as s3://bucket/key or s3a://bucket/key
+type s3TemplateFetcher struct{}
+
+func (f s3TemplateFetcher) Fetch(uri *url.URL, targetFile string, sparkConf map[string]string) error {
+	bucket := uri.Host
+	key := strings.TrimPrefix(uri.Path, ForwardSlash)
+	if bucket == "" || key == "" {
+		return fmt.Errorf("invalid object storage URI %s, expected %s://bucket/key", uri.String(), uri.Scheme)
+	}
+	endpointURL, err := getS3Endpoint(sparkConf)
+	if err != nil {
+		return err
+	}
+	if err := checkTemplateHostAllowed(getS3BucketHost(bucket, endpointURL, sparkConf)); err != nil {
+		return err
+	}
+	client, err := newS3Client(endpointURL, sparkConf)
+	if err != nil {
+		return fmt.Errorf("failed to create the client of %s: %w", endpointURL.Redacted(), err)
+	}
+	fetchTimeout, err := getFetchTimeout(sparkConf)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	object, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch s3://%s/%s: %w", bucket, key, err)
+	}
+	defer object.Close()
+	if _, err := downloadFileFromURL(object, targetFile); err != nil {
+		return fmt.Errorf("failed to fetch s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
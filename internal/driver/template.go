@@ -0,0 +1,53 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// mergePodTemplate merges the generated driver pod into the pod template the same way spark-submit builds on top of
+// spark.kubernetes.driver.podTemplateFile: the template is the base and the generated pod is applied to it as a
+// strategic merge patch. Containers, volumes, env vars and ports are merged by name, maps such as labels, annotations
+// and the node selector are merged by key, and template tolerations are kept next to the generated ones.
+func mergePodTemplate(templatePod apiv1.Pod, generatedPod apiv1.Pod) (apiv1.Pod, error) {
+	var mergedPod apiv1.Pod
+	//Tolerations have no merge key, so the generated list would replace the template one
+	if len(templatePod.Spec.Tolerations) > 0 {
+		tolerations := append([]apiv1.Toleration{}, templatePod.Spec.Tolerations...)
+		for _, toleration := range generatedPod.Spec.Tolerations {
+			if !containsToleration(tolerations, toleration) {
+				tolerations = append(tolerations, toleration)
+			}
+		}
+		generatedPod.Spec.Tolerations = tolerations
+	}
+
+	templateJSON, err := json.Marshal(templatePod)
+	if err != nil {
+		return mergedPod, fmt.Errorf("failed to encode pod template: %w", err)
+	}
+	generatedJSON, err := json.Marshal(generatedPod)
+	if err != nil {
+		return mergedPod, fmt.Errorf("failed to encode driver pod: %w", err)
+	}
+	mergedJSON, err := strategicpatch.StrategicMergePatch(templateJSON, generatedJSON, apiv1.Pod{})
+	if err != nil {
+		return mergedPod, fmt.Errorf("failed to merge driver pod into pod template: %w", err)
+	}
+	if err := json.Unmarshal(mergedJSON, &mergedPod); err != nil {
+		return mergedPod, fmt.Errorf("failed to decode merged driver pod: %w", err)
+	}
+	return mergedPod, nil
+}
+
+func containsToleration(tolerations []apiv1.Toleration, toleration apiv1.Toleration) bool {
+	for _, existing := range tolerations {
+		if existing.MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
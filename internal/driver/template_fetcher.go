@@ -0,0 +1,207 @@
+package driver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TemplateFetcher downloads a pod template file with a given URI scheme to targetFile
+type TemplateFetcher interface {
+	Fetch(uri *url.URL, targetFile string, sparkConf map[string]string) error
+}
+
+// TemplateFetcherFunc adapts a function to a TemplateFetcher
+type TemplateFetcherFunc func(uri *url.URL, targetFile string, sparkConf map[string]string) error
+
+func (f TemplateFetcherFunc) Fetch(uri *url.URL, targetFile string, sparkConf map[string]string) error {
+	return f(uri, targetFile, sparkConf)
+}
+
+var (
+	customTemplateFetchersMutex sync.RWMutex
+	customTemplateFetchers      = map[string]TemplateFetcher{}
+)
+
+// RegisterTemplateFetcher registers a fetcher for pod template files with the given URI scheme, e.g. gs or abfs.
+// A registered fetcher takes precedence over the built-in one for the same scheme.
+func RegisterTemplateFetcher(scheme string, fetcher TemplateFetcher) {
+	customTemplateFetchersMutex.Lock()
+	defer customTemplateFetchersMutex.Unlock()
+	customTemplateFetchers[strings.ToLower(scheme)] = fetcher
+}
+
+// getTemplateFetcher returns the fetcher for a URI scheme, the built-in ones being http(s), configmap and s3(a).
+// The configmap fetcher only reads ConfigMaps of the application namespace.
+func getTemplateFetcher(scheme string, namespace string, kubeClient kubernetes.Interface) (TemplateFetcher, bool) {
+	customTemplateFetchersMutex.RLock()
+	fetcher, exists := customTemplateFetchers[scheme]
+	customTemplateFetchersMutex.RUnlock()
+	if exists {
+		return fetcher, true
+	}
+	switch scheme {
+	case "http", "https":
+		return TemplateFetcherFunc(fetchHTTPTemplate), true
+	case ConfigMapScheme:
+		return configMapTemplateFetcher{kubeClient: kubeClient, namespace: namespace}, true
+	case "s3", "s3a":
+		return s3TemplateFetcher{}, true
+	}
+	return nil, false
+}
+
+func fetchHTTPTemplate(uri *url.URL, targetFile string, sparkConf map[string]string) error {
+	_, err := doFetchFile(uri.String(), filepath.Dir(targetFile), filepath.Base(targetFile), sparkConf)
+	return err
+}
+
+// configMapTemplateFetcher reads a pod template from a ConfigMap key addressed as configmap://namespace/name/key,
+// where namespace must be the namespace of the application
+type configMapTemplateFetcher struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+func (f configMapTemplateFetcher) Fetch(uri *url.URL, targetFile string, sparkConf map[string]string) error {
+	if f.kubeClient == nil {
+		return fmt.Errorf("no kubernetes client available to read %s", uri.String())
+	}
+	namespace := uri.Host
+	pathParts := strings.Split(strings.Trim(uri.Path, ForwardSlash), ForwardSlash)
+	if namespace == "" || len(pathParts) != 2 || pathParts[0] == "" || pathParts[1] == "" {
+		return fmt.Errorf("invalid configmap template URI %s, expected %s://namespace/name/key", uri.String(), ConfigMapScheme)
+	}
+	if namespace != f.namespace {
+		return fmt.Errorf("configmap template %s must be in the application namespace %s", uri.String(), f.namespace)
+	}
+	name, key := pathParts[0], pathParts[1]
+	configMap, err := f.kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
+	}
+	if data, exists := configMap.Data[key]; exists {
+		return os.WriteFile(targetFile, []byte(data), 0600)
+	}
+	if data, exists := configMap.BinaryData[key]; exists {
+		return os.WriteFile(targetFile, data, 0600)
+	}
+	return fmt.Errorf("key %s not found in configmap %s/%s", key, namespace, name)
+}
+
+// templateCacheEntry is a downloaded pod template
+type templateCacheEntry struct {
+	content   []byte
+	fetchedAt time.Time
+}
+
+// templateCache keeps remote pod templates in memory so the same template is not downloaded on every submission. A
+// template is served until it is older than the TTL, without asking the origin whether it changed.
+type templateCache struct {
+	mutex      sync.Mutex
+	entries    map[string]templateCacheEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+var podTemplateCache = newTemplateCache()
+
+func newTemplateCache() *templateCache {
+	return &templateCache{entries: make(map[string]templateCacheEntry), maxEntries: PodTemplateCacheMaxEntries, now: time.Now}
+}
+
+// get returns a cached template younger than ttl, dropping it once expired
+func (c *templateCache) get(key string, ttl time.Duration) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	if c.now().Sub(entry.fetchedAt) > ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.content, true
+}
+
+// put caches a template, first dropping the expired templates and, when the cache is still full, the oldest one
+func (c *templateCache) put(key string, content []byte, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	var oldestKey string
+	for cachedKey, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) > ttl {
+			delete(c.entries, cachedKey)
+		} else if oldestKey == "" || entry.fetchedAt.Before(c.entries[oldestKey].fetchedAt) {
+			oldestKey = cachedKey
+		}
+	}
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = templateCacheEntry{content: content, fetchedAt: now}
+}
+
+// templateCacheKey identifies a remote template as a hash of its URI, the application namespace and the full S3
+// credentials, so that a cached template is only served to submissions of the same namespace and credentials.
+func templateCacheKey(uri *url.URL, namespace string, sparkConf map[string]string) string {
+	credentials := getS3Credentials(sparkConf)
+	hash := sha256.New()
+	for _, part := range []string{uri.String(), namespace, credentials.accessKey, credentials.secretKey, credentials.sessionToken} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// getTemplateCacheTTL returns how long a downloaded pod template is reused. It is set by the operator through the
+// environment, 0 disabling the cache.
+func getTemplateCacheTTL() time.Duration {
+	ttl, ttlExists := os.LookupEnv(PodTemplateCacheTTLEnvVar)
+	if !ttlExists {
+		return DefaultPodTemplateCacheTTL
+	}
+	parsedTTL, err := time.ParseDuration(ttl)
+	if err != nil || parsedTTL < 0 {
+		log.Printf("Invalid value %q for %s, using default %v", ttl, PodTemplateCacheTTLEnvVar, DefaultPodTemplateCacheTTL)
+		return DefaultPodTemplateCacheTTL
+	}
+	return parsedTTL
+}
+
+// fetchTemplate downloads a remote pod template to targetFile, serving it from the template cache when possible
+func fetchTemplate(fetcher TemplateFetcher, uri *url.URL, targetFile string, namespace string, sparkConf map[string]string) error {
+	ttl := getTemplateCacheTTL()
+	cacheKey := templateCacheKey(uri, namespace, sparkConf)
+	if ttl > 0 {
+		if content, cached := podTemplateCache.get(cacheKey, ttl); cached {
+			log.Printf("Using cached pod template %s", uri.Redacted())
+			return os.WriteFile(targetFile, content, 0600)
+		}
+	}
+	if err := fetcher.Fetch(uri, targetFile, sparkConf); err != nil {
+		return fmt.Errorf("failed to fetch pod template %s: %w", uri.Redacted(), err)
+	}
+	if ttl > 0 {
+		content, err := os.ReadFile(targetFile)
+		if err != nil {
+			return err
+		}
+		podTemplateCache.put(cacheKey, content, ttl)
+		log.Printf("Cached pod template %s for %v", uri.Redacted(), ttl)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package driver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testPodTemplate = `apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    team: data-platform
+spec:
+  containers:
+    - name: spark
+      image: template-image:1.0
+`
+
+// useTemplateCache swaps the package template cache for the duration of a test
+func useTemplateCache(t *testing.T) *templateCache {
+	t.Helper()
+	previous := podTemplateCache
+	podTemplateCache = newTemplateCache()
+	t.Cleanup(func() { podTemplateCache = previous })
+	return podTemplateCache
+}
+
+func TestLoadPodFromTemplateS3(t *testing.T) {
+	useTemplateCache(t)
+	t.Setenv(PodTemplateAllowedHostsEnvVar, "127.0.0.1")
+	requestCount := 0
+	objectStore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if r.URL.Path != "/templates/driver/pod template.yaml" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio-access/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// The object metadata headers S3 sends along with the content
+		w.Header().Set("Last-Modified", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		w.Header().Set("ETag", `"template-etag"`)
+		w.Write([]byte(testPodTemplate))
+	}))
+	defer objectStore.Close()
+	sparkConf := map[string]string{
+		S3Endpoint:        objectStore.URL,
+		S3AccessKey:       "minio-access",
+		S3SecretKey:       "minio-secret",
+		S3PathStyleAccess: "true",
+	}
+
+	for i := 0; i < 2; i++ {
+		pod, err := loadPodFromTemplate("s3a://templates/driver/pod%20template.yaml", "spark", "default", sparkConf, nil)
+		require.NoError(t, err)
+		require.Len(t, pod.Spec.Containers, 1)
+		assert.Equal(t, "template-image:1.0", pod.Spec.Containers[0].Image)
+		assert.Equal(t, "data-platform", pod.Labels["team"])
+	}
+	assert.Equal(t, 1, requestCount)
+
+	_, err := loadPodFromTemplate("s3a://templates/missing.yaml", "spark", "default", sparkConf, nil)
+	assert.Error(t, err)
+}
+
+func TestLoadPodFromTemplateConfigMap(t *testing.T) {
+	useTemplateCache(t)
+	kubeClient := fake.NewSimpleClientset(&apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-templates", Namespace: "spark-system"},
+		Data:       map[string]string{"driver.yaml": testPodTemplate},
+	})
+	t.Setenv(PodTemplateCacheTTLEnvVar, "0")
+	sparkConf := map[string]string{}
+
+	pod, err := loadPodFromTemplate("configmap://spark-system/pod-templates/driver.yaml", "spark", "spark-system", sparkConf, kubeClient)
+	require.NoError(t, err)
+	assert.Equal(t, "template-image:1.0", pod.Spec.Containers[0].Image)
+	assert.Empty(t, podTemplateCache.entries)
+
+	for _, invalidURI := range []string{
+		"configmap://spark-system/pod-templates/executor.yaml",
+		"configmap://spark-system/missing/driver.yaml",
+		"configmap://spark-system/pod-templates",
+	} {
+		_, err := loadPodFromTemplate(invalidURI, "spark", "spark-system", sparkConf, kubeClient)
+		assert.Error(t, err, invalidURI)
+	}
+
+	_, err = loadPodFromTemplate("configmap://spark-system/pod-templates/driver.yaml", "spark", "tenant-a", sparkConf, kubeClient)
+	assert.ErrorContains(t, err, "application namespace tenant-a")
+}
+
+func TestLoadPodFromTemplateUnsupportedScheme(t *testing.T) {
+	for _, templateFile := range []string{"gs://bucket/driver.yaml", "abfs://container@account/driver.yaml"} {
+		_, err := loadPodFromTemplate(templateFile, "spark", "default", map[string]string{}, nil)
+		assert.ErrorContains(t, err, "unsupported scheme")
+	}
+}
+
+func TestRegisterTemplateFetcher(t *testing.T) {
+	useTemplateCache(t)
+	RegisterTemplateFetcher("gs", TemplateFetcherFunc(func(uri *url.URL, targetFile string, sparkConf map[string]string) error {
+		return os.WriteFile(targetFile, []byte(testPodTemplate), 0600)
+	}))
+	defer func() {
+		customTemplateFetchersMutex.Lock()
+		delete(customTemplateFetchers, "gs")
+		customTemplateFetchersMutex.Unlock()
+	}()
+
+	pod, err := loadPodFromTemplate("gs://bucket/driver.yaml", "spark", "default", map[string]string{}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "template-image:1.0", pod.Spec.Containers[0].Image)
+}
+
+func TestTemplateCache(t *testing.T) {
+	cache := newTemplateCache()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.put("s3a://bucket/driver.yaml", []byte(testPodTemplate), time.Minute)
+	content, cached := cache.get("s3a://bucket/driver.yaml", time.Minute)
+	assert.True(t, cached)
+	assert.Equal(t, testPodTemplate, string(content))
+
+	now = now.Add(2 * time.Minute)
+	_, cached = cache.get("s3a://bucket/driver.yaml", time.Minute)
+	assert.False(t, cached)
+	assert.Empty(t, cache.entries)
+}
+
+func TestTemplateCacheBounded(t *testing.T) {
+	cache := newTemplateCache()
+	cache.maxEntries = 2
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.put("expired", []byte("expired"), time.Minute)
+	now = now.Add(2 * time.Minute)
+	cache.put("oldest", []byte("oldest"), time.Minute)
+	assert.Len(t, cache.entries, 1, "expired templates are swept")
+
+	now = now.Add(time.Second)
+	cache.put("newer", []byte("newer"), time.Minute)
+	now = now.Add(time.Second)
+	cache.put("newest", []byte("newest"), time.Minute)
+	assert.Len(t, cache.entries, 2)
+	_, cached := cache.get("oldest", time.Minute)
+	assert.False(t, cached, "the oldest template is evicted once the cache is full")
+	_, cached = cache.get("newest", time.Minute)
+	assert.True(t, cached)
+}
+
+func TestGetTemplateCacheTTL(t *testing.T) {
+	assert.Equal(t, DefaultPodTemplateCacheTTL, getTemplateCacheTTL())
+	t.Setenv(PodTemplateCacheTTLEnvVar, "1m")
+	assert.Equal(t, time.Minute, getTemplateCacheTTL())
+	t.Setenv(PodTemplateCacheTTLEnvVar, "0")
+	assert.Equal(t, time.Duration(0), getTemplateCacheTTL())
+	t.Setenv(PodTemplateCacheTTLEnvVar, "soon")
+	assert.Equal(t, DefaultPodTemplateCacheTTL, getTemplateCacheTTL())
+}
+
+func TestTemplateCacheKey(t *testing.T) {
+	uri, err := url.Parse("s3a://bucket/driver.yaml")
+	require.NoError(t, err)
+	credentials := map[string]string{S3AccessKey: "tenant-a", S3SecretKey: "secret-a", S3SessionToken: "token-a"}
+	key := templateCacheKey(uri, "tenant-a", credentials)
+
+	tests := []struct {
+		name      string
+		namespace string
+		sparkConf map[string]string
+	}{
+		{name: "other namespace", namespace: "tenant-b", sparkConf: credentials},
+		{name: "other access key", namespace: "tenant-a", sparkConf: map[string]string{S3AccessKey: "tenant-b", S3SecretKey: "secret-a", S3SessionToken: "token-a"}},
+		{name: "other secret key", namespace: "tenant-a", sparkConf: map[string]string{S3AccessKey: "tenant-a", S3SecretKey: "guessed", S3SessionToken: "token-a"}},
+		{name: "other session token", namespace: "tenant-a", sparkConf: map[string]string{S3AccessKey: "tenant-a", S3SecretKey: "secret-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, key, templateCacheKey(uri, tt.namespace, tt.sparkConf))
+		})
+	}
+	assert.Equal(t, key, templateCacheKey(uri, "tenant-a", credentials))
+	assert.NotContains(t, key, "secret-a")
+}
+
+func TestGetS3CredentialsServerFallback(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "server-access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "server-secret")
+
+	tests := []struct {
+		name      string
+		optIn     string
+		sparkConf map[string]string
+		want      string
+	}{
+		{name: "application credentials", optIn: "true", sparkConf: map[string]string{S3AccessKey: "app-access"}, want: "app-access"},
+		{name: "server credentials without opt-in", sparkConf: map[string]string{}, want: ""},
+		{name: "server credentials with opt-in", optIn: "true", sparkConf: map[string]string{}, want: "server-access"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PodTemplateS3ServerCredentialsEnvVar, tt.optIn)
+			assert.Equal(t, tt.want, getS3Credentials(tt.sparkConf).accessKey)
+		})
+	}
+}
+
+func TestCheckTemplateHostAllowed(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedHosts string
+		templateURL  string
+		wantErr      bool
+	}{
+		{"no allow-list", "", "https://templates.example.com/driver.yaml", true},
+		{"exact host", "templates.example.com", "https://templates.example.com:8443/driver.yaml", false},
+		{"host is case insensitive", "Templates.Example.com", "https://TEMPLATES.example.com/driver.yaml", false},
+		{"wildcard", "*.s3.amazonaws.com", "https://bucket.s3.amazonaws.com/driver.yaml", false},
+		{"wildcard does not match the bare domain", "*.example.com", "https://example.com/driver.yaml", true},
+		{"suffix is not a subdomain", "example.com", "https://evil-example.com/driver.yaml", true},
+		{"metadata endpoint", "templates.example.com", "http://169.254.169.254/latest/meta-data", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PodTemplateAllowedHostsEnvVar, tt.allowedHosts)
+			templateURL, err := url.Parse(tt.templateURL)
+			require.NoError(t, err)
+
+			err = checkTemplateHostAllowed(templateURL)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadPodFromTemplateHTTPHardening(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/driver.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testPodTemplate))
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/external", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	})
+	mux.HandleFunc("/not-found", http.NotFound)
+	templateServer := httptest.NewServer(mux)
+	defer templateServer.Close()
+	t.Setenv(PodTemplateCacheTTLEnvVar, "0")
+	noCache := map[string]string{}
+
+	t.Run("host not allowed", func(t *testing.T) {
+		t.Setenv(PodTemplateAllowedHostsEnvVar, "")
+		_, err := loadPodFromTemplate(templateServer.URL+"/driver.yaml", "spark", "default", noCache, nil)
+		assert.ErrorContains(t, err, "not allowed")
+	})
+
+	t.Setenv(PodTemplateAllowedHostsEnvVar, "127.0.0.1")
+	t.Run("allowed host", func(t *testing.T) {
+		pod, err := loadPodFromTemplate(templateServer.URL+"/driver.yaml", "spark", "default", noCache, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "template-image:1.0", pod.Spec.Containers[0].Image)
+	})
+	t.Run("template too large", func(t *testing.T) {
+		t.Setenv(PodTemplateMaxSizeEnvVar, "16")
+		_, err := loadPodFromTemplate(templateServer.URL+"/driver.yaml", "spark", "default", noCache, nil)
+		assert.ErrorContains(t, err, "maximum pod template size")
+	})
+	t.Run("redirect loop", func(t *testing.T) {
+		_, err := loadPodFromTemplate(templateServer.URL+"/loop", "spark", "default", noCache, nil)
+		assert.ErrorContains(t, err, "redirects")
+	})
+	t.Run("redirect to host not allowed", func(t *testing.T) {
+		_, err := loadPodFromTemplate(templateServer.URL+"/external", "spark", "default", noCache, nil)
+		assert.ErrorContains(t, err, "not allowed")
+	})
+	t.Run("error status", func(t *testing.T) {
+		_, err := loadPodFromTemplate(templateServer.URL+"/not-found", "spark", "default", noCache, nil)
+		assert.ErrorContains(t, err, "404")
+	})
+}
+
+func TestLoadPodFromTemplateLocalFile(t *testing.T) {
+	tempRoot := t.TempDir()
+	t.Setenv("TMPDIR", tempRoot)
+	templateDir := t.TempDir()
+	t.Setenv(PodTemplateAllowedDirsEnvVar, templateDir)
+	templatePath := filepath.Join(templateDir, "driver.yaml")
+	require.NoError(t, os.WriteFile(templatePath, []byte(testPodTemplate), 0600))
+
+	pod, err := loadPodFromTemplate("file://"+templatePath, "spark", "default", map[string]string{}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "template-image:1.0", pod.Spec.Containers[0].Image)
+	_, err = os.Stat(templatePath)
+	assert.NoError(t, err, "local template must not be moved")
+	leftovers, err := os.ReadDir(tempRoot)
+	require.NoError(t, err)
+	assert.Empty(t, leftovers, "temp directory must be removed")
+
+	servicePath := filepath.Join(templateDir, "service.yaml")
+	require.NoError(t, os.WriteFile(servicePath, []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: driver\n"), 0600))
+	_, err = loadPodFromTemplate(servicePath, "spark", "default", map[string]string{}, nil)
+	assert.ErrorContains(t, err, "instead of a Pod")
+}
+
+func TestCheckTemplatePathAllowed(t *testing.T) {
+	templateDir := t.TempDir()
+	otherDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "driver.yaml"), []byte(testPodTemplate), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(otherDir, "secret.yaml"), []byte(testPodTemplate), 0600))
+	require.NoError(t, os.Symlink(filepath.Join(otherDir, "secret.yaml"), filepath.Join(templateDir, "link.yaml")))
+
+	tests := []struct {
+		name        string
+		allowedDirs string
+		path        string
+		wantErr     string
+	}{
+		{name: "allowed directory", allowedDirs: templateDir, path: filepath.Join(templateDir, "driver.yaml")},
+		{name: "no allowed directory", allowedDirs: "", path: filepath.Join(templateDir, "driver.yaml"), wantErr: "not in an allowed directory"},
+		{name: "other directory", allowedDirs: templateDir, path: filepath.Join(otherDir, "secret.yaml"), wantErr: "not in an allowed directory"},
+		{name: "path traversal", allowedDirs: templateDir, path: templateDir + "/../" + filepath.Base(otherDir) + "/secret.yaml", wantErr: "not in an allowed directory"},
+		{name: "symbolic link out of the directory", allowedDirs: templateDir, path: filepath.Join(templateDir, "link.yaml"), wantErr: "not in an allowed directory"},
+		{name: "server file", allowedDirs: templateDir, path: "/etc/passwd", wantErr: "not in an allowed directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PodTemplateAllowedDirsEnvVar, tt.allowedDirs)
+			_, err := checkTemplatePathAllowed(tt.path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetFetchTimeout(t *testing.T) {
+	tests := []struct {
+		name      string
+		sparkConf map[string]string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{name: "not set", sparkConf: map[string]string{}, want: 60 * time.Second},
+		{name: "with unit", sparkConf: map[string]string{SparkFilesFetchTimeout: "30s"}, want: 30 * time.Second},
+		{name: "bare seconds", sparkConf: map[string]string{SparkFilesFetchTimeout: "90"}, want: 90 * time.Second},
+		{name: "minutes", sparkConf: map[string]string{SparkFilesFetchTimeout: "2min"}, want: 2 * time.Minute},
+		{name: "invalid", sparkConf: map[string]string{SparkFilesFetchTimeout: "soon"}, wantErr: true},
+		{name: "zero", sparkConf: map[string]string{SparkFilesFetchTimeout: "0s"}, wantErr: true},
+		{name: "negative", sparkConf: map[string]string{SparkFilesFetchTimeout: "-1s"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getFetchTimeout(tt.sparkConf)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, SparkFilesFetchTimeout)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	sourceFile := filepath.Join(t.TempDir(), "source.yaml")
+	require.NoError(t, os.WriteFile(sourceFile, []byte(testPodTemplate), 0600))
+	destDir := t.TempDir()
+
+	copied, err := copyFile(sourceFile, filepath.Join(destDir, "copy.yaml"), false)
+	require.NoError(t, err)
+	content, err := os.ReadFile(copied)
+	require.NoError(t, err)
+	assert.Equal(t, testPodTemplate, string(content))
+	assert.FileExists(t, sourceFile)
+
+	moved, err := copyFile(sourceFile, filepath.Join(destDir, "moved.yaml"), true)
+	require.NoError(t, err)
+	assert.FileExists(t, moved)
+	assert.NoFileExists(t, sourceFile)
+}
@@ -0,0 +1,95 @@
+package driver
+
+import (
+	"flag"
+	"nativesubmit/internal/testutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+func assertGolden(t *testing.T, goldenFile string, pod *apiv1.Pod) {
+	t.Helper()
+	actual, err := yaml.Marshal(pod)
+	require.NoError(t, err)
+	goldenPath := filepath.Join("testdata", goldenFile)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(goldenPath, actual, 0644))
+	}
+	expected, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestCreateDriverPodTemplateMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		containerName string
+		goldenFile    string
+	}{
+		{"named spark container", "spark", "pod-template-merged.golden.yaml"},
+		{"first container by default", "", "pod-template-first-container.golden.yaml"},
+	}
+
+	templatePath, err := filepath.Abs(filepath.Join("testdata", "pod-template.yaml"))
+	require.NoError(t, err)
+	t.Setenv(PodTemplateAllowedDirsEnvVar, filepath.Dir(templatePath))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sparkConf := map[string]string{
+				"spark.kubernetes.driver.podTemplateFile": "file://" + templatePath,
+				"spark.local.dir":                         "/var/data/spark-local",
+			}
+			if tt.containerName != "" {
+				sparkConf["spark.kubernetes.driver.podTemplateContainerName"] = tt.containerName
+			}
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: sparkConf})
+
+			pod, _ := renderDriverPod(t, app)
+
+			assertGolden(t, tt.goldenFile, pod)
+		})
+	}
+}
+
+func TestMergePodTemplate(t *testing.T) {
+	templatePod := apiv1.Pod{
+		Spec: apiv1.PodSpec{
+			Tolerations: []apiv1.Toleration{{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "spark", Effect: apiv1.TaintEffectNoSchedule}},
+			Containers: []apiv1.Container{
+				{Name: "spark-kubernetes-driver", Image: "template-image", Env: []apiv1.EnvVar{{Name: "A", Value: "template"}, {Name: "B", Value: "template"}}},
+				{Name: "sidecar", Image: "sidecar-image"},
+			},
+		},
+	}
+	generatedPod := apiv1.Pod{
+		Spec: apiv1.PodSpec{
+			Tolerations: []apiv1.Toleration{
+				{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "spark", Effect: apiv1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoExecute},
+			},
+			Containers: []apiv1.Container{
+				{Name: "spark-kubernetes-driver", Image: "generated-image", Env: []apiv1.EnvVar{{Name: "B", Value: "generated"}}},
+			},
+		},
+	}
+
+	mergedPod, err := mergePodTemplate(templatePod, generatedPod)
+
+	require.NoError(t, err)
+	require.Len(t, mergedPod.Spec.Containers, 2)
+	driverContainer := mergedPod.Spec.Containers[0]
+	assert.Equal(t, "generated-image", driverContainer.Image)
+	assert.ElementsMatch(t, []apiv1.EnvVar{{Name: "A", Value: "template"}, {Name: "B", Value: "generated"}}, driverContainer.Env)
+	assert.Equal(t, "sidecar", mergedPod.Spec.Containers[1].Name)
+	assert.Len(t, mergedPod.Spec.Tolerations, 2)
+}
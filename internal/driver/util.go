@@ -5,15 +5,20 @@ import (
 	"io"
 	"log"
 	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -27,7 +32,7 @@ func Int32Pointer(a int32) *int32 {
 	return &a
 }
 
-func downloadFile(path string, targetDir string, sparkConf map[string]string) (string, error) {
+func downloadFile(path string, targetDir string, namespace string, sparkConf map[string]string, kubeClient kubernetes.Interface) (string, error) {
 	if path == "" {
 		return "", fmt.Errorf("Pod template file's download path is empty")
 	}
@@ -37,20 +42,18 @@ func downloadFile(path string, targetDir string, sparkConf map[string]string) (s
 	}
 
 	switch uri.Scheme {
-	case "file", "local":
-		return path, nil
-	case "http", "https", "ftp":
-		fname := filepath.Base(uri.Path)
-		localFile, _ := doFetchFile(uri.String(), targetDir, fname, sparkConf)
-		return localFile, nil
-	default:
-		fname := filepath.Base(uri.Path)
-		localFile, err := doFetchFile(uri.String(), targetDir, fname, sparkConf)
-		if err != nil {
-			return "", err
-		}
-		return localFile, nil
+	case "", "file", "local":
+		return checkTemplatePathAllowed(uri.Path)
 	}
+	fetcher, fetcherExists := getTemplateFetcher(strings.ToLower(uri.Scheme), namespace, kubeClient)
+	if !fetcherExists {
+		return "", fmt.Errorf("unsupported scheme %s for pod template file %s", uri.Scheme, uri.Redacted())
+	}
+	localFile := filepath.Join(targetDir, filepath.Base(uri.Path))
+	if err := fetchTemplate(fetcher, uri, localFile, namespace, sparkConf); err != nil {
+		return "", err
+	}
+	return localFile, nil
 }
 func doFetchFile(urlStr string, targetDir string, filename string, conf map[string]string) (string, error) {
 	targetFile := filepath.Join(targetDir, filename)
@@ -60,32 +63,168 @@ func doFetchFile(urlStr string, targetDir string, filename string, conf map[stri
 	}
 
 	switch scheme := parsedURL.Scheme; scheme {
-	case "spark":
-		return "", err
-	case "http", "https", "ftp":
-		fetchTimeoutDuration, _ := time.ParseDuration("5s")
-		fetchTimeout, fetchTimeoutExists := conf["spark.files.fetchTimeout"]
-		if fetchTimeoutExists {
-			fetchTimeoutDuration, _ = time.ParseDuration(fetchTimeout)
+	case "http", "https":
+		if err := checkTemplateHostAllowed(parsedURL); err != nil {
+			return "", err
 		}
-		client := http.Client{
-			Timeout: fetchTimeoutDuration,
+		client, err := newTemplateHTTPClient(conf)
+		if err != nil {
+			return "", err
 		}
 		resp, err := client.Get(urlStr)
 		if err != nil {
 			return "", err
 		}
 		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status %s fetching %s", resp.Status, parsedURL.Redacted())
+		}
 		return downloadFileFromURL(resp.Body, targetFile)
-	case "file":
-		sourceFile := parsedURL.Path
-		return copyFile(sourceFile, targetFile, false)
 	default:
-		return "", err
+		return "", fmt.Errorf("unsupported scheme %s for %s", scheme, parsedURL.Redacted())
+	}
+}
+
+// getFetchTimeout returns spark.files.fetchTimeout, in seconds when it has no unit as in Spark
+func getFetchTimeout(conf map[string]string) (time.Duration, error) {
+	fetchTimeout, fetchTimeoutExists := conf[SparkFilesFetchTimeout]
+	if !fetchTimeoutExists {
+		return DefaultFetchTimeout, nil
+	}
+	fetchTimeoutDuration, err := common.ParseTimeString(fetchTimeout, time.Second)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", SparkFilesFetchTimeout, err)
+	}
+	if fetchTimeoutDuration <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be positive", SparkFilesFetchTimeout, fetchTimeout)
+	}
+	return fetchTimeoutDuration, nil
+}
+
+// getTemplateAllowedHosts returns the hosts pod templates may be downloaded from. The list is set by the operator
+// through the environment rather than sparkConf, so that tenants cannot widen it.
+func getTemplateAllowedHosts() []string {
+	var allowedHosts []string
+	for _, host := range strings.Split(os.Getenv(PodTemplateAllowedHostsEnvVar), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowedHosts = append(allowedHosts, host)
+		}
+	}
+	return allowedHosts
+}
+
+// checkTemplateHostAllowed matches the host of a remote pod template against the allow-list, where an entry is
+// either an exact host name or a *.domain wildcard. Remote templates are rejected when no allow-list is configured.
+func checkTemplateHostAllowed(uri *url.URL) error {
+	host := strings.ToLower(uri.Hostname())
+	for _, allowedHost := range getTemplateAllowedHosts() {
+		if host == allowedHost || (strings.HasPrefix(allowedHost, "*.") && strings.HasSuffix(host, allowedHost[1:])) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %q of pod template %s is not allowed, see %s", host, uri.Redacted(), PodTemplateAllowedHostsEnvVar)
+}
+
+// checkTemplatePathAllowed returns the absolute path of a local pod template when it is inside one of the allowed
+// directories, both as written and with its symbolic links followed. Local templates are rejected when no directory
+// is configured.
+func checkTemplatePathAllowed(path string) (string, error) {
+	notAllowedErr := fmt.Errorf("pod template %s is not in an allowed directory, see %s", path, PodTemplateAllowedDirsEnvVar)
+	absPath, err := filepath.Abs(path)
+	if err != nil || !isInTemplateAllowedDir(absPath) {
+		return "", notAllowedErr
+	}
+	resolvedPath, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve pod template %s: %w", path, err)
 	}
+	if !isInTemplateAllowedDir(resolvedPath) {
+		return "", notAllowedErr
+	}
+	return resolvedPath, nil
+}
+
+// isInTemplateAllowedDir checks an absolute path against the directories set by the operator through the environment
+func isInTemplateAllowedDir(absPath string) bool {
+	for _, dir := range strings.Split(os.Getenv(PodTemplateAllowedDirsEnvVar), ",") {
+		if dir = strings.TrimSpace(dir); dir == "" {
+			continue
+		}
+		candidates := []string{dir}
+		if resolvedDir, err := filepath.EvalSymlinks(dir); err == nil {
+			candidates = append(candidates, resolvedDir)
+		}
+		for _, candidate := range candidates {
+			absDir, err := filepath.Abs(candidate)
+			if err != nil {
+				continue
+			}
+			relPath, err := filepath.Rel(absDir, absPath)
+			if err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getTemplateMaxSize returns the maximum size in bytes of a pod template
+func getTemplateMaxSize() int64 {
+	maxSize, maxSizeExists := os.LookupEnv(PodTemplateMaxSizeEnvVar)
+	if !maxSizeExists {
+		return DefaultPodTemplateMaxSize
+	}
+	parsedMaxSize, err := strconv.ParseInt(maxSize, 10, 64)
+	if err != nil || parsedMaxSize <= 0 {
+		log.Printf("Invalid value %q for %s, using default %d", maxSize, PodTemplateMaxSizeEnvVar, DefaultPodTemplateMaxSize)
+		return DefaultPodTemplateMaxSize
+	}
+	return parsedMaxSize
+}
+
+// newTemplateHTTPClient returns the client used to download pod templates. It follows a limited number of redirects,
+// never from https to http, and only to allow-listed hosts.
+func newTemplateHTTPClient(conf map[string]string) (*http.Client, error) {
+	fetchTimeout, err := getFetchTimeout(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout: fetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > MaxPodTemplateRedirects {
+				return fmt.Errorf("stopped after %d redirects", MaxPodTemplateRedirects)
+			}
+			if via[len(via)-1].URL.Scheme == "https" && req.URL.Scheme != "https" {
+				return fmt.Errorf("refusing redirect from https to %s", req.URL.Scheme)
+			}
+			return checkTemplateHostAllowed(req.URL)
+		},
+	}, nil
 }
+
+// copyFile copies sourceFile to destFile, removing the source when removeSourceFile is set. Such a move is first tried
+// as a rename, which fails across file systems, and falls back to copying the content.
 func copyFile(sourceFile string, destFile string, removeSourceFile bool) (string, error) {
-	if err := os.Rename(sourceFile, destFile); err != nil {
+	if removeSourceFile {
+		if err := os.Rename(sourceFile, destFile); err == nil {
+			return destFile, nil
+		}
+	}
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(destFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		return "", err
+	}
+	if err := destination.Close(); err != nil {
 		return "", err
 	}
 	if removeSourceFile {
@@ -95,6 +234,8 @@ func copyFile(sourceFile string, destFile string, removeSourceFile bool) (string
 	}
 	return destFile, nil
 }
+
+// downloadFileFromURL writes a download to destFile, failing once it exceeds the maximum pod template size
 func downloadFileFromURL(reader io.Reader, destFile string) (string, error) {
 	tempFile, err := os.CreateTemp(filepath.Dir(destFile), "fetchFileTemp")
 	if err != nil {
@@ -103,101 +244,110 @@ func downloadFileFromURL(reader io.Reader, destFile string) (string, error) {
 	defer tempFile.Close()
 	defer os.Remove(tempFile.Name())
 
-	if _, err := io.Copy(tempFile, reader); err != nil {
+	maxSize := getTemplateMaxSize()
+	written, err := io.Copy(tempFile, io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if written > maxSize {
+		return "", fmt.Errorf("download exceeds the maximum pod template size of %d bytes", maxSize)
+	}
+	if err := tempFile.Close(); err != nil {
 		return "", err
 	}
 
 	return copyFile(tempFile.Name(), destFile, true)
 }
-func createTempDir() string {
-	dir, err := os.MkdirTemp("", "spark")
-	if err != nil {
-		log.Fatal(err)
-	}
-	return dir
+func createTempDir() (string, error) {
+	return os.MkdirTemp("", "spark")
 }
-func loadPodFromTemplate(templateFileName string, containerName string, conf map[string]string) (apiv1.Pod, error) {
+func loadPodFromTemplate(templateFileName string, containerName string, namespace string, conf map[string]string, kubeClient kubernetes.Interface) (apiv1.Pod, error) {
 	var file apiv1.Pod
-	localFile, err := downloadFile(templateFileName, createTempDir(), conf)
+	tempDir, err := createTempDir()
+	if err != nil {
+		return file, fmt.Errorf("failed to create a directory for the pod template file: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	localFile, err := downloadFile(templateFileName, tempDir, namespace, conf, kubeClient)
 	if err != nil {
-		fmt.Errorf("Encountered exception while attempting to download the pod template file : %v", err)
+		log.Printf("Encountered exception while attempting to download the pod template file : %v", err)
 		return file, err
-	} else {
-		data, err := os.ReadFile(localFile)
-		if err != nil {
-			return file, err
-		}
-		manifests := string(data)
-		decode := scheme.Codecs.UniversalDeserializer().Decode
-		obj, _, err := decode([]byte(manifests), nil, nil)
-		if err != nil {
-			return file, err
-		}
-		pod := obj.(*apiv1.Pod)
-		newPod := selectSparkContainer(*pod, containerName)
-		return newPod, nil
 	}
-}
-func selectSparkContainer(pod apiv1.Pod, containerName string) apiv1.Pod {
-	selectNamedContainer := func(containers []apiv1.Container, name string) (*apiv1.Container, []apiv1.Container, bool) {
-		var rest []apiv1.Container
-		for _, container := range containers {
-			if container.Name == name {
-				return &container, rest, true
-			}
-			rest = append(rest, container)
-		}
-		log.Printf("specified container %s not found on pod template, falling back to taking the first container", name)
-		return nil, nil, false
+	fileInfo, err := os.Stat(localFile)
+	if err != nil {
+		return file, err
+	}
+	if maxSize := getTemplateMaxSize(); fileInfo.Size() > maxSize {
+		return file, fmt.Errorf("pod template file %s exceeds the maximum size of %d bytes", templateFileName, maxSize)
+	}
+	data, err := os.ReadFile(localFile)
+	if err != nil {
+		return file, err
 	}
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, _, err := decode(data, nil, nil)
+	if err != nil {
+		return file, err
+	}
+	pod, isPod := obj.(*apiv1.Pod)
+	if !isPod {
+		return file, fmt.Errorf("pod template file %s contains a %T instead of a Pod", templateFileName, obj)
+	}
+	newPod := selectSparkContainer(*pod, containerName)
+	return newPod, nil
+}
 
+// selectSparkContainer moves the Spark container of a pod template to the front and renames it to the driver
+// container name, so that it is merged with the generated driver container. Like spark-submit, the first container is
+// used when no container name is configured or the named one does not exist. All other template content is kept.
+func selectSparkContainer(pod apiv1.Pod, containerName string) apiv1.Pod {
 	containers := pod.Spec.Containers
+	if len(containers) == 0 {
+		return pod
+	}
+	sparkContainerIndex := 0
 	if containerName != "" {
-		if selectedContainer, _, found := selectNamedContainer(containers, containerName); found {
-			return apiv1.Pod{
-				Spec: apiv1.PodSpec{
-					Containers: []apiv1.Container{*selectedContainer},
-				},
+		found := false
+		for index, container := range containers {
+			if container.Name == containerName {
+				sparkContainerIndex = index
+				found = true
+				break
 			}
 		}
+		if !found {
+			log.Printf("specified container %s not found on pod template, falling back to taking the first container", containerName)
+		}
 	}
 
-	if len(containers) > 0 {
-		var containerList []apiv1.Container
-		containerList = append(containerList, containers[0])
-		for _, container := range containers[1:] {
+	sparkContainer := containers[sparkContainerIndex]
+	sparkContainer.Name = common.SparkDriverContainerName
+	containerList := []apiv1.Container{sparkContainer}
+	for index, container := range containers {
+		if index != sparkContainerIndex {
 			containerList = append(containerList, container)
 		}
-		return apiv1.Pod{
-			Spec: apiv1.PodSpec{
-				Containers: containerList,
-			},
-		}
-
 	}
-	return apiv1.Pod{}
+	pod.Spec.Containers = containerList
+	return pod
 }
-func getBlockManagerPort(sparkConfKeyValuePairs map[string]string) int {
-	//BlockManager Port
-	blockManagerPortToBeUsed := common.DefaultBlockManagerPort
-	sparkBlockManagerPortSupplied, sparkBlockManagerPortSuppliedValueExists := sparkConfKeyValuePairs[SparkBlockManagerPort]
-	sparkDriverBlockManagerPortSupplied, sparkDriverBlockManagerPortSuppliedValueExists := sparkConfKeyValuePairs[SparkDriverBlockManagerPort]
-	if sparkDriverBlockManagerPortSuppliedValueExists {
-		blockManagerPortFromConfig, err := strconv.Atoi(sparkDriverBlockManagerPortSupplied)
-		if err != nil {
-			panic("Block Manager Port not parseable to integer value - hence failing the spark submit" + fmt.Sprint(err))
-		} else {
-			blockManagerPortToBeUsed = blockManagerPortFromConfig
-		}
-	} else if sparkBlockManagerPortSuppliedValueExists {
-		blockManagerPortFromConfig, err := strconv.Atoi(sparkBlockManagerPortSupplied)
+
+// getReservedDriverPorts returns the ports exposed by the driver container before the user declared ones, keyed by
+// port name
+func getReservedDriverPorts(app *v1beta2.SparkApplication, driverConfig resolver.RoleConfig) (map[string]int32, error) {
+	reservedPorts := make(map[string]int32)
+	for name, setting := range driverConfig.Ports {
+		port, err := resolver.ParsePort(setting)
 		if err != nil {
-			panic("Driver Block Manager Port not parseable to integer value - hence failing the spark submit" + fmt.Sprint(err))
-		} else {
-			blockManagerPortToBeUsed = blockManagerPortFromConfig
+			return nil, err
 		}
+		reservedPorts[name] = port
+	}
+	if common.ExposesDriverMetrics(app) {
+		reservedPorts[common.GetPrometheusPortName(app)] = common.GetPrometheusPort(app)
 	}
-	return blockManagerPortToBeUsed
+	return reservedPorts, nil
 }
 
 func addSecret(secret v1beta2.SecretInfo, volumeExtension string, driverPodVolumes []apiv1.Volume, driverPodContainerSpec apiv1.Container) ([]apiv1.Volume, apiv1.Container) {
@@ -219,3 +369,91 @@ func addSecret(secret v1beta2.SecretInfo, volumeExtension string, driverPodVolum
 	driverPodContainerSpec.VolumeMounts = append(driverPodContainerSpec.VolumeMounts, volumeMount)
 	return driverPodVolumes, driverPodContainerSpec
 }
+
+// addConfigMaps creates a volume for every ConfigMap listed in the driver spec and mounts it on the driver container.
+// A ConfigMap listed more than once shares its volume, but the derived volume name must not clash with a volume
+// already generated for the driver pod.
+func addConfigMaps(configMaps []v1beta2.NamePath, existingVolumes []apiv1.Volume, driverPodContainerSpec apiv1.Container) ([]apiv1.Volume, apiv1.Container, error) {
+	var configMapVolumes []apiv1.Volume
+	for _, configMap := range configMaps {
+		if configMap.Name == "" || configMap.Path == "" {
+			return nil, driverPodContainerSpec, fmt.Errorf("configmap name and path must be specified, got name %q and path %q", configMap.Name, configMap.Path)
+		}
+		volumeName := configMap.Name + ConfigMapVolumeExtension
+		if checkVolumeMountIsVolume(existingVolumes, volumeName) {
+			return nil, driverPodContainerSpec, fmt.Errorf("volume %s for configmap %s clashes with an existing volume of the driver pod", volumeName, configMap.Name)
+		}
+		if !checkVolumeMountIsVolume(configMapVolumes, volumeName) {
+			configMapVolumes = append(configMapVolumes, apiv1.Volume{
+				Name: volumeName,
+				VolumeSource: apiv1.VolumeSource{
+					ConfigMap: &apiv1.ConfigMapVolumeSource{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: configMap.Name},
+					},
+				},
+			})
+		}
+		driverPodContainerSpec.VolumeMounts = append(driverPodContainerSpec.VolumeMounts, apiv1.VolumeMount{
+			Name:      volumeName,
+			MountPath: configMap.Path,
+		})
+	}
+	return configMapVolumes, driverPodContainerSpec, nil
+}
+
+// getDriverSecretKeyRefEnvVars builds the driver env vars sourced from secret keys. They are declared in
+// Driver.EnvSecretKeyRefs or as spark.kubernetes.driver.secretKeyRef.[EnvName]=[SecretName]:[Key] in sparkConf,
+// optionally followed by ":optional". Driver.EnvSecretKeyRefs wins when both declare the same env var.
+func getDriverSecretKeyRefEnvVars(app *v1beta2.SparkApplication) ([]apiv1.EnvVar, error) {
+	secretKeySelectors := make(map[string]*apiv1.SecretKeySelector)
+	for sparkConfKey, sparkConfValue := range app.Spec.SparkConf {
+		if !strings.HasPrefix(sparkConfKey, common.SparkDriverSecretKeyRefKeyPrefix) {
+			continue
+		}
+		envName := strings.TrimPrefix(sparkConfKey, common.SparkDriverSecretKeyRefKeyPrefix)
+		parts := strings.Split(sparkConfValue, ":")
+		if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != SecretKeyRefOptional) {
+			return nil, fmt.Errorf("invalid value %q for %s: expected [SecretName]:[Key] or [SecretName]:[Key]:%s", sparkConfValue, sparkConfKey, SecretKeyRefOptional)
+		}
+		secretKeySelectors[envName] = &apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: parts[0]},
+			Key:                  parts[1],
+		}
+		if len(parts) == 3 {
+			secretKeySelectors[envName].Optional = common.BoolPointer(true)
+		}
+	}
+	for envName, nameKey := range app.Spec.Driver.EnvSecretKeyRefs {
+		if _, exists := secretKeySelectors[envName]; exists {
+			log.Printf("Env var %s is declared in both sparkConf and envSecretKeyRefs, using envSecretKeyRefs", envName)
+		}
+		secretKeySelectors[envName] = &apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: nameKey.Name},
+			Key:                  nameKey.Key,
+		}
+	}
+
+	envNames := make([]string, 0, len(secretKeySelectors))
+	for envName := range secretKeySelectors {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	var envVars []apiv1.EnvVar
+	for _, envName := range envNames {
+		selector := secretKeySelectors[envName]
+		if errs := validation.IsEnvVarName(envName); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid secretKeyRef env var name %q: %s", envName, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsDNS1123Subdomain(selector.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid secret name %q for env var %s: %s", selector.Name, envName, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsConfigMapKey(selector.Key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid secret key %q for env var %s: %s", selector.Key, envName, strings.Join(errs, ", "))
+		}
+		envVars = append(envVars, apiv1.EnvVar{
+			Name:      envName,
+			ValueFrom: &apiv1.EnvVarSource{SecretKeyRef: selector},
+		})
+	}
+	return envVars, nil
+}
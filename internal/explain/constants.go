@@ -0,0 +1,8 @@
+package explain
+
+import "nativesubmit/internal/resolver"
+
+const (
+	// SourceDerived is the source of the values computed from other settings, like the memory overhead
+	SourceDerived resolver.Source = "derived"
+)
@@ -0,0 +1,100 @@
+package explain
+
+import (
+	"fmt"
+	"nativesubmit/internal/memory"
+	"nativesubmit/internal/resolver"
+	"sort"
+)
+
+// Entry is an effective setting of the Spark Application, with where its value comes from
+type Entry struct {
+	// Role is "driver" or "executor", empty for the application wide settings
+	Role string `json:"role,omitempty"`
+	// Name is the setting, like memory or nodeSelector[disktype]
+	Name string `json:"name"`
+	resolver.Setting
+	// Note tells how a derived value is computed
+	Note string `json:"note,omitempty"`
+}
+
+// Explain lists the effective settings of the resolved driver and executors, the way the driver pod, the properties
+// file and the batch scheduler see them. Settings that are unset and have no default are left out.
+func Explain(config resolver.Config) ([]Entry, error) {
+	entries := []Entry{
+		{Name: "imagePullPolicy", Setting: config.ImagePullPolicy},
+		{Name: "memoryOverheadFactor", Setting: config.MemoryOverheadFactor},
+	}
+	for _, roleConfig := range []resolver.RoleConfig{config.Driver, config.Executor} {
+		roleEntries, err := explainRole(roleConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s settings: %w", roleConfig.Role, err)
+		}
+		entries = append(entries, roleEntries...)
+	}
+	return entries, nil
+}
+
+func explainRole(config resolver.RoleConfig) ([]Entry, error) {
+	var entries []Entry
+	add := func(name string, setting resolver.Setting) {
+		if setting.IsSet() {
+			entries = append(entries, Entry{Role: config.Role, Name: name, Setting: setting})
+		}
+	}
+	addDerived := func(name, value, note string) {
+		entries = append(entries, Entry{Role: config.Role, Name: name, Setting: resolver.Setting{Value: value, Source: SourceDerived}, Note: note})
+	}
+
+	add("image", config.Image)
+	add("cores", config.Cores)
+	add("coreRequest", config.CoreRequest)
+	add("coreLimit", config.CoreLimit)
+
+	memoryMiB, err := memory.GetMemoryMiB(config)
+	if err != nil {
+		return nil, err
+	}
+	overheadMiB, err := memory.GetOverheadMiB(config, memoryMiB)
+	if err != nil {
+		return nil, err
+	}
+	containerMemoryMiB, err := memory.GetContainerMemoryMiB(config)
+	if err != nil {
+		return nil, err
+	}
+	add("memory", config.Memory)
+	add("memoryOverheadFactor", config.MemoryOverheadFactor)
+	if config.MemoryOverhead.IsSet() {
+		add("memoryOverhead", config.MemoryOverhead)
+	} else {
+		addDerived("memoryOverhead", fmt.Sprintf("%dMi", overheadMiB),
+			fmt.Sprintf("max(%s x %dMi memory, %dMi)", config.MemoryOverheadFactor.Value, memoryMiB, memory.MemoryOverheadMinMiB))
+	}
+	add("pysparkMemory", config.PySparkMemory)
+	note := fmt.Sprintf("%dMi memory + %dMi overhead", memoryMiB, overheadMiB)
+	if pysparkMiB := containerMemoryMiB - memoryMiB - overheadMiB; pysparkMiB > 0 {
+		note += fmt.Sprintf(" + %dMi pyspark memory", pysparkMiB)
+	}
+	addDerived("containerMemory", fmt.Sprintf("%dMi", containerMemoryMiB), note)
+
+	add("serviceAccount", config.ServiceAccount)
+	for _, label := range sortedKeys(config.NodeSelector) {
+		add(fmt.Sprintf("nodeSelector[%s]", label), config.NodeSelector[label])
+	}
+	for _, name := range sortedKeys(config.Ports) {
+		add(fmt.Sprintf("ports[%s]", name), config.Ports[name])
+	}
+	add("localDirs", config.LocalDirs)
+	add("schedulerName", config.SchedulerName)
+	return entries, nil
+}
+
+func sortedKeys(settings map[string]resolver.Setting) []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
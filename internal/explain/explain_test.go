@@ -0,0 +1,104 @@
+package explain
+
+import (
+	"bytes"
+	"nativesubmit/internal/resolver"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringPtr(s string) *string { return &s }
+
+// find returns the entry of the role and setting name
+func find(t *testing.T, entries []Entry, role, name string) Entry {
+	t.Helper()
+	for _, entry := range entries {
+		if entry.Role == role && entry.Name == name {
+			return entry
+		}
+	}
+	t.Fatalf("no %s entry %s", role, name)
+	return Entry{}
+}
+
+func TestExplain(t *testing.T) {
+	app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{
+		Type:         v1beta2.SparkApplicationTypePython,
+		Image:        stringPtr("spark:app"),
+		NodeSelector: map[string]string{"disktype": "ssd"},
+		SparkConf: map[string]string{
+			"spark.driver.memory":                     "4g",
+			"spark.executor.memoryOverhead":           "1g",
+			"spark.executor.pyspark.memory":           "512m",
+			"spark.kubernetes.node.selector.disktype": "hdd",
+			"spark.driver.port":                       "7000",
+		},
+		Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("2g"), ServiceAccount: stringPtr("spark")}},
+	}}
+	entries, err := Explain(resolver.Resolve(app))
+	require.NoError(t, err)
+
+	assert.Equal(t, Entry{Name: "imagePullPolicy", Setting: resolver.Setting{Value: "IfNotPresent", Source: resolver.SourceDefault}}, entries[0])
+
+	memory := find(t, entries, "driver", "memory")
+	assert.Equal(t, resolver.Setting{
+		Value:      "2g",
+		Source:     resolver.SourceSpec,
+		Field:      "spec.driver.memory",
+		Overridden: []resolver.Setting{{Value: "4g", Source: resolver.SourceSparkConf, Field: "spec.sparkConf[spark.driver.memory]"}},
+	}, memory.Setting)
+	assert.Equal(t, Entry{
+		Role:    "driver",
+		Name:    "memoryOverhead",
+		Setting: resolver.Setting{Value: "819Mi", Source: SourceDerived},
+		Note:    "max(0.40 x 2048Mi memory, 384Mi)",
+	}, find(t, entries, "driver", "memoryOverhead"))
+	assert.Equal(t, "2867Mi", find(t, entries, "driver", "containerMemory").Value)
+	assert.Equal(t, "spark", find(t, entries, "driver", "serviceAccount").Value)
+	assert.Equal(t, "7000", find(t, entries, "driver", "ports[driver-rpc-port]").Value)
+	assert.Equal(t, "spark:app", find(t, entries, "executor", "image").Value)
+
+	nodeSelector := find(t, entries, "executor", "nodeSelector[disktype]")
+	assert.Equal(t, "ssd", nodeSelector.Value)
+	assert.Equal(t, "spec.sparkConf[spark.kubernetes.node.selector.disktype]", nodeSelector.Overridden[0].Field)
+
+	assert.Equal(t, "1g", find(t, entries, "executor", "memoryOverhead").Value)
+	assert.Equal(t, Entry{
+		Role:    "executor",
+		Name:    "containerMemory",
+		Setting: resolver.Setting{Value: "2560Mi", Source: SourceDerived},
+		Note:    "1024Mi memory + 1024Mi overhead + 512Mi pyspark memory",
+	}, find(t, entries, "executor", "containerMemory"))
+	assert.Equal(t, resolver.DefaultLocalDirs, find(t, entries, "executor", "localDirs").Value)
+}
+
+func TestExplainInvalidMemory(t *testing.T) {
+	app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{
+		SparkConf: map[string]string{"spark.executor.memory": "lots"},
+	}}
+	_, err := Explain(resolver.Resolve(app))
+	assert.ErrorContains(t, err, "invalid executor settings: spec.sparkConf[spark.executor.memory]")
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTable(&buf, []Entry{
+		{Name: "imagePullPolicy", Setting: resolver.Setting{Value: "IfNotPresent", Source: resolver.SourceDefault}},
+		{Role: "driver", Name: "cores", Setting: resolver.Setting{
+			Value:      "2",
+			Source:     resolver.SourceSpec,
+			Field:      "spec.driver.cores",
+			Overridden: []resolver.Setting{{Value: "4", Source: resolver.SourceSparkConf, Field: "spec.sparkConf[spark.driver.cores]"}},
+		}},
+		{Role: "driver", Name: "containerMemory", Setting: resolver.Setting{Value: "1408Mi", Source: SourceDerived}, Note: "1024Mi memory + 384Mi overhead"},
+	}))
+	assert.Equal(t, `ROLE    SETTING          VALUE         SOURCE     FROM
+-       imagePullPolicy  IfNotPresent  default    -
+driver  cores            2             spec       spec.driver.cores
+          overrides      4             sparkConf  spec.sparkConf[spark.driver.cores]
+driver  containerMemory  1408Mi        derived    1024Mi memory + 384Mi overhead
+`, buf.String())
+}
@@ -0,0 +1,30 @@
+package explain
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteTable writes the entries as a table, each overridden input on its own line below the setting overriding it
+func WriteTable(w io.Writer, entries []Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROLE\tSETTING\tVALUE\tSOURCE\tFROM")
+	for _, entry := range entries {
+		role := entry.Role
+		if role == "" {
+			role = "-"
+		}
+		from := entry.Field
+		if entry.Note != "" {
+			from = entry.Note
+		} else if from == "" {
+			from = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", role, entry.Name, entry.Value, entry.Source, from)
+		for _, overridden := range entry.Overridden {
+			fmt.Fprintf(tw, "\t  overrides\t%s\t%s\t%s\n", overridden.Value, overridden.Source, overridden.Field)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,13 @@
+package ingress
+
+const (
+	// NginxRewriteTargetAnnotation rewrites requests served under a sub path to the root of the backend.
+	NginxRewriteTargetAnnotation = "nginx.ingress.kubernetes.io/rewrite-target"
+	// SubPathRegex captures the remainder of a request path so it can be used by the rewrite target.
+	SubPathRegex                  = "(/|$)(.*)"
+	SubPathRewriteTarget          = "/$2"
+	SparkUIServiceNameExtension   = "-ui-svc"
+	SparkUIIngressNameExtension   = "-ui-ingress"
+	DefaultSparkUIServicePortName = "spark-driver-ui-port"
+	Protocol                      = "TCP"
+)
@@ -0,0 +1,340 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// exposedService describes a Service, and optionally an Ingress, exposing one port of the driver pod
+type exposedService struct {
+	serviceName        string
+	servicePort        int32
+	servicePortName    string
+	targetPort         int32
+	serviceType        apiv1.ServiceType
+	serviceLabels      map[string]string
+	serviceAnnotations map[string]string
+	ingressName        string
+	ingressURL         *url.URL
+	ingressAnnotations map[string]string
+	ingressTLS         []networkingv1.IngressTLS
+}
+
+// GetSparkUIServiceName returns the name of the Service exposing the Spark UI
+func GetSparkUIServiceName(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("%s%s", app.Name, SparkUIServiceNameExtension)
+}
+
+// GetSparkUIIngressName returns the name of the Ingress exposing the Spark UI
+func GetSparkUIIngressName(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("%s%s", app.Name, SparkUIIngressNameExtension)
+}
+
+// GetDriverIngressServiceName returns the name of the Service exposing a port configured in the driver ingress options
+func GetDriverIngressServiceName(app *v1beta2.SparkApplication, port int32) string {
+	return fmt.Sprintf("%s-driver-%d-svc", app.Name, port)
+}
+
+// GetDriverIngressName returns the name of the Ingress exposing a port configured in the driver ingress options
+func GetDriverIngressName(app *v1beta2.SparkApplication, port int32) string {
+	return fmt.Sprintf("%s-driver-%d-ingress", app.Name, port)
+}
+
+// Helper func to create the Spark UI and driver ingress options Services and Ingresses of the Spark Application
+func Create(app *v1beta2.SparkApplication, config resolver.Config, kubeClient kubernetes.Interface, createdApplicationId string, driverPodUID string) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+
+	exposedServices, err := getExposedServices(app, config)
+	if err != nil {
+		return err
+	}
+	if len(exposedServices) == 0 {
+		log.Printf("No Spark UI or driver ingress options for app: %s, skipping", app.Name)
+		return nil
+	}
+	log.Printf("=== Starting Spark UI and driver ingress creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	ownerReference := *common.GetServiceOwnerReference(app, driverPodUID)
+	for _, exposed := range exposedServices {
+		service := buildService(app, exposed, createdApplicationId, ownerReference)
+		if err := createOrUpdateService(kubeClient, service); err != nil {
+			return fmt.Errorf("error while creating service %s: %w", service.Name, err)
+		}
+		log.Printf("Service %s of type %s exposes port %d", service.Name, service.Spec.Type, exposed.servicePort)
+
+		if exposed.ingressURL == nil {
+			continue
+		}
+		ingress := buildIngress(app, exposed, createdApplicationId, ownerReference)
+		if err := createOrUpdateIngress(kubeClient, ingress); err != nil {
+			return fmt.Errorf("error while creating ingress %s: %w", ingress.Name, err)
+		}
+		log.Printf("Ingress %s serves %s", ingress.Name, exposed.ingressURL.String())
+	}
+	log.Printf("=== Successfully completed Spark UI and driver ingress creation ===")
+	return nil
+}
+
+func getExposedServices(app *v1beta2.SparkApplication, config resolver.Config) ([]exposedService, error) {
+	var exposedServices []exposedService
+
+	if uiOptions := app.Spec.SparkUIOptions; uiOptions != nil {
+		uiPort, err := resolver.ParsePort(config.Driver.Ports[resolver.UIPortName])
+		if err != nil {
+			return nil, err
+		}
+		ingressURL, err := common.GetSparkUIIngressURL(app)
+		if err != nil {
+			return nil, err
+		}
+		exposed := exposedService{
+			serviceName:        GetSparkUIServiceName(app),
+			servicePort:        uiPort,
+			servicePortName:    DefaultSparkUIServicePortName,
+			targetPort:         uiPort,
+			serviceType:        apiv1.ServiceTypeClusterIP,
+			serviceLabels:      uiOptions.ServiceLabels,
+			serviceAnnotations: uiOptions.ServiceAnnotations,
+			ingressName:        GetSparkUIIngressName(app),
+			ingressURL:         ingressURL,
+			ingressAnnotations: uiOptions.IngressAnnotations,
+			ingressTLS:         uiOptions.IngressTLS,
+		}
+		if uiOptions.ServicePort != nil {
+			exposed.servicePort = *uiOptions.ServicePort
+		}
+		if uiOptions.ServicePortName != nil {
+			exposed.servicePortName = *uiOptions.ServicePortName
+		}
+		if uiOptions.ServiceType != nil {
+			exposed.serviceType = *uiOptions.ServiceType
+		}
+		exposedServices = append(exposedServices, exposed)
+	}
+
+	for i, ingressOptions := range app.Spec.DriverIngressOptions {
+		if ingressOptions.ServicePort == nil {
+			return nil, fmt.Errorf("driver ingress options %d: service port is required", i)
+		}
+		if ingressOptions.IngressURLFormat == "" {
+			return nil, fmt.Errorf("driver ingress options %d: ingress URL format is required", i)
+		}
+		port := *ingressOptions.ServicePort
+		ingressURL, err := common.GetIngressURL(ingressOptions.IngressURLFormat, app)
+		if err != nil {
+			return nil, fmt.Errorf("driver ingress options %d: %w", i, err)
+		}
+		exposed := exposedService{
+			serviceName:        GetDriverIngressServiceName(app, port),
+			servicePort:        port,
+			servicePortName:    fmt.Sprintf("driver-port-%d", port),
+			targetPort:         port,
+			serviceType:        apiv1.ServiceTypeClusterIP,
+			serviceLabels:      ingressOptions.ServiceLabels,
+			serviceAnnotations: ingressOptions.ServiceAnnotations,
+			ingressName:        GetDriverIngressName(app, port),
+			ingressURL:         ingressURL,
+			ingressAnnotations: ingressOptions.IngressAnnotations,
+			ingressTLS:         ingressOptions.IngressTLS,
+		}
+		if ingressOptions.ServicePortName != nil {
+			exposed.servicePortName = *ingressOptions.ServicePortName
+		}
+		if ingressOptions.ServiceType != nil {
+			exposed.serviceType = *ingressOptions.ServiceType
+		}
+		exposedServices = append(exposedServices, exposed)
+	}
+	return exposedServices, nil
+}
+
+func getResourceLabels(app *v1beta2.SparkApplication, createdApplicationId string) map[string]string {
+	return map[string]string{
+		common.SparkApplicationSelectorLabel: createdApplicationId,
+		common.SparkAppNameLabel:             app.Name,
+	}
+}
+
+func buildService(app *v1beta2.SparkApplication, exposed exposedService, createdApplicationId string, ownerReference metav1.OwnerReference) *apiv1.Service {
+	// User supplied labels never override the labels identifying the application
+	serviceLabels := make(map[string]string)
+	for key, value := range exposed.serviceLabels {
+		serviceLabels[key] = value
+	}
+	for key, value := range getResourceLabels(app, createdApplicationId) {
+		serviceLabels[key] = value
+	}
+
+	var serviceAnnotations map[string]string
+	if len(exposed.serviceAnnotations) > 0 {
+		serviceAnnotations = make(map[string]string, len(exposed.serviceAnnotations))
+		for key, value := range exposed.serviceAnnotations {
+			serviceAnnotations[key] = value
+		}
+	}
+
+	return &apiv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            exposed.serviceName,
+			Namespace:       common.GetAppNamespace(app),
+			Labels:          serviceLabels,
+			Annotations:     serviceAnnotations,
+			OwnerReferences: []metav1.OwnerReference{ownerReference},
+		},
+		Spec: apiv1.ServiceSpec{
+			Type: exposed.serviceType,
+			Ports: []apiv1.ServicePort{
+				{
+					Name:       exposed.servicePortName,
+					Port:       exposed.servicePort,
+					Protocol:   Protocol,
+					TargetPort: intstr.FromInt32(exposed.targetPort),
+				},
+			},
+			Selector: map[string]string{
+				common.SparkApplicationSelectorLabel: createdApplicationId,
+				common.SparkRoleLabel:                common.SparkDriverRole,
+			},
+		},
+	}
+}
+
+func buildIngress(app *v1beta2.SparkApplication, exposed exposedService, createdApplicationId string, ownerReference metav1.OwnerReference) *networkingv1.Ingress {
+	annotations := make(map[string]string)
+	for key, value := range exposed.ingressAnnotations {
+		annotations[key] = value
+	}
+
+	// Requests served under a sub path are rewritten to the root of the service
+	ingressPath := strings.TrimSuffix(exposed.ingressURL.Path, "/")
+	if ingressPath == "" {
+		ingressPath = "/"
+	} else {
+		ingressPath = ingressPath + SubPathRegex
+		annotations[NginxRewriteTargetAnnotation] = SubPathRewriteTarget
+	}
+	pathType := networkingv1.PathTypeImplementationSpecific
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            exposed.ingressName,
+			Namespace:       common.GetAppNamespace(app),
+			Labels:          getResourceLabels(app, createdApplicationId),
+			OwnerReferences: []metav1.OwnerReference{ownerReference},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: exposed.ingressURL.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     ingressPath,
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: exposed.serviceName,
+											Port: networkingv1.ServiceBackendPort{Number: exposed.servicePort},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			TLS: exposed.ingressTLS,
+		},
+	}
+	if len(annotations) > 0 {
+		ingress.Annotations = annotations
+	}
+	if ingressClassName := os.Getenv(common.IngressClassNameEnvVar); ingressClassName != "" {
+		ingress.Spec.IngressClassName = &ingressClassName
+	}
+	return ingress
+}
+
+func createOrUpdateService(kubeClient kubernetes.Interface, service *apiv1.Service) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existingService, err := kubeClient.CoreV1().Services(service.Namespace).Get(context.TODO(), service.Name, metav1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, err = kubeClient.CoreV1().Services(service.Namespace).Create(context.TODO(), service, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		existingService.Labels = service.Labels
+		existingService.Annotations = service.Annotations
+		existingService.OwnerReferences = service.OwnerReferences
+		// The cluster IP of an existing service is immutable
+		service.Spec.ClusterIP = existingService.Spec.ClusterIP
+		service.Spec.ClusterIPs = existingService.Spec.ClusterIPs
+		keepNodePorts(service, existingService)
+		existingService.Spec = service.Spec
+		_, err = kubeClient.CoreV1().Services(service.Namespace).Update(context.TODO(), existingService, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// keepNodePorts copies the node ports allocated to the existing service to the ports it keeps, so an update does not
+// move them to newly allocated ones or fail on a conflict
+func keepNodePorts(service *apiv1.Service, existingService *apiv1.Service) {
+	if service.Spec.Type != apiv1.ServiceTypeNodePort && service.Spec.Type != apiv1.ServiceTypeLoadBalancer {
+		return
+	}
+	for i := range service.Spec.Ports {
+		port := &service.Spec.Ports[i]
+		if port.NodePort != 0 {
+			continue
+		}
+		for _, existingPort := range existingService.Spec.Ports {
+			if existingPort.Port == port.Port && existingPort.Protocol == port.Protocol {
+				port.NodePort = existingPort.NodePort
+				break
+			}
+		}
+	}
+	if service.Spec.HealthCheckNodePort == 0 {
+		service.Spec.HealthCheckNodePort = existingService.Spec.HealthCheckNodePort
+	}
+}
+
+func createOrUpdateIngress(kubeClient kubernetes.Interface, ingress *networkingv1.Ingress) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existingIngress, err := kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Get(context.TODO(), ingress.Name, metav1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, err = kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Create(context.TODO(), ingress, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		existingIngress.Labels = ingress.Labels
+		existingIngress.Annotations = ingress.Annotations
+		existingIngress.OwnerReferences = ingress.OwnerReferences
+		existingIngress.Spec = ingress.Spec
+		_, err = kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Update(context.TODO(), existingIngress, metav1.UpdateOptions{})
+		return err
+	})
+}
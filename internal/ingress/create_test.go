@@ -0,0 +1,210 @@
+package ingress
+
+import (
+	"context"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateWithoutOptions(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	services, err := kubeClient.CoreV1().Services("default").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, services.Items)
+}
+
+func TestCreateSparkUIService(t *testing.T) {
+	t.Setenv("SPARK_UI_INGRESS_URL_FORMAT", "")
+	nodePort := apiv1.ServiceTypeNodePort
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.SparkConf = map[string]string{"spark.ui.port": "4041"}
+	app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{
+		ServicePort:        int32Ptr(80),
+		ServicePortName:    strPtr("http"),
+		ServiceType:        &nodePort,
+		ServiceLabels:      map[string]string{"team": "data", common.SparkApplicationSelectorLabel: "spoofed"},
+		ServiceAnnotations: map[string]string{"prometheus.io/scrape": "true"},
+	}
+	kubeClient := fake.NewSimpleClientset()
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-ui-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.ServiceTypeNodePort, service.Spec.Type)
+	require.Len(t, service.Spec.Ports, 1)
+	assert.Equal(t, "http", service.Spec.Ports[0].Name)
+	assert.Equal(t, int32(80), service.Spec.Ports[0].Port)
+	assert.Equal(t, int32(4041), service.Spec.Ports[0].TargetPort.IntVal)
+	assert.Equal(t, map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id", common.SparkRoleLabel: common.SparkDriverRole}, service.Spec.Selector)
+	assert.Equal(t, "data", service.Labels["team"])
+	assert.Equal(t, "spark-app-id", service.Labels[common.SparkApplicationSelectorLabel])
+	assert.Equal(t, map[string]string{"prometheus.io/scrape": "true"}, service.Annotations)
+	require.Len(t, service.OwnerReferences, 1)
+	assert.Equal(t, "driver-pod-uid", string(service.OwnerReferences[0].UID))
+
+	ingresses, err := kubeClient.NetworkingV1().Ingresses("default").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, ingresses.Items, "no UI ingress without an ingress URL format")
+}
+
+func TestCreateSparkUIIngress(t *testing.T) {
+	tests := []struct {
+		name            string
+		urlFormat       string
+		wantHost        string
+		wantPath        string
+		wantRewrite     bool
+		wantAnnotations int
+	}{
+		{
+			name:            "host per application",
+			urlFormat:       "{{$appName}}.{{$appNamespace}}.spark.example.com",
+			wantHost:        "test-app.default.spark.example.com",
+			wantPath:        "/",
+			wantAnnotations: 1,
+		},
+		{
+			name:            "path per application",
+			urlFormat:       "https://spark.example.com/{{$appNamespace}}/{{$appName}}",
+			wantHost:        "spark.example.com",
+			wantPath:        "/default/test-app(/|$)(.*)",
+			wantRewrite:     true,
+			wantAnnotations: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SPARK_UI_INGRESS_URL_FORMAT", tt.urlFormat)
+			t.Setenv("INGRESS_CLASS_NAME", "nginx")
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+			app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{
+				IngressAnnotations: map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+				IngressTLS:         []networkingv1.IngressTLS{{Hosts: []string{tt.wantHost}, SecretName: "spark-tls"}},
+			}
+			kubeClient := fake.NewSimpleClientset()
+			require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+			ingress, err := kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "test-app-ui-ingress", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Len(t, ingress.Spec.Rules, 1)
+			rule := ingress.Spec.Rules[0]
+			assert.Equal(t, tt.wantHost, rule.Host)
+			require.Len(t, rule.HTTP.Paths, 1)
+			assert.Equal(t, tt.wantPath, rule.HTTP.Paths[0].Path)
+			assert.Equal(t, "test-app-ui-svc", rule.HTTP.Paths[0].Backend.Service.Name)
+			assert.Equal(t, int32(4040), rule.HTTP.Paths[0].Backend.Service.Port.Number)
+			assert.Len(t, ingress.Annotations, tt.wantAnnotations)
+			assert.Equal(t, "letsencrypt", ingress.Annotations["cert-manager.io/cluster-issuer"])
+			if tt.wantRewrite {
+				assert.Equal(t, "/$2", ingress.Annotations[NginxRewriteTargetAnnotation])
+			}
+			assert.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{tt.wantHost}, SecretName: "spark-tls"}}, ingress.Spec.TLS)
+			require.NotNil(t, ingress.Spec.IngressClassName)
+			assert.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+			assert.Equal(t, "driver-pod-uid", string(ingress.OwnerReferences[0].UID))
+		})
+	}
+}
+
+func TestCreateDriverIngressOptions(t *testing.T) {
+	loadBalancer := apiv1.ServiceTypeLoadBalancer
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.DriverIngressOptions = []v1beta2.DriverIngressConfiguration{
+		{
+			ServicePort:        int32Ptr(8888),
+			ServiceType:        &loadBalancer,
+			IngressURLFormat:   "{{$appName}}-notebook.example.com",
+			IngressAnnotations: map[string]string{"kubernetes.io/ingress.class": "internal"},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset()
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-driver-8888-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, apiv1.ServiceTypeLoadBalancer, service.Spec.Type)
+	assert.Equal(t, "driver-port-8888", service.Spec.Ports[0].Name)
+	assert.Equal(t, int32(8888), service.Spec.Ports[0].TargetPort.IntVal)
+
+	ingress, err := kubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "test-app-driver-8888-ingress", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "test-app-notebook.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "test-app-driver-8888-svc", ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+	assert.Equal(t, map[string]string{"kubernetes.io/ingress.class": "internal"}, ingress.Annotations)
+}
+
+func TestCreateUpdatesExistingService(t *testing.T) {
+	existing := &apiv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-app-ui-svc", Namespace: "default"},
+		Spec:       apiv1.ServiceSpec{ClusterIP: "10.0.0.12", Type: apiv1.ServiceTypeClusterIP},
+	}
+	kubeClient := fake.NewSimpleClientset(existing)
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{ServicePort: int32Ptr(8080)}
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-ui-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.12", service.Spec.ClusterIP)
+	assert.Equal(t, int32(8080), service.Spec.Ports[0].Port)
+}
+
+func TestCreateUpdatesExistingNodePortService(t *testing.T) {
+	existing := &apiv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-app-ui-svc", Namespace: "default"},
+		Spec: apiv1.ServiceSpec{
+			ClusterIP: "10.0.0.12",
+			Type:      apiv1.ServiceTypeNodePort,
+			Ports:     []apiv1.ServicePort{{Name: "spark-driver-ui-port", Port: 4040, Protocol: apiv1.ProtocolTCP, NodePort: 31040}},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(existing)
+	nodePort := apiv1.ServiceTypeNodePort
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{ServicePort: int32Ptr(4040), ServiceType: &nodePort}
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-ui-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, service.Spec.Ports, 1)
+	assert.Equal(t, int32(31040), service.Spec.Ports[0].NodePort, "the allocated node port is kept")
+}
+
+func TestCreateInvalidDriverIngressOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options v1beta2.DriverIngressConfiguration
+	}{
+		{name: "missing service port", options: v1beta2.DriverIngressConfiguration{IngressURLFormat: "example.com"}},
+		{name: "missing ingress URL format", options: v1beta2.DriverIngressConfiguration{ServicePort: int32Ptr(8888)}},
+		{name: "ingress URL without host", options: v1beta2.DriverIngressConfiguration{ServicePort: int32Ptr(8888), IngressURLFormat: "http:///path"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+			app.Spec.DriverIngressOptions = []v1beta2.DriverIngressConfiguration{tt.options}
+			assert.Error(t, Create(app, resolver.Resolve(app), fake.NewSimpleClientset(), "spark-app-id", "driver-pod-uid"))
+		})
+	}
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func strPtr(value string) *string {
+	return &value
+}
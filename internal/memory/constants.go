@@ -0,0 +1,16 @@
+package memory
+
+// Byte units of the JVM memory string suffixes
+const (
+	Byte int64 = 1
+	KiB        = 1024 * Byte
+	MiB        = 1024 * KiB
+	GiB        = 1024 * MiB
+	TiB        = 1024 * GiB
+	PiB        = 1024 * TiB
+)
+
+const (
+	// MemoryOverheadMinMiB is the minimum overhead Spark adds when it is derived from the overhead factor
+	MemoryOverheadMinMiB int64 = 384
+)
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"fmt"
+	"nativesubmit/internal/resolver"
+	"strconv"
+	"strings"
+)
+
+// GetMemoryMiB returns the JVM heap of the resolved driver or executor in MiB. The spec takes precedence over
+// spark.<role>.memory, and Spark's 1g default applies when neither is set.
+func GetMemoryMiB(config resolver.RoleConfig) (int64, error) {
+	return settingToMiB(config.Memory)
+}
+
+// GetOverheadFactor returns the memory overhead factor of the driver or executor. spark.<role>.memoryOverheadFactor
+// takes precedence over spec.memoryOverheadFactor and spark.kubernetes.memoryOverheadFactor. Without any of them
+// Spark uses 0.1 for JVM applications and 0.4 for Python and R ones.
+func GetOverheadFactor(config resolver.RoleConfig) (float64, error) {
+	setting := config.MemoryOverheadFactor
+	factor, err := strconv.ParseFloat(strings.TrimSpace(setting.Value), 64)
+	if err != nil || factor <= 0 {
+		return 0, fmt.Errorf("%s: invalid memory overhead factor %q, must be a positive number", setting.Describe(), setting.Value)
+	}
+	return factor, nil
+}
+
+// GetOverheadMiB returns the memory overhead Spark adds on top of the driver or executor heap, in MiB. An explicit
+// spec.<role>.memoryOverhead, spark.<role>.memoryOverhead or spark.kubernetes.memoryOverhead is used as is, otherwise
+// the overhead factor is applied to the heap, truncated to whole MiB, with a 384 MiB minimum.
+func GetOverheadMiB(config resolver.RoleConfig, memoryMiB int64) (int64, error) {
+	if setting := config.MemoryOverhead; setting.IsSet() {
+		return settingToMiB(setting)
+	}
+
+	factor, err := GetOverheadFactor(config)
+	if err != nil {
+		return 0, err
+	}
+	return max(int64(factor*float64(memoryMiB)), MemoryOverheadMinMiB), nil
+}
+
+// GetContainerMemoryMiB returns the memory of the driver or executor container in MiB: the heap, its overhead and,
+// for Python executors, spark.executor.pyspark.memory
+func GetContainerMemoryMiB(config resolver.RoleConfig) (int64, error) {
+	memoryMiB, err := GetMemoryMiB(config)
+	if err != nil {
+		return 0, err
+	}
+	overheadMiB, err := GetOverheadMiB(config, memoryMiB)
+	if err != nil {
+		return 0, err
+	}
+	total := memoryMiB + overheadMiB
+
+	if pysparkMemory := config.PySparkMemory; pysparkMemory.IsSet() {
+		pysparkMiB, err := settingToMiB(pysparkMemory)
+		if err != nil {
+			return 0, err
+		}
+		total += pysparkMiB
+	}
+	return total, nil
+}
+
+// settingToMiB converts a resolved memory setting to MiB, naming its field in the error
+func settingToMiB(setting resolver.Setting) (int64, error) {
+	memoryMiB, err := ToMiB(setting.Value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", setting.Describe(), err)
+	}
+	return memoryMiB, nil
+}
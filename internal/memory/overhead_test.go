@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"testing"
+	"testing/quick"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringPtr(s string) *string { return &s }
+
+// Expected values are the pod memory Spark's BasicDriverFeatureStep and BasicExecutorFeatureStep request for the same
+// settings
+func TestGetContainerMemoryMiB(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		spec    v1beta2.SparkApplicationSpec
+		want    int64
+		wantErr string
+	}{
+		{name: "driver default", role: common.SparkDriverRole, spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala}, want: 1408},
+		{name: "executor default", role: common.SparkExecutorRole, spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeJava}, want: 1408},
+		{
+			name: "minimum overhead",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeJava, Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("3g")}}},
+			want: 3456,
+		},
+		{
+			name: "jvm factor truncated",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeJava, Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("7g")}}},
+			want: 7884,
+		},
+		{
+			name: "python factor",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypePython, Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("4g")}}},
+			want: 5734,
+		},
+		{
+			name: "r factor",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeR, Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("2g")}}},
+			want: 2867,
+		},
+		{
+			name: "sparkConf memory and role factor",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:      v1beta2.SparkApplicationTypeJava,
+				SparkConf: map[string]string{"spark.driver.memory": "8g", "spark.driver.memoryOverheadFactor": "0.25", "spark.executor.memoryOverheadFactor": "0.5"},
+			},
+			want: 10240,
+		},
+		{
+			name: "spec memory over sparkConf memory",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:      v1beta2.SparkApplicationTypeJava,
+				SparkConf: map[string]string{"spark.executor.memory": "8g"},
+				Executor:  v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("10g")}},
+			},
+			want: 11264,
+		},
+		{
+			name: "spec factor",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:                 v1beta2.SparkApplicationTypeScala,
+				MemoryOverheadFactor: stringPtr("0.2"),
+				Driver:               v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("5g")}},
+			},
+			want: 6144,
+		},
+		{
+			name: "role factor over spec factor",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:                 v1beta2.SparkApplicationTypeScala,
+				MemoryOverheadFactor: stringPtr("0.2"),
+				SparkConf:            map[string]string{"spark.executor.memoryOverheadFactor": "0.5"},
+				Executor:             v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("2g")}},
+			},
+			want: 3072,
+		},
+		{
+			name: "explicit factor replaces the python factor",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:      v1beta2.SparkApplicationTypePython,
+				SparkConf: map[string]string{"spark.kubernetes.memoryOverheadFactor": "0.1"},
+				Executor:  v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("4g")}},
+			},
+			want: 4505,
+		},
+		{
+			name: "spec overhead",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("2g"), MemoryOverhead: stringPtr("512m")}}},
+			want: 2560,
+		},
+		{
+			name: "role overhead over shared overhead",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{"spark.executor.memory": "2g", "spark.executor.memoryOverhead": "256", common.SparkMemoryOverheadKey: "1g"},
+			},
+			want: 2304,
+		},
+		{
+			name: "pyspark memory",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:      v1beta2.SparkApplicationTypePython,
+				SparkConf: map[string]string{"spark.executor.memory": "2g", resolver.SparkPySparkExecutorMemoryKey: "512m"},
+			},
+			want: 3379,
+		},
+		{
+			name: "pyspark memory ignored by the driver",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:      v1beta2.SparkApplicationTypePython,
+				SparkConf: map[string]string{"spark.driver.memory": "2g", resolver.SparkPySparkExecutorMemoryKey: "512m"},
+			},
+			want: 2867,
+		},
+		{
+			name:    "fractional memory",
+			role:    common.SparkDriverRole,
+			spec:    v1beta2.SparkApplicationSpec{Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("1.5g")}}},
+			wantErr: "spec.driver.memory: invalid memory",
+		},
+		{
+			name:    "invalid overhead",
+			role:    common.SparkExecutorRole,
+			spec:    v1beta2.SparkApplicationSpec{SparkConf: map[string]string{"spark.executor.memoryOverhead": "lots"}},
+			wantErr: "spec.sparkConf[spark.executor.memoryOverhead]: invalid memory",
+		},
+		{
+			name:    "invalid factor",
+			role:    common.SparkDriverRole,
+			spec:    v1beta2.SparkApplicationSpec{MemoryOverheadFactor: stringPtr("0")},
+			wantErr: "spec.memoryOverheadFactor: invalid memory overhead factor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetContainerMemoryMiB(resolver.Resolve(&v1beta2.SparkApplication{Spec: tt.spec}).Role(tt.role))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetOverheadMiBProperties(t *testing.T) {
+	jvmApp := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeJava}}
+	pythonApp := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypePython}}
+
+	// The derived overhead is never below the minimum, and is the truncated factor of the heap above it
+	derived := func(memoryMiB uint32) bool {
+		for app, factor := range map[*v1beta2.SparkApplication]float64{jvmApp: 0.1, pythonApp: 0.4} {
+			overhead, err := GetOverheadMiB(resolver.Resolve(app).Executor, int64(memoryMiB))
+			if err != nil || overhead < MemoryOverheadMinMiB {
+				return false
+			}
+			if scaled := int64(factor * float64(memoryMiB)); scaled > MemoryOverheadMinMiB && overhead != scaled {
+				return false
+			}
+		}
+		return true
+	}
+	assert.NoError(t, quick.Check(derived, nil))
+}
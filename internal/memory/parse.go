@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps the suffixes of JVM memory strings to their unit, as in Spark's JavaUtils.byteStringAs
+var byteUnits = map[string]int64{
+	"b":  Byte,
+	"k":  KiB,
+	"kb": KiB,
+	"m":  MiB,
+	"mb": MiB,
+	"g":  GiB,
+	"gb": GiB,
+	"t":  TiB,
+	"tb": TiB,
+	"p":  PiB,
+	"pb": PiB,
+}
+
+var (
+	memoryStringRegex           = regexp.MustCompile(`^([0-9]+)([a-z]+)?$`)
+	fractionalMemoryStringRegex = regexp.MustCompile(`^([0-9]+\.[0-9]+)([a-z]+)?$`)
+)
+
+// ParseBytes parses a JVM memory string, e.g. 512m or 2g, to bytes as Spark does. Suffixes are case-insensitive and
+// values without one are in defaultUnit. Like Spark, fractional values such as 1.5g are rejected rather than rounded.
+func ParseBytes(memory string, defaultUnit int64) (int64, error) {
+	lower := strings.ToLower(strings.TrimSpace(memory))
+	if fractionalMemoryStringRegex.MatchString(lower) {
+		return 0, fmt.Errorf("invalid memory %q: fractional values are not supported, use a smaller unit such as 1536m for 1.5g", memory)
+	}
+	match := memoryStringRegex.FindStringSubmatch(lower)
+	if match == nil {
+		return 0, fmt.Errorf("invalid memory %q: must be a whole number with an optional b, k, m, g, t or p suffix, e.g. 512m or 2g", memory)
+	}
+
+	unit := defaultUnit
+	if match[2] != "" {
+		suffixUnit, ok := byteUnits[match[2]]
+		if !ok {
+			return 0, fmt.Errorf("invalid memory %q: unknown suffix %q", memory, match[2])
+		}
+		unit = suffixUnit
+	}
+	value, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || value > math.MaxInt64/unit {
+		return 0, fmt.Errorf("invalid memory %q: value is too large", memory)
+	}
+	return value * unit, nil
+}
+
+// ToMiB parses the JVM memory string of a Spark memory setting, in MiB when it has no suffix. The result is rounded
+// down to whole MiB, as Spark does.
+func ToMiB(memory string) (int64, error) {
+	bytes, err := ParseBytes(memory, MiB)
+	if err != nil {
+		return 0, err
+	}
+	return bytes / MiB, nil
+}
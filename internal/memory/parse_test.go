@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Expected values are the ones Spark's JavaUtils.byteStringAs gives for the same strings
+func TestToMiB(t *testing.T) {
+	tests := []struct {
+		memory  string
+		want    int64
+		wantErr string
+	}{
+		{memory: "2048", want: 2048},
+		{memory: "100b", want: 0},
+		{memory: "1536k", want: 1},
+		{memory: "1536kb", want: 1},
+		{memory: "512m", want: 512},
+		{memory: "512MB", want: 512},
+		{memory: "1g", want: 1024},
+		{memory: "2Gb", want: 2048},
+		{memory: " 4G ", want: 4096},
+		{memory: "1t", want: 1024 * 1024},
+		{memory: "1pb", want: 1024 * 1024 * 1024},
+		{memory: "1.5g", wantErr: "fractional values are not supported"},
+		{memory: "0.5", wantErr: "fractional values are not supported"},
+		{memory: "", wantErr: "must be a whole number"},
+		{memory: "-1g", wantErr: "must be a whole number"},
+		{memory: "g", wantErr: "must be a whole number"},
+		{memory: "1x", wantErr: "unknown suffix"},
+		{memory: "1gib", wantErr: "unknown suffix"},
+		{memory: "99999999999p", wantErr: "too large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.memory, func(t *testing.T) {
+			got, err := ToMiB(tt.memory)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBytesProperties(t *testing.T) {
+	// Units up to g, so that no uint32 value overflows
+	units := []string{"b", "k", "kb", "m", "mb", "g", "gb"}
+
+	// Every whole value with a unit suffix is the value times the unit, whatever the case of the suffix
+	withUnit := func(value uint32, unitIndex uint8) bool {
+		suffix := units[int(unitIndex)%len(units)]
+		want := int64(value) * byteUnits[suffix]
+		lower, errLower := ParseBytes(fmt.Sprintf("%d%s", value, suffix), MiB)
+		upper, errUpper := ParseBytes(fmt.Sprintf("%d%s", value, strings.ToUpper(suffix)), MiB)
+		return errLower == nil && errUpper == nil && lower == want && upper == want
+	}
+	assert.NoError(t, quick.Check(withUnit, nil))
+
+	// Values without a suffix are in MiB, and converting them to MiB is lossless
+	withoutUnit := func(value uint32) bool {
+		got, err := ToMiB(fmt.Sprint(value))
+		return err == nil && got == int64(value)
+	}
+	assert.NoError(t, quick.Check(withoutUnit, nil))
+
+	// Fractional values are always rejected
+	fractional := func(value uint32, fraction uint8, unitIndex uint8) bool {
+		_, err := ParseBytes(fmt.Sprintf("%d.%d%s", value, fraction, units[int(unitIndex)%len(units)]), MiB)
+		return err != nil
+	}
+	assert.NoError(t, quick.Check(fractional, nil))
+}
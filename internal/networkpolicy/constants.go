@@ -0,0 +1,22 @@
+package networkpolicy
+
+import "nativesubmit/common"
+
+const (
+	// NetworkPolicyEnabledAnnotation enables the NetworkPolicy for a single application, it takes precedence over sparkConf.
+	NetworkPolicyEnabledAnnotation = common.LabelAnnotationPrefix + "network-policy-enabled"
+	// NetworkPolicyEnabledProperty enables the NetworkPolicy through sparkConf.
+	NetworkPolicyEnabledProperty = "spark.kubernetes.networkPolicy.enabled"
+	// UINamespaceSelectorProperty is a comma separated list of key=value namespace labels allowed to reach the Spark UI,
+	// the Prometheus exporter and the declared driver ports.
+	UINamespaceSelectorProperty = "spark.kubernetes.networkPolicy.ui.namespaceSelector"
+	// EgressCIDRsProperty is a comma separated list of CIDRs the driver and executors may connect to. Egress is not
+	// restricted when it is unset. DNS and the API server are always allowed.
+	EgressCIDRsProperty        = "spark.kubernetes.networkPolicy.egress.cidrs"
+	NetworkPolicyNameExtension = "-netpol"
+	CommaSeparator             = ","
+	DNSPort                    = 53
+	// APIServerServiceName and APIServerNamespace name the Service whose endpoints are the API server.
+	APIServerServiceName = "kubernetes"
+	APIServerNamespace   = "default"
+)
@@ -0,0 +1,308 @@
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// IsEnabled reports whether a NetworkPolicy should isolate the driver and executors of the Spark Application.
+// The application annotation takes precedence over the sparkConf property.
+func IsEnabled(app *v1beta2.SparkApplication) (bool, error) {
+	return common.IsEnabledByAnnotationOrConf(app, NetworkPolicyEnabledAnnotation, NetworkPolicyEnabledProperty)
+}
+
+// GetName returns the name of the NetworkPolicy isolating the Spark Application pods
+func GetName(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("%s%s", common.GetDriverPodName(app), NetworkPolicyNameExtension)
+}
+
+// Helper func to create NetworkPolicy for the Driver and Executor Pods of the Spark Application when enabled
+func Create(app *v1beta2.SparkApplication, config resolver.Config, kubeClient kubernetes.Interface, createdApplicationId string, driverPodUID string) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+
+	enabled, err := IsEnabled(app)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		log.Printf("NetworkPolicy not enabled for app: %s, skipping", app.Name)
+		return nil
+	}
+	log.Printf("=== Starting NetworkPolicy creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	networkPolicy, err := buildNetworkPolicy(app, config, kubeClient, createdApplicationId, driverPodUID)
+	if err != nil {
+		return err
+	}
+	log.Printf("NetworkPolicy name: %s, ingress rules: %d, egress rules: %d", networkPolicy.Name, len(networkPolicy.Spec.Ingress), len(networkPolicy.Spec.Egress))
+
+	createPolicyErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existingPolicy, err := kubeClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Get(context.TODO(), networkPolicy.Name, metav1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, createErr := kubeClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Create(context.TODO(), networkPolicy, metav1.CreateOptions{})
+			if createErr != nil {
+				log.Printf("ERROR: Failed to create NetworkPolicy: %v", createErr)
+			}
+			return createErr
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to get existing NetworkPolicy: %v", err)
+			return err
+		}
+
+		log.Printf("NetworkPolicy exists, updating...")
+		existingPolicy.Labels = networkPolicy.Labels
+		existingPolicy.OwnerReferences = networkPolicy.OwnerReferences
+		existingPolicy.Spec = networkPolicy.Spec
+		_, updateErr := kubeClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).Update(context.TODO(), existingPolicy, metav1.UpdateOptions{})
+		if updateErr != nil {
+			log.Printf("ERROR: Failed to update NetworkPolicy: %v", updateErr)
+		}
+		return updateErr
+	})
+
+	if createPolicyErr != nil {
+		return fmt.Errorf("error while creating network policy %s: %w", networkPolicy.Name, createPolicyErr)
+	}
+	log.Printf("=== Successfully completed NetworkPolicy creation ===")
+	return nil
+}
+
+// buildNetworkPolicy selects the driver and executors through the spark-app-selector label both carry, allows
+// driver<->executor traffic on the RPC and block manager ports, the Prometheus exporter and declared driver ports from
+// the application and the UI namespaces, the Spark UI from the UI namespaces, and restricts egress when egress CIDRs
+// are configured
+func buildNetworkPolicy(app *v1beta2.SparkApplication, config resolver.Config, kubeClient kubernetes.Interface, createdApplicationId string, driverPodUID string) (*networkingv1.NetworkPolicy, error) {
+	appPodSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{common.SparkApplicationSelectorLabel: createdApplicationId},
+	}
+
+	sparkPorts, err := getSparkPorts(config)
+	if err != nil {
+		return nil, err
+	}
+	appPeer := []networkingv1.NetworkPolicyPeer{{PodSelector: appPodSelector.DeepCopy()}}
+	uiPeer, err := getUINamespacePeer(app)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressRules := []networkingv1.NetworkPolicyIngressRule{
+		{From: appPeer, Ports: toPolicyPorts(sparkPorts, apiv1.ProtocolTCP)},
+	}
+
+	// The Prometheus exporter and the ports declared in the driver spec are reached from the application pods and, like
+	// the Spark UI, from the UI namespaces where monitoring and clients run
+	if exposedPorts := getExposedPorts(app); len(exposedPorts) > 0 {
+		exposedPeers := appPeer
+		if uiPeer != nil {
+			exposedPeers = append([]networkingv1.NetworkPolicyPeer{*uiPeer}, appPeer...)
+		}
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{From: exposedPeers, Ports: exposedPorts})
+	}
+
+	if uiPeer != nil {
+		uiPort, err := resolver.ParsePort(config.Driver.Ports[resolver.UIPortName])
+		if err != nil {
+			return nil, err
+		}
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{*uiPeer},
+			Ports: toPolicyPorts([]int32{uiPort}, apiv1.ProtocolTCP),
+		})
+	}
+
+	policyTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	var egressRules []networkingv1.NetworkPolicyEgressRule
+	if value, ok := app.Spec.SparkConf[EgressCIDRsProperty]; ok {
+		cidrPeers, err := getEgressPeers(value)
+		if err != nil {
+			return nil, err
+		}
+		// The driver creates and watches its executors through the API server
+		apiServerRule, err := getAPIServerEgressRule(kubeClient)
+		if err != nil {
+			return nil, err
+		}
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+		egressRules = []networkingv1.NetworkPolicyEgressRule{
+			{To: appPeer, Ports: toPolicyPorts(sparkPorts, apiv1.ProtocolTCP)},
+			// Name resolution of the driver service and external endpoints
+			{Ports: append(toPolicyPorts([]int32{DNSPort}, apiv1.ProtocolUDP), toPolicyPorts([]int32{DNSPort}, apiv1.ProtocolTCP)...)},
+			apiServerRule,
+			{To: cidrPeers},
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetName(app),
+			Namespace: common.GetAppNamespace(app),
+			Labels: map[string]string{
+				common.SparkApplicationSelectorLabel: createdApplicationId,
+				common.SparkAppNameLabel:             app.Name,
+			},
+			// Owned by the driver pod, which also owns the executors, so it is garbage collected with them
+			OwnerReferences: []metav1.OwnerReference{*common.GetServiceOwnerReference(app, driverPodUID)},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: appPodSelector,
+			PolicyTypes: policyTypes,
+			Ingress:     ingressRules,
+			Egress:      egressRules,
+		},
+	}, nil
+}
+
+// getSparkPorts returns the sorted, de-duplicated driver RPC, driver block manager and executor block manager ports
+func getSparkPorts(config resolver.Config) ([]int32, error) {
+	driverPorts := config.Driver.Ports
+	executorPorts := config.Executor.Ports
+	unique := make(map[int32]bool)
+	for _, setting := range []resolver.Setting{driverPorts[resolver.DriverPortName], driverPorts[resolver.BlockManagerPortName], executorPorts[resolver.BlockManagerPortName]} {
+		port, err := resolver.ParsePort(setting)
+		if err != nil {
+			return nil, err
+		}
+		unique[port] = true
+	}
+
+	ports := make([]int32, 0, len(unique))
+	for port := range unique {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports, nil
+}
+
+// getExposedPorts returns the Prometheus exporter port of the driver or executors and the ports declared in the
+// driver spec
+func getExposedPorts(app *v1beta2.SparkApplication) []networkingv1.NetworkPolicyPort {
+	var policyPorts []networkingv1.NetworkPolicyPort
+	if common.ExposesDriverMetrics(app) || common.ExposesExecutorMetrics(app) {
+		policyPorts = append(policyPorts, toPolicyPorts([]int32{common.GetPrometheusPort(app)}, apiv1.ProtocolTCP)...)
+	}
+	for _, port := range app.Spec.Driver.Ports {
+		policyPorts = append(policyPorts, toPolicyPorts([]int32{port.ContainerPort}, apiv1.Protocol(common.GetPortProtocol(port)))...)
+	}
+	return policyPorts
+}
+
+// getUINamespacePeer returns the namespaces allowed to reach the Spark UI and the exposed ports, nil when none are set
+func getUINamespacePeer(app *v1beta2.SparkApplication) (*networkingv1.NetworkPolicyPeer, error) {
+	value, ok := app.Spec.SparkConf[UINamespaceSelectorProperty]
+	if !ok {
+		return nil, nil
+	}
+	namespaceLabels, err := labels.ConvertSelectorToLabelsMap(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace selector %q for %s: %w", value, UINamespaceSelectorProperty, err)
+	}
+	return &networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: namespaceLabels}}, nil
+}
+
+// getAPIServerEgressRule allows the addresses and ports of the API server endpoints. NetworkPolicies match the
+// endpoints rather than the kubernetes Service, whose cluster IP is translated before the policy applies.
+func getAPIServerEgressRule(kubeClient kubernetes.Interface) (networkingv1.NetworkPolicyEgressRule, error) {
+	var rule networkingv1.NetworkPolicyEgressRule
+	endpointSlices, err := kubeClient.DiscoveryV1().EndpointSlices(APIServerNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.Set{discoveryv1.LabelServiceName: APIServerServiceName}.String(),
+	})
+	if err != nil {
+		return rule, fmt.Errorf("error while getting the API server endpoints to allow egress to them: %w", err)
+	}
+
+	addresses := make(map[string]bool)
+	ports := make(map[int32]bool)
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range endpointSlice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				addresses[address] = true
+			}
+		}
+		for _, port := range endpointSlice.Ports {
+			if port.Port != nil {
+				ports[*port.Port] = true
+			}
+		}
+	}
+	if len(addresses) == 0 || len(ports) == 0 {
+		return rule, fmt.Errorf("no endpoints found for the %s service in namespace %s, egress to the API server cannot be allowed", APIServerServiceName, APIServerNamespace)
+	}
+
+	for _, address := range sortedKeys(addresses) {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return rule, fmt.Errorf("invalid API server endpoint address %q", address)
+		}
+		prefixLength := 32
+		if ip.To4() == nil {
+			prefixLength = 128
+		}
+		rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: fmt.Sprintf("%s/%d", address, prefixLength)}})
+	}
+	portNumbers := make([]int32, 0, len(ports))
+	for port := range ports {
+		portNumbers = append(portNumbers, port)
+	}
+	sort.Slice(portNumbers, func(i, j int) bool { return portNumbers[i] < portNumbers[j] })
+	rule.Ports = toPolicyPorts(portNumbers, apiv1.ProtocolTCP)
+	return rule, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getEgressPeers(value string) ([]networkingv1.NetworkPolicyPeer, error) {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range strings.Split(value, CommaSeparator) {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid egress CIDR %q in %s: %w", cidr, EgressCIDRsProperty, err)
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("%s must list at least one CIDR", EgressCIDRsProperty)
+	}
+	return peers, nil
+}
+
+func toPolicyPorts(ports []int32, protocol apiv1.Protocol) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		portValue := intstr.FromInt32(port)
+		portProtocol := protocol
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{Protocol: &portProtocol, Port: &portValue})
+	}
+	return policyPorts
+}
@@ -0,0 +1,237 @@
+package networkpolicy
+
+import (
+	"context"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func getPolicy(t *testing.T, kubeClient *fake.Clientset) *networkingv1.NetworkPolicy {
+	t.Helper()
+	policy, err := kubeClient.NetworkingV1().NetworkPolicies("default").Get(context.TODO(), "test-app-driver-netpol", metav1.GetOptions{})
+	require.NoError(t, err)
+	return policy
+}
+
+func policyPortNumbers(ports []networkingv1.NetworkPolicyPort) []int32 {
+	var numbers []int32
+	for _, port := range ports {
+		numbers = append(numbers, port.Port.IntVal)
+	}
+	return numbers
+}
+
+func TestCreateSkippedWhenDisabled(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	_, err := kubeClient.NetworkingV1().NetworkPolicies("default").Get(context.TODO(), "test-app-driver-netpol", metav1.GetOptions{})
+	assert.True(t, apiErrors.IsNotFound(err))
+}
+
+func TestCreateDefaultPolicy(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Annotations = map[string]string{NetworkPolicyEnabledAnnotation: "true"}
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	policy := getPolicy(t, kubeClient)
+	assert.Equal(t, map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id"}, policy.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+	assert.Empty(t, policy.Spec.Egress)
+
+	require.Len(t, policy.Spec.Ingress, 1, "the UI stays closed without a namespace selector")
+	rule := policy.Spec.Ingress[0]
+	require.Len(t, rule.From, 1)
+	assert.Equal(t, map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id"}, rule.From[0].PodSelector.MatchLabels)
+	assert.Nil(t, rule.From[0].NamespaceSelector)
+	assert.Equal(t, []int32{7078, 7079}, policyPortNumbers(rule.Ports))
+	assert.Equal(t, apiv1.ProtocolTCP, *rule.Ports[0].Protocol)
+
+	require.Len(t, policy.OwnerReferences, 1)
+	assert.Equal(t, "Pod", policy.OwnerReferences[0].Kind)
+	assert.Equal(t, "test-app-driver", policy.OwnerReferences[0].Name)
+	assert.Equal(t, "driver-pod-uid", string(policy.OwnerReferences[0].UID))
+}
+
+// apiServerEndpoints is the EndpointSlice of the kubernetes Service, as created by the API server
+func apiServerEndpoints(port int32, addresses ...string) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubernetes",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "kubernetes"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: addresses}},
+		Ports:       []discoveryv1.EndpointPort{{Port: common.Int32Pointer(port)}},
+	}
+}
+
+func TestCreateConfiguredPolicy(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(apiServerEndpoints(6443, "172.18.0.3", "172.18.0.2"))
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: map[string]string{
+		NetworkPolicyEnabledProperty:     "true",
+		"spark.driver.port":              "30000",
+		"spark.driver.blockManager.port": "30001",
+		"spark.blockManager.port":        "30002",
+		"spark.ui.port":                  "4050",
+		UINamespaceSelectorProperty:      "team=data,env=prod",
+		EgressCIDRsProperty:              "10.0.0.0/8, 192.168.1.10/32",
+	}})
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	policy := getPolicy(t, kubeClient)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+
+	require.Len(t, policy.Spec.Ingress, 2)
+	assert.Equal(t, []int32{30000, 30001, 30002}, policyPortNumbers(policy.Spec.Ingress[0].Ports))
+	uiRule := policy.Spec.Ingress[1]
+	assert.Equal(t, map[string]string{"team": "data", "env": "prod"}, uiRule.From[0].NamespaceSelector.MatchLabels)
+	assert.Nil(t, uiRule.From[0].PodSelector)
+	assert.Equal(t, []int32{4050}, policyPortNumbers(uiRule.Ports))
+
+	require.Len(t, policy.Spec.Egress, 4)
+	assert.Equal(t, []int32{30000, 30001, 30002}, policyPortNumbers(policy.Spec.Egress[0].Ports))
+	assert.Equal(t, "spark-app-id", policy.Spec.Egress[0].To[0].PodSelector.MatchLabels[common.SparkApplicationSelectorLabel])
+	assert.Equal(t, []int32{53, 53}, policyPortNumbers(policy.Spec.Egress[1].Ports))
+	assert.Empty(t, policy.Spec.Egress[1].To, "DNS is allowed to any destination")
+	apiServerRule := policy.Spec.Egress[2]
+	require.Len(t, apiServerRule.To, 2)
+	assert.Equal(t, "172.18.0.2/32", apiServerRule.To[0].IPBlock.CIDR)
+	assert.Equal(t, "172.18.0.3/32", apiServerRule.To[1].IPBlock.CIDR)
+	assert.Equal(t, []int32{6443}, policyPortNumbers(apiServerRule.Ports))
+	require.Len(t, policy.Spec.Egress[3].To, 2)
+	assert.Equal(t, "10.0.0.0/8", policy.Spec.Egress[3].To[0].IPBlock.CIDR)
+	assert.Equal(t, "192.168.1.10/32", policy.Spec.Egress[3].To[1].IPBlock.CIDR)
+}
+
+func TestCreateEgressWithoutAPIServerEndpoints(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: map[string]string{
+		NetworkPolicyEnabledProperty: "true",
+		EgressCIDRsProperty:          "10.0.0.0/8",
+	}})
+	err := Create(app, resolver.Resolve(app), fake.NewSimpleClientset(), "spark-app-id", "driver-pod-uid")
+	assert.ErrorContains(t, err, "no endpoints found for the kubernetes service")
+}
+
+func TestCreateExposedPorts(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      v1beta2.SparkApplicationSpec
+		wantPorts []int32
+		wantProto []apiv1.Protocol
+	}{
+		{
+			name: "driver metrics",
+			spec: v1beta2.SparkApplicationSpec{Monitoring: &v1beta2.MonitoringSpec{
+				ExposeDriverMetrics: true,
+				Prometheus:          &v1beta2.PrometheusSpec{Port: common.Int32Pointer(9100)},
+			}},
+			wantPorts: []int32{9100},
+			wantProto: []apiv1.Protocol{apiv1.ProtocolTCP},
+		},
+		{
+			name: "executor metrics on the default port",
+			spec: v1beta2.SparkApplicationSpec{Monitoring: &v1beta2.MonitoringSpec{
+				ExposeExecutorMetrics: true,
+				Prometheus:            &v1beta2.PrometheusSpec{},
+			}},
+			wantPorts: []int32{8090},
+			wantProto: []apiv1.Protocol{apiv1.ProtocolTCP},
+		},
+		{
+			name: "declared driver ports",
+			spec: v1beta2.SparkApplicationSpec{Driver: v1beta2.DriverSpec{Ports: []v1beta2.Port{
+				{Name: "thrift", ContainerPort: 10000},
+				{Name: "statsd", Protocol: "udp", ContainerPort: 8125},
+			}}},
+			wantPorts: []int32{10000, 8125},
+			wantProto: []apiv1.Protocol{apiv1.ProtocolTCP, apiv1.ProtocolUDP},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			app := testutil.NewSparkApplication(tt.spec)
+			app.Annotations = map[string]string{NetworkPolicyEnabledAnnotation: "true"}
+			require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+			policy := getPolicy(t, kubeClient)
+			require.Len(t, policy.Spec.Ingress, 2)
+			exposedRule := policy.Spec.Ingress[1]
+			require.Len(t, exposedRule.From, 1, "exposed ports are only open to the application pods")
+			assert.Equal(t, "spark-app-id", exposedRule.From[0].PodSelector.MatchLabels[common.SparkApplicationSelectorLabel])
+			assert.Equal(t, tt.wantPorts, policyPortNumbers(exposedRule.Ports))
+			for i, port := range exposedRule.Ports {
+				assert.Equal(t, tt.wantProto[i], *port.Protocol)
+			}
+		})
+	}
+}
+
+func TestCreateExposedPortsFromUINamespaces(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+		SparkConf: map[string]string{NetworkPolicyEnabledProperty: "true", UINamespaceSelectorProperty: "team=monitoring"},
+		Driver:    v1beta2.DriverSpec{Ports: []v1beta2.Port{{Name: "thrift", ContainerPort: 10000}}},
+	})
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	policy := getPolicy(t, kubeClient)
+	require.Len(t, policy.Spec.Ingress, 3)
+	exposedRule := policy.Spec.Ingress[1]
+	require.Len(t, exposedRule.From, 2)
+	assert.Equal(t, map[string]string{"team": "monitoring"}, exposedRule.From[0].NamespaceSelector.MatchLabels)
+	assert.Equal(t, "spark-app-id", exposedRule.From[1].PodSelector.MatchLabels[common.SparkApplicationSelectorLabel])
+	assert.Equal(t, []int32{10000}, policyPortNumbers(exposedRule.Ports))
+}
+
+func TestCreateUpdatesExisting(t *testing.T) {
+	existing := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-app-driver-netpol", Namespace: "default"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{common.SparkApplicationSelectorLabel: "old-app-id"}},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(existing)
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Annotations = map[string]string{NetworkPolicyEnabledAnnotation: "true"}
+	require.NoError(t, Create(app, resolver.Resolve(app), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	policy := getPolicy(t, kubeClient)
+	assert.Equal(t, "spark-app-id", policy.Spec.PodSelector.MatchLabels[common.SparkApplicationSelectorLabel])
+	assert.Equal(t, "driver-pod-uid", string(policy.OwnerReferences[0].UID))
+}
+
+func TestCreateInvalidConfiguration(t *testing.T) {
+	tests := []struct {
+		name      string
+		sparkConf map[string]string
+	}{
+		{name: "invalid enabled flag", sparkConf: map[string]string{NetworkPolicyEnabledProperty: "sometimes"}},
+		{name: "invalid port", sparkConf: map[string]string{NetworkPolicyEnabledProperty: "true", "spark.blockManager.port": "70000"}},
+		{name: "invalid namespace selector", sparkConf: map[string]string{NetworkPolicyEnabledProperty: "true", UINamespaceSelectorProperty: "team"}},
+		{name: "invalid egress CIDR", sparkConf: map[string]string{NetworkPolicyEnabledProperty: "true", EgressCIDRsProperty: "10.0.0.0/33"}},
+		{name: "empty egress CIDRs", sparkConf: map[string]string{NetworkPolicyEnabledProperty: "true", EgressCIDRsProperty: " , "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: tt.sparkConf})
+			assert.Error(t, Create(app, resolver.Resolve(app), fake.NewSimpleClientset(), "spark-app-id", "driver-pod-uid"))
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package pdb
+
+import "nativesubmit/common"
+
+const (
+	// DriverPDBEnabledAnnotation enables the driver PodDisruptionBudget for a single application, it takes precedence over sparkConf.
+	DriverPDBEnabledAnnotation = common.LabelAnnotationPrefix + "driver-pdb-enabled"
+	// DriverPDBEnabledProperty enables the driver PodDisruptionBudget through sparkConf.
+	DriverPDBEnabledProperty = "spark.kubernetes.driver.podDisruptionBudget.enabled"
+	PDBNameExtension         = "-pdb"
+	DriverPDBMinAvailable    = 1
+)
@@ -0,0 +1,101 @@
+package pdb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	policyv1 "k8s.io/api/policy/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// IsEnabled reports whether a PodDisruptionBudget should protect the driver pod of the Spark Application.
+// The application annotation takes precedence over the sparkConf property.
+func IsEnabled(app *v1beta2.SparkApplication) (bool, error) {
+	return common.IsEnabledByAnnotationOrConf(app, DriverPDBEnabledAnnotation, DriverPDBEnabledProperty)
+}
+
+// GetName returns the name of the PodDisruptionBudget protecting the driver pod
+func GetName(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("%s%s", common.GetDriverPodName(app), PDBNameExtension)
+}
+
+// Helper func to create PodDisruptionBudget for the Driver Pod of the Spark Application when enabled
+func Create(app *v1beta2.SparkApplication, kubeClient kubernetes.Interface, createdApplicationId string, driverPodUID string) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+
+	enabled, err := IsEnabled(app)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		log.Printf("Driver PodDisruptionBudget not enabled for app: %s, skipping", app.Name)
+		return nil
+	}
+	log.Printf("=== Starting Driver PodDisruptionBudget creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	minAvailable := intstr.FromInt32(DriverPDBMinAvailable)
+	driverPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetName(app),
+			Namespace: common.GetAppNamespace(app),
+			Labels: map[string]string{
+				common.SparkApplicationSelectorLabel: createdApplicationId,
+				common.SparkAppNameLabel:             app.Name,
+			},
+			// Owned by the driver pod, like the driver service, so it is garbage collected with the driver
+			OwnerReferences: []metav1.OwnerReference{*common.GetServiceOwnerReference(app, driverPodUID)},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{
+				// The executors share the application label, only the driver is protected
+				MatchLabels: map[string]string{
+					common.SparkApplicationSelectorLabel: createdApplicationId,
+					common.SparkRoleLabel:                common.SparkDriverRole,
+				},
+			},
+		},
+	}
+	log.Printf("Driver PodDisruptionBudget name: %s, selector: %v", driverPDB.Name, driverPDB.Spec.Selector.MatchLabels)
+
+	createPDBErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existingPDB, err := kubeClient.PolicyV1().PodDisruptionBudgets(driverPDB.Namespace).Get(context.TODO(), driverPDB.Name, metav1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, createErr := kubeClient.PolicyV1().PodDisruptionBudgets(driverPDB.Namespace).Create(context.TODO(), driverPDB, metav1.CreateOptions{})
+			if createErr != nil {
+				log.Printf("ERROR: Failed to create PodDisruptionBudget: %v", createErr)
+			}
+			return createErr
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to get existing PodDisruptionBudget: %v", err)
+			return err
+		}
+
+		log.Printf("PodDisruptionBudget exists, updating...")
+		existingPDB.Labels = driverPDB.Labels
+		existingPDB.OwnerReferences = driverPDB.OwnerReferences
+		existingPDB.Spec = driverPDB.Spec
+		_, updateErr := kubeClient.PolicyV1().PodDisruptionBudgets(driverPDB.Namespace).Update(context.TODO(), existingPDB, metav1.UpdateOptions{})
+		if updateErr != nil {
+			log.Printf("ERROR: Failed to update PodDisruptionBudget: %v", updateErr)
+		}
+		return updateErr
+	})
+
+	if createPDBErr != nil {
+		return fmt.Errorf("error while creating driver pod disruption budget %s: %w", driverPDB.Name, createPDBErr)
+	}
+	log.Printf("=== Successfully completed Driver PodDisruptionBudget creation ===")
+	return nil
+}
@@ -0,0 +1,121 @@
+package pdb
+
+import (
+	"context"
+	"nativesubmit/common"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsEnabled(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		sparkConf   map[string]string
+		want        bool
+		wantErr     bool
+	}{
+		{name: "not configured"},
+		{name: "enabled by annotation", annotations: map[string]string{DriverPDBEnabledAnnotation: "true"}, want: true},
+		{name: "enabled by sparkConf", sparkConf: map[string]string{DriverPDBEnabledProperty: "true"}, want: true},
+		{
+			name:        "annotation overrides sparkConf",
+			annotations: map[string]string{DriverPDBEnabledAnnotation: "false"},
+			sparkConf:   map[string]string{DriverPDBEnabledProperty: "true"},
+		},
+		{name: "invalid annotation", annotations: map[string]string{DriverPDBEnabledAnnotation: "yes please"}, wantErr: true},
+		{name: "invalid sparkConf", sparkConf: map[string]string{DriverPDBEnabledProperty: "maybe"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: tt.sparkConf})
+			app.Annotations = tt.annotations
+			got, err := IsEnabled(app)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateSkippedWhenDisabled(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	require.NoError(t, Create(testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{}), kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	_, err := kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "test-app-driver-pdb", metav1.GetOptions{})
+	assert.True(t, apiErrors.IsNotFound(err))
+}
+
+func TestCreate(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Annotations = map[string]string{DriverPDBEnabledAnnotation: "true"}
+	require.NoError(t, Create(app, kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	pdb, err := kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "test-app-driver-pdb", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		common.SparkApplicationSelectorLabel: "spark-app-id",
+		common.SparkRoleLabel:                common.SparkDriverRole,
+	}, pdb.Spec.Selector.MatchLabels)
+	assert.Equal(t, intstr.FromInt32(1), *pdb.Spec.MinAvailable)
+	require.Len(t, pdb.OwnerReferences, 1)
+	assert.Equal(t, "Pod", pdb.OwnerReferences[0].Kind)
+	assert.Equal(t, "test-app-driver", pdb.OwnerReferences[0].Name)
+	assert.Equal(t, "driver-pod-uid", string(pdb.OwnerReferences[0].UID))
+}
+
+func TestCreateUpdatesExisting(t *testing.T) {
+	existing := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-app-driver-pdb", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{common.SparkApplicationSelectorLabel: "old-app-id"}},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(existing)
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: map[string]string{DriverPDBEnabledProperty: "true"}})
+	require.NoError(t, Create(app, kubeClient, "spark-app-id", "driver-pod-uid"))
+
+	pdb, err := kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "test-app-driver-pdb", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "spark-app-id", pdb.Spec.Selector.MatchLabels[common.SparkApplicationSelectorLabel])
+	assert.Equal(t, "driver-pod-uid", string(pdb.OwnerReferences[0].UID))
+}
+
+func TestCreateSelectsOnlyTheDriver(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: map[string]string{DriverPDBEnabledProperty: "true"}})
+	require.NoError(t, Create(app, kubeClient, "spark-app-id", "driver-pod-uid"))
+	pdb, err := kubeClient.PolicyV1().PodDisruptionBudgets("default").Get(context.TODO(), "test-app-driver-pdb", metav1.GetOptions{})
+	require.NoError(t, err)
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		podLabels map[string]string
+		want      bool
+	}{
+		{name: "driver", podLabels: map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id", common.SparkRoleLabel: common.SparkDriverRole}, want: true},
+		{name: "executor of the application", podLabels: map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id", common.SparkRoleLabel: common.SparkExecutorRole}},
+		{name: "driver of another application", podLabels: map[string]string{common.SparkApplicationSelectorLabel: "other-app-id", common.SparkRoleLabel: common.SparkDriverRole}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selector.Matches(labels.Set(tt.podLabels)))
+		})
+	}
+}
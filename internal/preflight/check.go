@@ -0,0 +1,254 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/driver"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/scheduler"
+	"strings"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// footprint is the projected resource usage of the driver and the initial executors
+type footprint struct {
+	driver    apiv1.ResourceRequirements
+	executor  apiv1.ResourceRequirements
+	executors int
+}
+
+// Helper func to check the driver and the initial executors fit the LimitRanges and ResourceQuotas of the application
+// namespace, before any resource of the Spark Application is created. LimitRange defaults are applied to the
+// projected requests and limits, as the API server does when the pods are created. config is the resolved configuration
+// of the submission.
+func Check(app *v1beta2.SparkApplication, config resolver.Config, kubeClient kubernetes.Interface) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+	namespace := common.GetAppNamespace(app)
+	log.Printf("=== Starting pre-flight checks for app: %s, namespace: %s ===", app.Name, namespace)
+
+	projected, err := getFootprint(app, config)
+	if err != nil {
+		return err
+	}
+
+	limitRanges, err := kubeClient.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+	if apiErrors.IsForbidden(err) {
+		log.Printf("Not allowed to list LimitRanges in namespace %s, skipping their check", namespace)
+	} else if err != nil {
+		return fmt.Errorf("error while listing limit ranges in namespace %s: %w", namespace, err)
+	} else {
+		for _, limitRange := range limitRanges.Items {
+			applyLimitRangeDefaults(&projected.driver, limitRange)
+			applyLimitRangeDefaults(&projected.executor, limitRange)
+			if err := checkLimitRange(common.SparkDriverRole, projected.driver, limitRange); err != nil {
+				return err
+			}
+			if projected.executors > 0 {
+				if err := checkLimitRange(common.SparkExecutorRole, projected.executor, limitRange); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	resourceQuotas, err := kubeClient.CoreV1().ResourceQuotas(namespace).List(context.TODO(), metav1.ListOptions{})
+	if apiErrors.IsForbidden(err) {
+		log.Printf("Not allowed to list ResourceQuotas in namespace %s, skipping their check", namespace)
+	} else if err != nil {
+		return fmt.Errorf("error while listing resource quotas in namespace %s: %w", namespace, err)
+	} else {
+		for _, resourceQuota := range resourceQuotas.Items {
+			if err := checkResourceQuota(projected, resourceQuota); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Printf("=== Successfully completed pre-flight checks ===")
+	return nil
+}
+
+// getFootprint computes the driver requirements as the driver pod does, and the requirements of one executor
+func getFootprint(app *v1beta2.SparkApplication, config resolver.Config) (*footprint, error) {
+	executorRequests, err := scheduler.GetExecutorResources(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid executor resources: %w", err)
+	}
+	executors, err := scheduler.GetExecutorInstances(app)
+	if err != nil {
+		return nil, err
+	}
+
+	// Spark sets the executor memory limit to its request, and a cpu limit only when configured
+	executorLimits := apiv1.ResourceList{apiv1.ResourceMemory: executorRequests[apiv1.ResourceMemory]}
+	if coreLimit := config.Executor.CoreLimit; coreLimit.Value != "" {
+		cpuLimit, err := resource.ParseQuantity(coreLimit.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid executor core limit %q in %s: %w", coreLimit.Value, coreLimit.Describe(), err)
+		}
+		executorLimits[apiv1.ResourceCPU] = cpuLimit
+	}
+
+	driverRequirements, err := driver.GetResourceRequirements(config.Driver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver resources: %w", err)
+	}
+	projected := &footprint{
+		driver:    driverRequirements,
+		executor:  apiv1.ResourceRequirements{Requests: executorRequests, Limits: executorLimits},
+		executors: executors,
+	}
+	defaultRequestsToLimits(&projected.driver)
+	return projected, nil
+}
+
+// defaultRequestsToLimits sets the missing requests to their limits, as the API server does
+func defaultRequestsToLimits(requirements *apiv1.ResourceRequirements) {
+	for name, limit := range requirements.Limits {
+		if _, ok := requirements.Requests[name]; !ok {
+			if requirements.Requests == nil {
+				requirements.Requests = apiv1.ResourceList{}
+			}
+			requirements.Requests[name] = limit
+		}
+	}
+}
+
+// applyLimitRangeDefaults sets the container default limits and requests of the LimitRange that are missing
+func applyLimitRangeDefaults(requirements *apiv1.ResourceRequirements, limitRange apiv1.LimitRange) {
+	for _, item := range limitRange.Spec.Limits {
+		if item.Type != apiv1.LimitTypeContainer {
+			continue
+		}
+		for name, limit := range item.Default {
+			if _, ok := requirements.Limits[name]; !ok {
+				if requirements.Limits == nil {
+					requirements.Limits = apiv1.ResourceList{}
+				}
+				requirements.Limits[name] = limit
+			}
+		}
+		for name, request := range item.DefaultRequest {
+			if _, ok := requirements.Requests[name]; !ok {
+				if requirements.Requests == nil {
+					requirements.Requests = apiv1.ResourceList{}
+				}
+				requirements.Requests[name] = request
+			}
+		}
+		defaultRequestsToLimits(requirements)
+	}
+}
+
+// checkLimitRange checks the container and pod minimum, maximum and limit to request ratio of the LimitRange, the
+// driver and executor pods having a single Spark container
+func checkLimitRange(role string, requirements apiv1.ResourceRequirements, limitRange apiv1.LimitRange) error {
+	for _, item := range limitRange.Spec.Limits {
+		if item.Type != apiv1.LimitTypeContainer && item.Type != apiv1.LimitTypePod {
+			continue
+		}
+		for name, min := range item.Min {
+			if request, ok := requirements.Requests[name]; ok && request.Cmp(min) < 0 {
+				return fmt.Errorf("%s %s request %s is below the minimum %s of LimitRange %s in namespace %s",
+					role, name, request.String(), min.String(), limitRange.Name, limitRange.Namespace)
+			}
+		}
+		for name, max := range item.Max {
+			limit, ok := requirements.Limits[name]
+			if !ok {
+				return fmt.Errorf("%s has no %s limit, LimitRange %s in namespace %s requires one with a maximum of %s",
+					role, name, limitRange.Name, limitRange.Namespace, max.String())
+			}
+			if limit.Cmp(max) > 0 {
+				return fmt.Errorf("%s %s limit %s is above the maximum %s of LimitRange %s in namespace %s",
+					role, name, limit.String(), max.String(), limitRange.Name, limitRange.Namespace)
+			}
+		}
+		for name, maxRatio := range item.MaxLimitRequestRatio {
+			limit, hasLimit := requirements.Limits[name]
+			request, hasRequest := requirements.Requests[name]
+			if !hasLimit || !hasRequest || request.IsZero() {
+				continue
+			}
+			ratio := float64(limit.MilliValue()) / float64(request.MilliValue())
+			if ratio > maxRatio.AsApproximateFloat64() {
+				return fmt.Errorf("%s %s limit %s to request %s ratio is above the maximum %s of LimitRange %s in namespace %s",
+					role, name, limit.String(), request.String(), maxRatio.String(), limitRange.Name, limitRange.Namespace)
+			}
+		}
+	}
+	return nil
+}
+
+// checkResourceQuota checks the driver and the initial executors fit the pods and the cpu, memory and
+// ephemeral-storage requests and limits left in the quota. Other resources, such as storage or extended resources,
+// are not set on the Spark pods and are skipped.
+func checkResourceQuota(projected *footprint, resourceQuota apiv1.ResourceQuota) error {
+	if len(resourceQuota.Spec.Scopes) > 0 || resourceQuota.Spec.ScopeSelector != nil {
+		log.Printf("ResourceQuota %s is scoped, skipping its check", resourceQuota.Name)
+		return nil
+	}
+
+	for name, hard := range resourceQuota.Spec.Hard {
+		var needed resource.Quantity
+		switch {
+		case name == apiv1.ResourcePods || name == QuotaPodsCount:
+			needed = *resource.NewQuantity(int64(1+projected.executors), resource.DecimalSI)
+		case strings.HasPrefix(string(name), QuotaLimitsPrefix):
+			resourceName := apiv1.ResourceName(strings.TrimPrefix(string(name), QuotaLimitsPrefix))
+			if !isQuotaCheckedResource(resourceName) {
+				continue
+			}
+			needed = getQuotaUsage(projected, resourceName, false)
+		default:
+			resourceName := apiv1.ResourceName(strings.TrimPrefix(string(name), QuotaRequestsPrefix))
+			if !isQuotaCheckedResource(resourceName) {
+				continue
+			}
+			needed = getQuotaUsage(projected, resourceName, true)
+		}
+
+		available := hard.DeepCopy()
+		if used, ok := resourceQuota.Status.Used[name]; ok {
+			available.Sub(used)
+		}
+		if needed.Cmp(available) > 0 {
+			return fmt.Errorf("driver and %d executors need %s=%s but ResourceQuota %s in namespace %s has %s of %s left",
+				projected.executors, name, needed.String(), resourceQuota.Name, resourceQuota.Namespace, available.String(), hard.String())
+		}
+	}
+	return nil
+}
+
+// isQuotaCheckedResource reports whether a quota resource is one the driver and executor pods set
+func isQuotaCheckedResource(name apiv1.ResourceName) bool {
+	return name == apiv1.ResourceCPU || name == apiv1.ResourceMemory || name == apiv1.ResourceEphemeralStorage
+}
+
+// getQuotaUsage sums the driver and initial executors requests, or limits, of a resource, a pod not setting it
+// using none
+func getQuotaUsage(projected *footprint, name apiv1.ResourceName, requests bool) resource.Quantity {
+	resources := func(requirements apiv1.ResourceRequirements) apiv1.ResourceList { return requirements.Limits }
+	if requests {
+		resources = func(requirements apiv1.ResourceRequirements) apiv1.ResourceList { return requirements.Requests }
+	}
+
+	total := resources(projected.driver)[name]
+	total = total.DeepCopy()
+	executorQuantity := resources(projected.executor)[name]
+	for i := 0; i < projected.executors; i++ {
+		total.Add(executorQuantity)
+	}
+	return total
+}
@@ -0,0 +1,176 @@
+package preflight
+
+import (
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newResourceQuota(hard apiv1.ResourceList, used apiv1.ResourceList) *apiv1.ResourceQuota {
+	return &apiv1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-quota", Namespace: "default"},
+		Spec:       apiv1.ResourceQuotaSpec{Hard: hard},
+		Status:     apiv1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func newLimitRange(items ...apiv1.LimitRangeItem) *apiv1.LimitRange {
+	return &apiv1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-limits", Namespace: "default"},
+		Spec:       apiv1.LimitRangeSpec{Limits: items},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		modify     func(app *v1beta2.SparkApplication)
+		wantErrMsg string
+	}{
+		{name: "no quotas or limit ranges"},
+		{
+			name: "quota fits",
+			objects: []runtime.Object{newResourceQuota(
+				apiv1.ResourceList{"requests.cpu": resource.MustParse("4"), "requests.memory": resource.MustParse("8Gi"), "pods": resource.MustParse("3")},
+				apiv1.ResourceList{"requests.cpu": resource.MustParse("1")},
+			)},
+		},
+		{
+			name: "requests exceed quota",
+			objects: []runtime.Object{newResourceQuota(
+				apiv1.ResourceList{"requests.memory": resource.MustParse("8Gi")},
+				apiv1.ResourceList{"requests.memory": resource.MustParse("6Gi")},
+			)},
+			wantErrMsg: "driver and 2 executors need requests.memory=4224Mi but ResourceQuota team-quota in namespace default has 2Gi of 8Gi left",
+		},
+		{
+			name:       "plain cpu quota counts requests",
+			objects:    []runtime.Object{newResourceQuota(apiv1.ResourceList{"cpu": resource.MustParse("2")}, nil)},
+			wantErrMsg: "need cpu=3",
+		},
+		{
+			name:       "pods exceed quota",
+			objects:    []runtime.Object{newResourceQuota(apiv1.ResourceList{"count/pods": resource.MustParse("5")}, apiv1.ResourceList{"count/pods": resource.MustParse("3")})},
+			wantErrMsg: "need count/pods=3",
+		},
+		{
+			name:    "limits quota without cpu limit counts none",
+			objects: []runtime.Object{newResourceQuota(apiv1.ResourceList{"limits.cpu": resource.MustParse("1")}, apiv1.ResourceList{"limits.cpu": resource.MustParse("1")})},
+		},
+		{
+			name: "storage and extended resource quotas are skipped",
+			objects: []runtime.Object{newResourceQuota(
+				apiv1.ResourceList{"requests.storage": resource.MustParse("10Gi"), "persistentvolumeclaims": resource.MustParse("2"), "requests.nvidia.com/gpu": resource.MustParse("1")},
+				apiv1.ResourceList{"requests.storage": resource.MustParse("10Gi"), "requests.nvidia.com/gpu": resource.MustParse("1")},
+			)},
+		},
+		{
+			name: "ephemeral storage quota with limit range default",
+			objects: []runtime.Object{
+				newResourceQuota(apiv1.ResourceList{"requests.ephemeral-storage": resource.MustParse("2Gi")}, nil),
+				newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypeContainer, DefaultRequest: apiv1.ResourceList{"ephemeral-storage": resource.MustParse("1Gi")}}),
+			},
+			wantErrMsg: "need requests.ephemeral-storage=3Gi",
+		},
+		{
+			name: "limits quota with limit range default",
+			objects: []runtime.Object{
+				newResourceQuota(apiv1.ResourceList{"limits.cpu": resource.MustParse("6")}, nil),
+				newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypeContainer, Default: apiv1.ResourceList{"cpu": resource.MustParse("2")}}),
+			},
+		},
+		{
+			name: "limits quota exceeded with limit range default",
+			objects: []runtime.Object{
+				newResourceQuota(apiv1.ResourceList{"limits.cpu": resource.MustParse("5")}, nil),
+				newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypeContainer, Default: apiv1.ResourceList{"cpu": resource.MustParse("2")}}),
+			},
+			wantErrMsg: "need limits.cpu=6",
+		},
+		{
+			name: "scoped quota is skipped",
+			objects: []runtime.Object{&apiv1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "best-effort", Namespace: "default"},
+				Spec:       apiv1.ResourceQuotaSpec{Hard: apiv1.ResourceList{"pods": resource.MustParse("0")}, Scopes: []apiv1.ResourceQuotaScope{apiv1.ResourceQuotaScopeBestEffort}},
+			}},
+		},
+		{
+			name:       "limit range maximum",
+			objects:    []runtime.Object{newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypeContainer, Max: apiv1.ResourceList{"memory": resource.MustParse("1Gi")}})},
+			wantErrMsg: "driver memory limit 1408Mi is above the maximum 1Gi of LimitRange team-limits in namespace default",
+		},
+		{
+			name:       "limit range maximum requires a limit",
+			objects:    []runtime.Object{newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypePod, Max: apiv1.ResourceList{"cpu": resource.MustParse("4")}})},
+			wantErrMsg: "driver has no cpu limit, LimitRange team-limits in namespace default requires one with a maximum of 4",
+		},
+		{
+			name:    "limit range minimum",
+			objects: []runtime.Object{newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypeContainer, Min: apiv1.ResourceList{"cpu": resource.MustParse("1500m")}})},
+			modify: func(app *v1beta2.SparkApplication) {
+				driverCores := int32(2)
+				app.Spec.Driver.Cores = &driverCores
+			},
+			wantErrMsg: "executor cpu request 1 is below the minimum 1500m of LimitRange team-limits in namespace default",
+		},
+		{
+			name:    "limit range ratio",
+			objects: []runtime.Object{newLimitRange(apiv1.LimitRangeItem{Type: apiv1.LimitTypeContainer, MaxLimitRequestRatio: apiv1.ResourceList{"cpu": resource.MustParse("2")}})},
+			modify: func(app *v1beta2.SparkApplication) {
+				coreLimit := "4"
+				app.Spec.Driver.CoreLimit = &coreLimit
+			},
+			wantErrMsg: "driver cpu limit 4 to request 1 ratio is above the maximum 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+				Type:     v1beta2.SparkApplicationTypeScala,
+				Driver:   v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: common.Int32Pointer(1), Memory: common.StringPointer("1g")}},
+				Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: common.Int32Pointer(1), Memory: common.StringPointer("1g")}},
+			})
+			if tt.modify != nil {
+				tt.modify(app)
+			}
+			err := Check(app, resolver.Resolve(app), fake.NewSimpleClientset(tt.objects...))
+			if tt.wantErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckForbidden(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(newResourceQuota(apiv1.ResourceList{"pods": resource.MustParse("0")}, nil))
+	kubeClient.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apiErrors.NewForbidden(schema.GroupResource{Resource: action.GetResource().Resource}, "", nil)
+	})
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+		Type:     v1beta2.SparkApplicationTypeScala,
+		Driver:   v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: common.Int32Pointer(1), Memory: common.StringPointer("1g")}},
+		Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: common.Int32Pointer(1), Memory: common.StringPointer("1g")}},
+	})
+	assert.NoError(t, Check(app, resolver.Resolve(app), kubeClient), "checks the submitter cannot read are skipped")
+}
+
+func TestCheckNilApplication(t *testing.T) {
+	assert.Error(t, Check(nil, resolver.Config{}, fake.NewSimpleClientset()))
+}
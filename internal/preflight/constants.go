@@ -0,0 +1,8 @@
+package preflight
+
+const (
+	// Quota resources without a requests. or limits. prefix count requests.
+	QuotaRequestsPrefix = "requests."
+	QuotaLimitsPrefix   = "limits."
+	QuotaPodsCount      = "count/pods"
+)
@@ -0,0 +1,312 @@
+package reference
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/driver"
+	"nativesubmit/internal/resolver"
+	"sort"
+	"strings"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reference is an object of the application namespace the Spark Application refers to
+type Reference struct {
+	Kind string
+	Name string
+	// Field is the path of the field holding the reference
+	Field string
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%s %s (%s)", r.Kind, r.Name, r.Field)
+}
+
+// MissingReferencesError lists every reference of the Spark Application missing from its namespace, and in strict
+// mode the references the submitter cannot read
+type MissingReferencesError struct {
+	Namespace  string
+	Missing    []Reference
+	Unreadable []Reference
+}
+
+func (e *MissingReferencesError) Error() string {
+	var messages []string
+	if len(e.Missing) > 0 {
+		messages = append(messages, fmt.Sprintf("%d referenced objects are missing in namespace %s: %s", len(e.Missing), e.Namespace, joinReferences(e.Missing)))
+	}
+	if len(e.Unreadable) > 0 {
+		messages = append(messages, fmt.Sprintf("%d referenced objects cannot be read in namespace %s: %s", len(e.Unreadable), e.Namespace, joinReferences(e.Unreadable)))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func joinReferences(references []Reference) string {
+	names := make([]string, 0, len(references))
+	for _, reference := range references {
+		names = append(names, reference.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// Helper func to resolve every object the Spark Application refers to in its namespace before anything is created.
+// All the missing references are returned together in a MissingReferencesError, unless the namespace makes the check
+// advisory. config is the resolved configuration of the submission.
+func Check(app *v1beta2.SparkApplication, config resolver.Config, kubeClient kubernetes.Interface) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+	namespace := common.GetAppNamespace(app)
+	log.Printf("=== Starting reference checks for app: %s, namespace: %s ===", app.Name, namespace)
+
+	references := GetReferences(app, config)
+	results := make(map[string]referenceResult)
+	var missing, unreadable []Reference
+	for _, reference := range references {
+		key := reference.Kind + "/" + reference.Name
+		result, checked := results[key]
+		if !checked {
+			var err error
+			result, err = getReference(kubeClient, namespace, reference)
+			if err != nil {
+				return err
+			}
+			results[key] = result
+		}
+		switch result {
+		case referenceMissing:
+			missing = append(missing, reference)
+		case referenceUnreadable:
+			unreadable = append(unreadable, reference)
+		}
+	}
+	log.Printf("Checked %d references, %d missing, %d unreadable", len(references), len(missing), len(unreadable))
+	if len(missing) == 0 && len(unreadable) == 0 {
+		log.Printf("=== Successfully completed reference checks ===")
+		return nil
+	}
+
+	mode := getCheckMode(kubeClient, namespace)
+	if len(unreadable) > 0 && mode != CheckModeStrict {
+		log.Printf("WARNING: not allowed to read %d referenced objects in namespace %s, skipping their check: %s", len(unreadable), namespace, joinReferences(unreadable))
+		unreadable = nil
+	}
+	if len(missing) == 0 && len(unreadable) == 0 {
+		log.Printf("=== Successfully completed reference checks ===")
+		return nil
+	}
+
+	missingErr := &MissingReferencesError{Namespace: namespace, Missing: missing, Unreadable: unreadable}
+	if mode == CheckModeAdvisory {
+		log.Printf("WARNING: %v, going on as namespace %s checks references in advisory mode", missingErr, namespace)
+		return nil
+	}
+	return missingErr
+}
+
+// GetReferences lists the image pull secrets, service accounts, Secrets, ConfigMaps and PersistentVolumeClaims the
+// driver and executors refer to, optional ones excluded
+func GetReferences(app *v1beta2.SparkApplication, config resolver.Config) []Reference {
+	var references []Reference
+	add := func(kind string, name string, field string) {
+		if name != "" {
+			references = append(references, Reference{Kind: kind, Name: name, Field: field})
+		}
+	}
+
+	for i, secret := range app.Spec.ImagePullSecrets {
+		add(SecretKind, secret, fmt.Sprintf("spec.imagePullSecrets[%d]", i))
+	}
+	if pullSecrets, ok := app.Spec.SparkConf[common.SparkImagePullSecretKey]; ok {
+		for _, secret := range strings.Split(pullSecrets, ",") {
+			add(SecretKind, strings.TrimSpace(secret), fmt.Sprintf("spec.sparkConf[%s]", common.SparkImagePullSecretKey))
+		}
+	}
+	if app.Spec.SparkConfigMap != nil {
+		add(ConfigMapKind, *app.Spec.SparkConfigMap, "spec.sparkConfigMap")
+	}
+	if app.Spec.HadoopConfigMap != nil {
+		add(ConfigMapKind, *app.Spec.HadoopConfigMap, "spec.hadoopConfigMap")
+	}
+
+	for i, volume := range app.Spec.Volumes {
+		field := fmt.Sprintf("spec.volumes[%d]", i)
+		if claim := volume.PersistentVolumeClaim; claim != nil {
+			add(PersistentVolumeClaimKind, claim.ClaimName, field+".persistentVolumeClaim.claimName")
+		}
+		if secret := volume.Secret; secret != nil && !isOptional(secret.Optional) {
+			add(SecretKind, secret.SecretName, field+".secret.secretName")
+		}
+		if configMap := volume.ConfigMap; configMap != nil && !isOptional(configMap.Optional) {
+			add(ConfigMapKind, configMap.Name, field+".configMap.name")
+		}
+	}
+
+	references = append(references, getPodReferences(app.Spec.Driver.SparkPodSpec, "spec.driver", config.Driver.ServiceAccount)...)
+	references = append(references, getPodReferences(app.Spec.Executor.SparkPodSpec, "spec.executor", config.Executor.ServiceAccount)...)
+	references = append(references, getSparkConfReferences(app.Spec.SparkConf)...)
+	return references
+}
+
+// getSparkConfReferences lists the Secrets mounted or read as env vars through sparkConf, and the Kerberos objects
+func getSparkConfReferences(sparkConf map[string]string) []Reference {
+	var references []Reference
+	keys := make([]string, 0, len(sparkConf))
+	for key := range sparkConf {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := strings.TrimSpace(sparkConf[key])
+		field := fmt.Sprintf("spec.sparkConf[%s]", key)
+		switch {
+		case strings.HasPrefix(key, common.SparkDriverSecretKeyPrefix):
+			references = append(references, Reference{Kind: SecretKind, Name: strings.TrimPrefix(key, common.SparkDriverSecretKeyPrefix), Field: field})
+		case strings.HasPrefix(key, common.SparkExecutorSecretKeyPrefix):
+			references = append(references, Reference{Kind: SecretKind, Name: strings.TrimPrefix(key, common.SparkExecutorSecretKeyPrefix), Field: field})
+		case strings.HasPrefix(key, common.SparkDriverSecretKeyRefKeyPrefix), strings.HasPrefix(key, common.SparkExecutorSecretKeyRefKeyPrefix):
+			// [SecretName]:[Key], optionally followed by :optional
+			parts := strings.Split(value, ":")
+			if len(parts) == 3 && parts[2] == driver.SecretKeyRefOptional {
+				continue
+			}
+			if parts[0] != "" {
+				references = append(references, Reference{Kind: SecretKind, Name: parts[0], Field: field})
+			}
+		case key == common.SparkKerberosKrb5ConfigMapKey && value != "":
+			references = append(references, Reference{Kind: ConfigMapKind, Name: value, Field: field})
+		case key == driver.KerberosTokenSecretName && value != "":
+			references = append(references, Reference{Kind: SecretKind, Name: value, Field: field})
+		}
+	}
+	return references
+}
+
+// getPodReferences lists the references of the driver or executor spec and its resolved service account
+func getPodReferences(podSpec v1beta2.SparkPodSpec, path string, serviceAccount resolver.Setting) []Reference {
+	var references []Reference
+	add := func(kind string, name string, field string) {
+		if name != "" {
+			references = append(references, Reference{Kind: kind, Name: name, Field: path + "." + field})
+		}
+	}
+
+	if serviceAccount.Value != "" {
+		references = append(references, Reference{Kind: ServiceAccountKind, Name: serviceAccount.Value, Field: serviceAccount.Field})
+	}
+	for i, secret := range podSpec.Secrets {
+		add(SecretKind, secret.Name, fmt.Sprintf("secrets[%d].name", i))
+	}
+	for i, configMap := range podSpec.ConfigMaps {
+		add(ConfigMapKind, configMap.Name, fmt.Sprintf("configMaps[%d].name", i))
+	}
+
+	envVars := make([]string, 0, len(podSpec.EnvSecretKeyRefs))
+	for envVar := range podSpec.EnvSecretKeyRefs {
+		envVars = append(envVars, envVar)
+	}
+	sort.Strings(envVars)
+	for _, envVar := range envVars {
+		add(SecretKind, podSpec.EnvSecretKeyRefs[envVar].Name, fmt.Sprintf("envSecretKeyRefs[%s].name", envVar))
+	}
+
+	references = append(references, getEnvFromReferences(podSpec.EnvFrom, path+".envFrom")...)
+	for i, container := range podSpec.Sidecars {
+		references = append(references, getContainerReferences(container, fmt.Sprintf("%s.sidecars[%d]", path, i))...)
+	}
+	for i, container := range podSpec.InitContainers {
+		references = append(references, getContainerReferences(container, fmt.Sprintf("%s.initContainers[%d]", path, i))...)
+	}
+	return references
+}
+
+// getContainerReferences lists the non-optional Secrets and ConfigMaps a sidecar or init container reads env vars from
+func getContainerReferences(container apiv1.Container, path string) []Reference {
+	var references []Reference
+	for i, env := range container.Env {
+		if env.ValueFrom == nil {
+			continue
+		}
+		field := fmt.Sprintf("%s.env[%d].valueFrom", path, i)
+		if secretKeyRef := env.ValueFrom.SecretKeyRef; secretKeyRef != nil && secretKeyRef.Name != "" && !isOptional(secretKeyRef.Optional) {
+			references = append(references, Reference{Kind: SecretKind, Name: secretKeyRef.Name, Field: field + ".secretKeyRef.name"})
+		}
+		if configMapKeyRef := env.ValueFrom.ConfigMapKeyRef; configMapKeyRef != nil && configMapKeyRef.Name != "" && !isOptional(configMapKeyRef.Optional) {
+			references = append(references, Reference{Kind: ConfigMapKind, Name: configMapKeyRef.Name, Field: field + ".configMapKeyRef.name"})
+		}
+	}
+	return append(references, getEnvFromReferences(container.EnvFrom, path+".envFrom")...)
+}
+
+// getEnvFromReferences lists the non-optional envFrom Secrets and ConfigMaps
+func getEnvFromReferences(envFromSources []apiv1.EnvFromSource, path string) []Reference {
+	var references []Reference
+	for i, envFrom := range envFromSources {
+		if secretRef := envFrom.SecretRef; secretRef != nil && secretRef.Name != "" && !isOptional(secretRef.Optional) {
+			references = append(references, Reference{Kind: SecretKind, Name: secretRef.Name, Field: fmt.Sprintf("%s[%d].secretRef.name", path, i)})
+		}
+		if configMapRef := envFrom.ConfigMapRef; configMapRef != nil && configMapRef.Name != "" && !isOptional(configMapRef.Optional) {
+			references = append(references, Reference{Kind: ConfigMapKind, Name: configMapRef.Name, Field: fmt.Sprintf("%s[%d].configMapRef.name", path, i)})
+		}
+	}
+	return references
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// referenceResult is the outcome of looking up a reference
+type referenceResult int
+
+const (
+	referenceFound referenceResult = iota
+	referenceMissing
+	referenceUnreadable
+)
+
+// getReference gets the referenced object, telling apart the references the submitter is not allowed to read
+func getReference(kubeClient kubernetes.Interface, namespace string, reference Reference) (referenceResult, error) {
+	var err error
+	switch reference.Kind {
+	case SecretKind:
+		_, err = kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), reference.Name, metav1.GetOptions{})
+	case ConfigMapKind:
+		_, err = kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), reference.Name, metav1.GetOptions{})
+	case ServiceAccountKind:
+		_, err = kubeClient.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), reference.Name, metav1.GetOptions{})
+	case PersistentVolumeClaimKind:
+		_, err = kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), reference.Name, metav1.GetOptions{})
+	default:
+		return referenceMissing, fmt.Errorf("unsupported reference kind %s", reference.Kind)
+	}
+
+	if apiErrors.IsNotFound(err) {
+		return referenceMissing, nil
+	}
+	if apiErrors.IsForbidden(err) {
+		return referenceUnreadable, nil
+	}
+	if err != nil {
+		return referenceMissing, fmt.Errorf("error while getting %s: %w", reference, err)
+	}
+	return referenceFound, nil
+}
+
+// getCheckMode returns the reference check mode annotated on the namespace, empty when it cannot be read
+func getCheckMode(kubeClient kubernetes.Interface, namespace string) string {
+	appNamespace, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("Could not get namespace %s for its reference check mode, missing references block the submission: %v", namespace, err)
+		return ""
+	}
+	return appNamespace.Annotations[CheckModeAnnotation]
+}
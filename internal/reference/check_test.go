@@ -0,0 +1,243 @@
+package reference
+
+import (
+	"errors"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newNamespace(annotations map[string]string) *apiv1.Namespace {
+	return &apiv1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: annotations}}
+}
+
+func existingObjects() []runtime.Object {
+	return []runtime.Object{
+		&apiv1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "spark", Namespace: "default"}},
+		&apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "default"}},
+		&apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-conf", Namespace: "default"}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	optional := true
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		modify      func(app *v1beta2.SparkApplication)
+		wantMissing []Reference
+	}{
+		{name: "all references exist", objects: existingObjects()},
+		{
+			name: "all missing references reported together",
+			wantMissing: []Reference{
+				{Kind: ServiceAccountKind, Name: "spark", Field: "spec.driver.serviceAccount"},
+				{Kind: SecretKind, Name: "app-secret", Field: "spec.driver.secrets[0].name"},
+				{Kind: ConfigMapKind, Name: "app-conf", Field: "spec.executor.configMaps[0].name"},
+			},
+		},
+		{
+			name:    "image pull secrets, volumes and env references",
+			objects: existingObjects(),
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.ImagePullSecrets = []string{"registry"}
+				app.Spec.SparkConf = map[string]string{"spark.kubernetes.container.image.pullSecrets": "registry, other-registry"}
+				app.Spec.Volumes = []apiv1.Volume{
+					{Name: "data", VolumeSource: apiv1.VolumeSource{PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: "data-pvc"}}},
+					{Name: "extra", VolumeSource: apiv1.VolumeSource{Secret: &apiv1.SecretVolumeSource{SecretName: "extra", Optional: &optional}}},
+				}
+				app.Spec.Executor.EnvSecretKeyRefs = map[string]v1beta2.NameKey{"PASSWORD": {Name: "db", Key: "password"}}
+				app.Spec.Driver.EnvFrom = []apiv1.EnvFromSource{{ConfigMapRef: &apiv1.ConfigMapEnvSource{LocalObjectReference: apiv1.LocalObjectReference{Name: "env"}}}}
+			},
+			wantMissing: []Reference{
+				{Kind: SecretKind, Name: "registry", Field: "spec.imagePullSecrets[0]"},
+				{Kind: SecretKind, Name: "registry", Field: "spec.sparkConf[spark.kubernetes.container.image.pullSecrets]"},
+				{Kind: SecretKind, Name: "other-registry", Field: "spec.sparkConf[spark.kubernetes.container.image.pullSecrets]"},
+				{Kind: PersistentVolumeClaimKind, Name: "data-pvc", Field: "spec.volumes[0].persistentVolumeClaim.claimName"},
+				{Kind: ConfigMapKind, Name: "env", Field: "spec.driver.envFrom[0].configMapRef.name"},
+				{Kind: SecretKind, Name: "db", Field: "spec.executor.envSecretKeyRefs[PASSWORD].name"},
+			},
+		},
+		{
+			name:    "service account from sparkConf",
+			objects: existingObjects(),
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{"spark.kubernetes.authenticate.executor.serviceAccountName": "executor-sa"}
+			},
+			wantMissing: []Reference{{Kind: ServiceAccountKind, Name: "executor-sa", Field: "spec.sparkConf[spark.kubernetes.authenticate.executor.serviceAccountName]"}},
+		},
+		{
+			name:    "sparkConf secrets and secretKeyRefs",
+			objects: existingObjects(),
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{
+					"spark.kubernetes.driver.secretKeyRef.PASSWORD":  "db:password",
+					"spark.kubernetes.executor.secretKeyRef.TOKEN":   "api:token:optional",
+					"spark.kubernetes.driver.secrets.driver-certs":   "/etc/certs",
+					"spark.kubernetes.executor.secrets.executor-key": "/etc/key",
+				}
+			},
+			wantMissing: []Reference{
+				{Kind: SecretKind, Name: "db", Field: "spec.sparkConf[spark.kubernetes.driver.secretKeyRef.PASSWORD]"},
+				{Kind: SecretKind, Name: "driver-certs", Field: "spec.sparkConf[spark.kubernetes.driver.secrets.driver-certs]"},
+				{Kind: SecretKind, Name: "executor-key", Field: "spec.sparkConf[spark.kubernetes.executor.secrets.executor-key]"},
+			},
+		},
+		{
+			name:    "kerberos objects",
+			objects: existingObjects(),
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{
+					common.SparkKerberosKrb5ConfigMapKey:         "krb5",
+					"spark.kubernetes.kerberos.tokenSecret.name": "delegation-tokens",
+				}
+			},
+			wantMissing: []Reference{
+				{Kind: ConfigMapKind, Name: "krb5", Field: "spec.sparkConf[" + common.SparkKerberosKrb5ConfigMapKey + "]"},
+				{Kind: SecretKind, Name: "delegation-tokens", Field: "spec.sparkConf[spark.kubernetes.kerberos.tokenSecret.name]"},
+			},
+		},
+		{
+			name:    "sidecar and init container references",
+			objects: existingObjects(),
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Sidecars = []apiv1.Container{{
+					Name: "proxy",
+					Env: []apiv1.EnvVar{
+						{Name: "TOKEN", ValueFrom: &apiv1.EnvVarSource{SecretKeyRef: &apiv1.SecretKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: "proxy-token"}, Key: "token"}}},
+						{Name: "MODE", ValueFrom: &apiv1.EnvVarSource{ConfigMapKeyRef: &apiv1.ConfigMapKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: "proxy-conf"}, Key: "mode", Optional: &optional}}},
+					},
+				}}
+				app.Spec.Executor.InitContainers = []apiv1.Container{{
+					Name:    "setup",
+					EnvFrom: []apiv1.EnvFromSource{{SecretRef: &apiv1.SecretEnvSource{LocalObjectReference: apiv1.LocalObjectReference{Name: "setup-env"}}}},
+				}}
+			},
+			wantMissing: []Reference{
+				{Kind: SecretKind, Name: "proxy-token", Field: "spec.driver.sidecars[0].env[0].valueFrom.secretKeyRef.name"},
+				{Kind: SecretKind, Name: "setup-env", Field: "spec.executor.initContainers[0].envFrom[0].secretRef.name"},
+			},
+		},
+		{
+			name:    "advisory namespace",
+			objects: []runtime.Object{newNamespace(map[string]string{CheckModeAnnotation: CheckModeAdvisory})},
+		},
+		{
+			name:    "blocking namespace",
+			objects: []runtime.Object{newNamespace(map[string]string{CheckModeAnnotation: "enforce"})},
+			wantMissing: []Reference{
+				{Kind: ServiceAccountKind, Name: "spark", Field: "spec.driver.serviceAccount"},
+				{Kind: SecretKind, Name: "app-secret", Field: "spec.driver.secrets[0].name"},
+				{Kind: ConfigMapKind, Name: "app-conf", Field: "spec.executor.configMaps[0].name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+				Type: v1beta2.SparkApplicationTypeScala,
+				Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+					ServiceAccount: common.StringPointer("spark"),
+					Secrets:        []v1beta2.SecretInfo{{Name: "app-secret", Path: "/etc/secret"}},
+				}},
+				Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+					ConfigMaps: []v1beta2.NamePath{{Name: "app-conf", Path: "/etc/conf"}},
+				}},
+			})
+			if tt.modify != nil {
+				tt.modify(app)
+			}
+			err := Check(app, resolver.Resolve(app), fake.NewSimpleClientset(tt.objects...))
+			if tt.wantMissing == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var missingErr *MissingReferencesError
+			require.True(t, errors.As(err, &missingErr))
+			assert.Equal(t, "default", missingErr.Namespace)
+			assert.Equal(t, tt.wantMissing, missingErr.Missing)
+		})
+	}
+}
+
+func TestMissingReferencesErrorMessage(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+		Type: v1beta2.SparkApplicationTypeScala,
+		Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+			ServiceAccount: common.StringPointer("spark"),
+			Secrets:        []v1beta2.SecretInfo{{Name: "app-secret", Path: "/etc/secret"}},
+		}},
+		Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+			ConfigMaps: []v1beta2.NamePath{{Name: "app-conf", Path: "/etc/conf"}},
+		}},
+	})
+	err := Check(app, resolver.Resolve(app), fake.NewSimpleClientset())
+	require.Error(t, err)
+	assert.Equal(t, "3 referenced objects are missing in namespace default: ServiceAccount spark (spec.driver.serviceAccount), "+
+		"Secret app-secret (spec.driver.secrets[0].name), ConfigMap app-conf (spec.executor.configMaps[0].name)", err.Error())
+}
+
+func TestCheckForbidden(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		wantUnreadable []Reference
+	}{
+		{name: "unreadable references are logged and skipped"},
+		{
+			name:        "strict namespace blocks on unreadable references",
+			annotations: map[string]string{CheckModeAnnotation: CheckModeStrict},
+			wantUnreadable: []Reference{
+				{Kind: ServiceAccountKind, Name: "spark", Field: "spec.driver.serviceAccount"},
+				{Kind: SecretKind, Name: "app-secret", Field: "spec.driver.secrets[0].name"},
+				{Kind: ConfigMapKind, Name: "app-conf", Field: "spec.executor.configMaps[0].name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(newNamespace(tt.annotations))
+			for _, resource := range []string{"secrets", "configmaps", "serviceaccounts"} {
+				kubeClient.PrependReactor("get", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apiErrors.NewForbidden(schema.GroupResource{Resource: action.GetResource().Resource}, "", nil)
+				})
+			}
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+				Type: v1beta2.SparkApplicationTypeScala,
+				Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+					ServiceAccount: common.StringPointer("spark"),
+					Secrets:        []v1beta2.SecretInfo{{Name: "app-secret", Path: "/etc/secret"}},
+				}},
+				Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+					ConfigMaps: []v1beta2.NamePath{{Name: "app-conf", Path: "/etc/conf"}},
+				}},
+			})
+			err := Check(app, resolver.Resolve(app), kubeClient)
+			if tt.wantUnreadable == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var missingErr *MissingReferencesError
+			require.True(t, errors.As(err, &missingErr))
+			assert.Empty(t, missingErr.Missing)
+			assert.Equal(t, tt.wantUnreadable, missingErr.Unreadable)
+			assert.Contains(t, err.Error(), "3 referenced objects cannot be read in namespace default")
+		})
+	}
+}
+
+func TestCheckNilApplication(t *testing.T) {
+	assert.Error(t, Check(nil, resolver.Config{}, fake.NewSimpleClientset()))
+}
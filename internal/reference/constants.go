@@ -0,0 +1,15 @@
+package reference
+
+const (
+	// CheckModeAnnotation on the application namespace makes missing references advisory, they are then logged and
+	// the submission goes on, or strict, where references the submitter cannot read block the submission too. Any
+	// other value blocks the submission on missing references only.
+	CheckModeAnnotation = "sparkoperator.k8s.io/reference-check-mode"
+	CheckModeAdvisory   = "advisory"
+	CheckModeStrict     = "strict"
+
+	SecretKind                = "Secret"
+	ConfigMapKind             = "ConfigMap"
+	ServiceAccountKind        = "ServiceAccount"
+	PersistentVolumeClaimKind = "PersistentVolumeClaim"
+)
@@ -0,0 +1,57 @@
+package resolver
+
+// The sources of a setting, from the highest precedence
+const (
+	// SourceBatchScheduler marks the values the batch scheduler of the application sets over the other sources
+	SourceBatchScheduler Source = "batchScheduler"
+	SourceSpec           Source = "spec"
+	SourceSparkConf      Source = "sparkConf"
+	SourceSparkDefaults  Source = "spark-defaults.conf"
+	SourceDefault        Source = "default"
+)
+
+const (
+	// DefaultCores is the Spark default of spark.driver.cores and spark.executor.cores
+	DefaultCores = "1"
+	// DefaultMemory is the Spark default of spark.driver.memory and spark.executor.memory
+	DefaultMemory = "1g"
+	// DefaultImagePullPolicy is the Spark default of spark.kubernetes.container.image.pullPolicy
+	DefaultImagePullPolicy = "IfNotPresent"
+	// DefaultLocalDirs stands for the emptyDir Spark mounts under a random name when no local directory is set
+	DefaultLocalDirs = "/var/data/spark-<uuid>"
+
+	SparkContainerImageKey           = "spark.kubernetes.container.image"
+	SparkContainerImagePullPolicyKey = "spark.kubernetes.container.image.pullPolicy"
+	SparkMemoryOverheadFactorKey     = "spark.kubernetes.memoryOverheadFactor"
+	SparkNodeSelectorKeyPrefix       = "spark.kubernetes.node.selector."
+	SparkSchedulerNameKey            = "spark.kubernetes.scheduler.name"
+	SparkBlockManagerPortKey         = "spark.blockManager.port"
+	SparkDriverBlockManagerPortKey   = "spark.driver.blockManager.port"
+	SparkLocalDirKey                 = "spark.local.dir"
+	SparkPySparkExecutorMemoryKey    = "spark.executor.pyspark.memory"
+	SparkDriverEnvKeyPrefix          = "spark.kubernetes.driverEnv."
+	SparkExecutorEnvKeyPrefix        = "spark.executorEnv."
+	// LocalDirVolumePrefix names the volumes Spark uses as local directories
+	LocalDirVolumePrefix = "spark-local-dir-"
+
+	// The names of the ports in RoleConfig.Ports, as named on the driver container
+	DriverPortName       = "driver-rpc-port"
+	BlockManagerPortName = "blockmanager"
+	UIPortName           = "spark-ui"
+
+	// The per-role keys, formatted with the role, "driver" or "executor"
+	SparkRoleContainerImageKeyFormat       = "spark.kubernetes.%s.container.image"
+	SparkRoleCoresKeyFormat                = "spark.%s.cores"
+	SparkRoleCoreRequestKeyFormat          = "spark.kubernetes.%s.request.cores"
+	SparkRoleCoreLimitKeyFormat            = "spark.kubernetes.%s.limit.cores"
+	SparkRoleMemoryKeyFormat               = "spark.%s.memory"
+	SparkRoleMemoryOverheadKeyFormat       = "spark.%s.memoryOverhead"
+	SparkRoleMemoryOverheadFactorKeyFormat = "spark.%s.memoryOverheadFactor"
+	SparkRoleServiceAccountKeyFormat       = "spark.kubernetes.authenticate.%s.serviceAccountName"
+	SparkRoleNodeSelectorKeyPrefixFormat   = "spark.kubernetes.%s.node.selector."
+	SparkRoleSchedulerNameKeyFormat        = "spark.kubernetes.%s.scheduler.name"
+	SparkRoleLabelKeyPrefixFormat          = "spark.kubernetes.%s.label."
+	SparkRoleAnnotationKeyPrefixFormat     = "spark.kubernetes.%s.annotation."
+	// SparkRoleLocalDirsEnvKeyFormat is formatted with the environment prefix of the role
+	SparkRoleLocalDirsEnvKeyFormat = "%sSPARK_LOCAL_DIRS"
+)
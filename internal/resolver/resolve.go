@@ -0,0 +1,423 @@
+package resolver
+
+import (
+	"fmt"
+	"nativesubmit/common"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/magiconair/properties"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// SparkDefaultsFile is the spark-defaults.conf of the Spark distribution. Its properties apply to every application,
+// below the sparkConf ones.
+var SparkDefaultsFile = "/opt/spark/conf/spark-defaults.conf"
+
+// Source tells where the effective value of a setting comes from
+type Source string
+
+// Setting is the effective value of a setting, with where it comes from
+type Setting struct {
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+	// Field is the path of the spec field or sparkConf key the value is read from, empty for defaults
+	Field string `json:"field,omitempty"`
+	// Overridden lists the lower precedence inputs that are set too, with a different value
+	Overridden []Setting `json:"overridden,omitempty"`
+}
+
+// IsSet reports whether the setting has a value, from any source
+func (s Setting) IsSet() bool {
+	return s.Source != ""
+}
+
+// Describe names the setting for messages, its field or else its source
+func (s Setting) Describe() string {
+	if s.Field != "" {
+		return s.Field
+	}
+	return string(s.Source)
+}
+
+// RoleConfig is the effective configuration of the driver or of the executors
+type RoleConfig struct {
+	Role           string
+	Image          Setting
+	Cores          Setting
+	CoreRequest    Setting
+	CoreLimit      Setting
+	Memory         Setting
+	MemoryOverhead Setting
+	// MemoryOverheadFactor is the factor applied to the memory when no memory overhead is set
+	MemoryOverheadFactor Setting
+	ServiceAccount       Setting
+	NodeSelector         map[string]Setting
+	// SchedulerName is the scheduler of the role pods, empty for the default scheduler
+	SchedulerName Setting
+	// Labels and Annotations are those of the role pods, keyed by label and annotation name
+	Labels      map[string]Setting
+	Annotations map[string]Setting
+	// Ports are the ports the role listens on, keyed by port name
+	Ports map[string]Setting
+	// LocalDirs is the comma separated list of Spark local directories
+	LocalDirs Setting
+	// PySparkMemory is the memory of the Python workers, only set for executors
+	PySparkMemory Setting
+}
+
+// Config is the effective configuration of a Spark Application, where the spec, sparkConf, spark-defaults.conf and
+// Spark defaults meet
+type Config struct {
+	ImagePullPolicy Setting
+	// MemoryOverheadFactor is the application wide spark.kubernetes.memoryOverheadFactor
+	MemoryOverheadFactor Setting
+	Driver               RoleConfig
+	Executor             RoleConfig
+	// SparkDefaults are the spark-defaults.conf properties the configuration is resolved from
+	SparkDefaults map[string]string
+}
+
+// Role returns the configuration of the driver or the executors, the role being "driver" or "executor"
+func (c Config) Role(role string) RoleConfig {
+	if role == common.SparkDriverRole {
+		return c.Driver
+	}
+	return c.Executor
+}
+
+// Helper func to resolve the effective configuration of the Spark Application, reading spark-defaults.conf once. The
+// spec takes precedence over sparkConf, then spark-defaults.conf and last the Spark defaults. A submission resolves it
+// once and builds the driver pod, the properties file and the batch scheduler resources from it, so that they agree
+// on every value.
+func Resolve(app *v1beta2.SparkApplication) Config {
+	in := inputs{app: app, sparkDefaults: loadSparkDefaults()}
+	return Config{
+		ImagePullPolicy: first(
+			specString(app.Spec.ImagePullPolicy, "spec.imagePullPolicy"),
+			in.conf(SparkContainerImagePullPolicyKey),
+			defaultValue(DefaultImagePullPolicy),
+		),
+		MemoryOverheadFactor: first(in.memoryOverheadFactorCandidates()...),
+		Driver:               in.resolveRole(common.SparkDriverRole),
+		Executor:             in.resolveRole(common.SparkExecutorRole),
+		SparkDefaults:        in.sparkDefaults,
+	}
+}
+
+// loadSparkDefaults reads the properties of SparkDefaultsFile, none when it does not exist or cannot be parsed
+func loadSparkDefaults() map[string]string {
+	sparkDefaults, err := properties.LoadFile(SparkDefaultsFile, properties.UTF8)
+	if err != nil {
+		return nil
+	}
+	return sparkDefaults.Map()
+}
+
+// ParsePort parses a resolved port, naming its field in the error
+func ParsePort(setting Setting) (int32, error) {
+	port, err := strconv.ParseInt(strings.TrimSpace(setting.Value), 10, 32)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q in %s", setting.Value, setting.Describe())
+	}
+	return int32(port), nil
+}
+
+// inputs are the sources a Spark Application setting is resolved from
+type inputs struct {
+	app           *v1beta2.SparkApplication
+	sparkDefaults map[string]string
+}
+
+func (in inputs) resolveRole(role string) RoleConfig {
+	app := in.app
+	specPath := "spec." + role
+	podSpec := app.Spec.Executor.SparkPodSpec
+	coreRequest := app.Spec.Executor.CoreRequest
+	if role == common.SparkDriverRole {
+		podSpec = app.Spec.Driver.SparkPodSpec
+		coreRequest = app.Spec.Driver.CoreRequest
+	}
+
+	var cores *string
+	if podSpec.Cores != nil {
+		cores = stringPtr(fmt.Sprint(*podSpec.Cores))
+	}
+	config := RoleConfig{
+		Role: role,
+		Image: first(
+			specString(podSpec.Image, specPath+".image"),
+			specString(app.Spec.Image, "spec.image"),
+			in.conf(fmt.Sprintf(SparkRoleContainerImageKeyFormat, role)),
+			in.conf(SparkContainerImageKey),
+		),
+		Cores: first(
+			specString(cores, specPath+".cores"),
+			in.conf(fmt.Sprintf(SparkRoleCoresKeyFormat, role)),
+			defaultValue(DefaultCores),
+		),
+		CoreRequest: first(
+			specString(coreRequest, specPath+".coreRequest"),
+			in.conf(fmt.Sprintf(SparkRoleCoreRequestKeyFormat, role)),
+		),
+		CoreLimit: first(
+			specString(podSpec.CoreLimit, specPath+".coreLimit"),
+			in.conf(fmt.Sprintf(SparkRoleCoreLimitKeyFormat, role)),
+		),
+		Memory: first(
+			specString(podSpec.Memory, specPath+".memory"),
+			in.conf(fmt.Sprintf(SparkRoleMemoryKeyFormat, role)),
+			defaultValue(DefaultMemory),
+		),
+		MemoryOverhead: first(
+			specString(podSpec.MemoryOverhead, specPath+".memoryOverhead"),
+			in.conf(fmt.Sprintf(SparkRoleMemoryOverheadKeyFormat, role)),
+			in.conf(common.SparkMemoryOverheadKey),
+		),
+		// Like Spark, the per-role factor takes precedence over the application wide one
+		MemoryOverheadFactor: first(
+			append([][]candidate{in.conf(fmt.Sprintf(SparkRoleMemoryOverheadFactorKeyFormat, role))},
+				in.memoryOverheadFactorCandidates()...)...,
+		),
+		ServiceAccount: first(
+			specString(podSpec.ServiceAccount, specPath+".serviceAccount"),
+			in.conf(fmt.Sprintf(SparkRoleServiceAccountKeyFormat, role)),
+		),
+		NodeSelector: in.resolveNodeSelector(role, podSpec.NodeSelector),
+		SchedulerName: first(
+			specString(podSpec.SchedulerName, specPath+".schedulerName"),
+			in.conf(fmt.Sprintf(SparkRoleSchedulerNameKeyFormat, role)),
+			in.conf(SparkSchedulerNameKey),
+		),
+		// The role labels override the application labels, as Spark applies them over the application ones
+		Labels: merge(
+			specMap(app.Labels, "metadata.labels"),
+			withPrefix(in.sparkDefaults, SourceSparkDefaults, fmt.Sprintf(SparkRoleLabelKeyPrefixFormat, role)),
+			withPrefix(app.Spec.SparkConf, SourceSparkConf, fmt.Sprintf(SparkRoleLabelKeyPrefixFormat, role)),
+			specMap(podSpec.Labels, specPath+".labels"),
+		),
+		Annotations: merge(
+			withPrefix(in.sparkDefaults, SourceSparkDefaults, fmt.Sprintf(SparkRoleAnnotationKeyPrefixFormat, role)),
+			withPrefix(app.Spec.SparkConf, SourceSparkConf, fmt.Sprintf(SparkRoleAnnotationKeyPrefixFormat, role)),
+			specMap(podSpec.Annotations, specPath+".annotations"),
+		),
+		LocalDirs: first(
+			specLocalDirs(podSpec.VolumeMounts, specPath+".volumeMounts"),
+			in.conf(fmt.Sprintf(SparkRoleLocalDirsEnvKeyFormat, sparkRoleEnvPrefix(role))),
+			in.conf(SparkLocalDirKey),
+			defaultValue(DefaultLocalDirs),
+		),
+	}
+
+	// spark.driver.blockManager.port takes precedence over spark.blockManager.port for the driver
+	blockManagerPort := first(in.conf(SparkBlockManagerPortKey), defaultValue(strconv.Itoa(common.DefaultBlockManagerPort)))
+	if role == common.SparkDriverRole {
+		config.Ports = map[string]Setting{
+			DriverPortName:       first(in.conf(common.SparkDriverPort), defaultValue(strconv.Itoa(common.DefaultDriverPort))),
+			BlockManagerPortName: first(in.conf(SparkDriverBlockManagerPortKey), blockManagerPort.candidates()),
+			UIPortName:           first(in.conf(common.SparkUIPortKey), defaultValue(strconv.Itoa(common.DefaultUiPort))),
+		}
+	} else {
+		config.Ports = map[string]Setting{BlockManagerPortName: blockManagerPort}
+		if app.Spec.Type == v1beta2.SparkApplicationTypePython {
+			config.PySparkMemory = first(in.conf(SparkPySparkExecutorMemoryKey))
+		}
+	}
+	return config
+}
+
+// memoryOverheadFactorCandidates lists the inputs of spark.kubernetes.memoryOverheadFactor, the default depending on
+// the application type
+func (in inputs) memoryOverheadFactorCandidates() [][]candidate {
+	return [][]candidate{
+		specString(in.app.Spec.MemoryOverheadFactor, "spec.memoryOverheadFactor"),
+		in.conf(SparkMemoryOverheadFactorKey),
+		defaultValue(common.GetMemoryOverheadFactor(in.app)),
+	}
+}
+
+// resolveNodeSelector merges the node selectors the way Spark does, the role ones overriding the application wide
+// ones. At each level the spec overrides sparkConf, which overrides spark-defaults.conf.
+func (in inputs) resolveNodeSelector(role string, roleNodeSelector map[string]string) map[string]Setting {
+	roleKeyPrefix := fmt.Sprintf(SparkRoleNodeSelectorKeyPrefixFormat, role)
+	layers := [][]candidate{
+		withPrefix(in.sparkDefaults, SourceSparkDefaults, SparkNodeSelectorKeyPrefix),
+		withPrefix(in.app.Spec.SparkConf, SourceSparkConf, SparkNodeSelectorKeyPrefix),
+		specMap(in.app.Spec.NodeSelector, "spec.nodeSelector"),
+		withPrefix(in.sparkDefaults, SourceSparkDefaults, roleKeyPrefix),
+		withPrefix(in.app.Spec.SparkConf, SourceSparkConf, roleKeyPrefix),
+		specMap(roleNodeSelector, fmt.Sprintf("spec.%s.nodeSelector", role)),
+	}
+
+	return merge(layers...)
+}
+
+// merge resolves the entries of maps given as layers of candidates named after their key, from the lowest
+// precedence. Nil when no layer has an entry.
+func merge(layers ...[]candidate) map[string]Setting {
+	// The candidates of each key, from the highest precedence
+	byName := make(map[string][]candidate)
+	for _, layer := range layers {
+		for _, c := range layer {
+			byName[c.name] = append([]candidate{c}, byName[c.name]...)
+		}
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+	merged := make(map[string]Setting, len(byName))
+	for name, candidates := range byName {
+		merged[name] = first(candidates)
+	}
+	return merged
+}
+
+// sparkRoleEnvPrefix returns the prefix of the sparkConf keys setting environment variables of the role
+func sparkRoleEnvPrefix(role string) string {
+	if role == common.SparkDriverRole {
+		return SparkDriverEnvKeyPrefix
+	}
+	return SparkExecutorEnvKeyPrefix
+}
+
+// Values returns the keys and values of a resolved node selector, labels or annotations
+func Values(settings map[string]Setting) map[string]string {
+	if len(settings) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(settings))
+	for key, setting := range settings {
+		values[key] = setting.Value
+	}
+	return values
+}
+
+// Override returns the setting with the value set from the given source, the previous value recorded as overridden.
+// It is used for the values a batch scheduler sets over the application ones.
+func (s Setting) Override(value string, source Source, field string) Setting {
+	return first([]candidate{{value: &value, source: source, field: field}}, s.candidates())
+}
+
+// candidate is an input of a setting, unset when value is nil
+type candidate struct {
+	value  *string
+	source Source
+	field  string
+	// name is the key of the candidate in a map setting, such as a node selector label
+	name string
+}
+
+// candidates turns a resolved setting back into the inputs it was resolved from
+func (s Setting) candidates() []candidate {
+	if !s.IsSet() {
+		return nil
+	}
+	candidates := []candidate{{value: stringPtr(s.Value), source: s.Source, field: s.Field}}
+	for _, overridden := range s.Overridden {
+		candidates = append(candidates, overridden.candidates()...)
+	}
+	return candidates
+}
+
+// first returns the first set candidate as the setting, the other set candidates with a different value being
+// recorded as overridden. The candidates are listed from the highest precedence.
+func first(groups ...[]candidate) Setting {
+	var setting Setting
+	for _, candidates := range groups {
+		for _, c := range candidates {
+			if c.value == nil {
+				continue
+			}
+			if !setting.IsSet() {
+				setting = Setting{Value: *c.value, Source: c.source, Field: c.field}
+				continue
+			}
+			if *c.value != setting.Value && c.source != SourceDefault {
+				setting.Overridden = append(setting.Overridden, Setting{Value: *c.value, Source: c.source, Field: c.field})
+			}
+		}
+	}
+	return setting
+}
+
+func specString(value *string, field string) []candidate {
+	if value == nil {
+		return nil
+	}
+	return []candidate{{value: value, source: SourceSpec, field: field}}
+}
+
+// conf lists the sparkConf value of the key, then the spark-defaults.conf one
+func (in inputs) conf(key string) []candidate {
+	var candidates []candidate
+	if value, ok := in.app.Spec.SparkConf[key]; ok {
+		candidates = append(candidates, candidate{value: &value, source: SourceSparkConf, field: sparkConfField(SourceSparkConf, key)})
+	}
+	if value, ok := in.sparkDefaults[key]; ok {
+		candidates = append(candidates, candidate{value: &value, source: SourceSparkDefaults, field: sparkConfField(SourceSparkDefaults, key)})
+	}
+	return candidates
+}
+
+func defaultValue(value string) []candidate {
+	return []candidate{{value: &value, source: SourceDefault}}
+}
+
+func specMap(values map[string]string, field string) []candidate {
+	var candidates []candidate
+	for _, name := range sortedKeys(values) {
+		value := values[name]
+		candidates = append(candidates, candidate{value: &value, source: SourceSpec, field: fmt.Sprintf("%s[%s]", field, name), name: name})
+	}
+	return candidates
+}
+
+// specLocalDirs lists the mount paths of the spark-local-dir- volume mounts, Spark using them as local directories
+func specLocalDirs(volumeMounts []apiv1.VolumeMount, field string) []candidate {
+	var mountPaths []string
+	for _, volumeMount := range volumeMounts {
+		if strings.HasPrefix(volumeMount.Name, LocalDirVolumePrefix) {
+			mountPaths = append(mountPaths, volumeMount.MountPath)
+		}
+	}
+	if len(mountPaths) == 0 {
+		return nil
+	}
+	return specString(stringPtr(strings.Join(mountPaths, ",")), field)
+}
+
+// withPrefix lists the properties whose key has the prefix, named after the rest of the key
+func withPrefix(values map[string]string, source Source, prefix string) []candidate {
+	var candidates []candidate
+	for _, key := range sortedKeys(values) {
+		if !strings.HasPrefix(key, prefix) || len(key) == len(prefix) {
+			continue
+		}
+		value := values[key]
+		candidates = append(candidates, candidate{value: &value, source: source, field: sparkConfField(source, key), name: strings.TrimPrefix(key, prefix)})
+	}
+	return candidates
+}
+
+// sparkConfField names a property of sparkConf or spark-defaults.conf
+func sparkConfField(source Source, key string) string {
+	if source == SourceSparkDefaults {
+		return fmt.Sprintf("%s[%s]", SourceSparkDefaults, key)
+	}
+	return fmt.Sprintf("spec.sparkConf[%s]", key)
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,355 @@
+package resolver
+
+import (
+	"fmt"
+	"nativesubmit/common"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestResolveRoles(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  string
+		spec  v1beta2.SparkApplicationSpec
+		check func(t *testing.T, config RoleConfig)
+	}{
+		{
+			name: "defaults",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{Value: "1", Source: SourceDefault}, config.Cores)
+				assert.Equal(t, Setting{Value: "1g", Source: SourceDefault}, config.Memory)
+				assert.Equal(t, Setting{Value: common.JavaScalaMemoryOverheadFactor, Source: SourceDefault}, config.MemoryOverheadFactor)
+				assert.False(t, config.Image.IsSet())
+				assert.False(t, config.MemoryOverhead.IsSet())
+				assert.False(t, config.ServiceAccount.IsSet())
+				assert.Nil(t, config.NodeSelector)
+			},
+		},
+		{
+			name: "spec over sparkConf",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{"spark.executor.cores": "2", "spark.executor.memory": "2g", "spark.executor.memoryOverhead": "512m"},
+				Executor: v1beta2.ExecutorSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(4), Memory: stringPtr("4g"), ServiceAccount: stringPtr("spark")},
+				},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{
+					Value:      "4",
+					Source:     SourceSpec,
+					Field:      "spec.executor.cores",
+					Overridden: []Setting{{Value: "2", Source: SourceSparkConf, Field: "spec.sparkConf[spark.executor.cores]"}},
+				}, config.Cores)
+				assert.Equal(t, "4g", config.Memory.Value)
+				assert.Len(t, config.Memory.Overridden, 1)
+				assert.Equal(t, Setting{Value: "512m", Source: SourceSparkConf, Field: "spec.sparkConf[spark.executor.memoryOverhead]"}, config.MemoryOverhead)
+				assert.Equal(t, Setting{Value: "spark", Source: SourceSpec, Field: "spec.executor.serviceAccount"}, config.ServiceAccount)
+			},
+		},
+		{
+			name: "equal values are not conflicts",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{"spark.driver.cores": "2"},
+				Driver:    v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(2)}},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{Value: "2", Source: SourceSpec, Field: "spec.driver.cores"}, config.Cores)
+			},
+		},
+		{
+			name: "image",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Image:     stringPtr("spark:app"),
+				SparkConf: map[string]string{"spark.kubernetes.driver.container.image": "spark:conf-driver", "spark.kubernetes.container.image": "spark:conf"},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, "spark:app", config.Image.Value)
+				assert.Equal(t, "spec.image", config.Image.Field)
+				assert.Len(t, config.Image.Overridden, 2)
+			},
+		},
+		{
+			name: "role image",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Image:    stringPtr("spark:app"),
+				Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Image: stringPtr("spark:executor")}},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{
+					Value:      "spark:executor",
+					Source:     SourceSpec,
+					Field:      "spec.executor.image",
+					Overridden: []Setting{{Value: "spark:app", Source: SourceSpec, Field: "spec.image"}},
+				}, config.Image)
+			},
+		},
+		{
+			name: "core request and limit",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{"spark.kubernetes.driver.request.cores": "500m", "spark.kubernetes.driver.limit.cores": "2"},
+				Driver:    v1beta2.DriverSpec{CoreRequest: stringPtr("250m")},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, "250m", config.CoreRequest.Value)
+				assert.Equal(t, Setting{Value: "2", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.driver.limit.cores]"}, config.CoreLimit)
+			},
+		},
+		{
+			name: "shared memory overhead",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{common.SparkMemoryOverheadKey: "1g", "spark.executor.memoryOverhead": "2g"}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{Value: "1g", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.memoryOverhead]"}, config.MemoryOverhead)
+			},
+		},
+		{
+			name: "role memory overhead factor over the spec one",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				Type:                 v1beta2.SparkApplicationTypePython,
+				MemoryOverheadFactor: stringPtr("0.2"),
+				SparkConf:            map[string]string{"spark.executor.memoryOverheadFactor": "0.3"},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, "0.3", config.MemoryOverheadFactor.Value)
+				assert.Equal(t, []Setting{{Value: "0.2", Source: SourceSpec, Field: "spec.memoryOverheadFactor"}}, config.MemoryOverheadFactor.Overridden)
+			},
+		},
+		{
+			name: "node selector",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{
+				NodeSelector: map[string]string{"disktype": "ssd", "topology.kubernetes.io/zone": "zone-a"},
+				SparkConf: map[string]string{
+					"spark.kubernetes.node.selector.disktype":                           "hdd",
+					"spark.kubernetes.node.selector.pool":                               "spark",
+					"spark.kubernetes.driver.node.selector.topology.kubernetes.io/zone": "zone-b",
+					"spark.kubernetes.executor.node.selector.pool":                      "executors",
+				},
+				Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "m5.xlarge"}}},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, map[string]string{
+					"disktype":                         "ssd",
+					"pool":                             "spark",
+					"topology.kubernetes.io/zone":      "zone-b",
+					"node.kubernetes.io/instance-type": "m5.xlarge",
+				}, Values(config.NodeSelector))
+				assert.Equal(t, Setting{
+					Value:      "ssd",
+					Source:     SourceSpec,
+					Field:      "spec.nodeSelector[disktype]",
+					Overridden: []Setting{{Value: "hdd", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.node.selector.disktype]"}},
+				}, config.NodeSelector["disktype"])
+				assert.Equal(t, "spec.sparkConf[spark.kubernetes.driver.node.selector.topology.kubernetes.io/zone]", config.NodeSelector["topology.kubernetes.io/zone"].Field)
+				assert.Len(t, config.NodeSelector["topology.kubernetes.io/zone"].Overridden, 1)
+			},
+		},
+		{
+			name: "driver ports",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{"spark.blockManager.port": "7100", "spark.ui.port": "4050"}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{Value: "7078", Source: SourceDefault}, config.Ports[DriverPortName])
+				assert.Equal(t, Setting{Value: "7100", Source: SourceSparkConf, Field: "spec.sparkConf[spark.blockManager.port]"}, config.Ports[BlockManagerPortName])
+				assert.Equal(t, "4050", config.Ports[UIPortName].Value)
+			},
+		},
+		{
+			name: "driver block manager port over the shared one",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{"spark.blockManager.port": "7100", "spark.driver.blockManager.port": "7200"}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{
+					Value:      "7200",
+					Source:     SourceSparkConf,
+					Field:      "spec.sparkConf[spark.driver.blockManager.port]",
+					Overridden: []Setting{{Value: "7100", Source: SourceSparkConf, Field: "spec.sparkConf[spark.blockManager.port]"}},
+				}, config.Ports[BlockManagerPortName])
+			},
+		},
+		{
+			name: "executor ports",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{"spark.driver.blockManager.port": "7200"}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, map[string]Setting{BlockManagerPortName: {Value: "7079", Source: SourceDefault}}, config.Ports)
+			},
+		},
+		{
+			name: "local dirs from volume mounts",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{"spark.local.dir": "/tmp/spark"},
+				Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{VolumeMounts: []apiv1.VolumeMount{
+					{Name: "spark-local-dir-1", MountPath: "/data1"},
+					{Name: "config", MountPath: "/etc/config"},
+					{Name: "spark-local-dir-2", MountPath: "/data2"},
+				}}},
+			},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, "/data1,/data2", config.LocalDirs.Value)
+				assert.Equal(t, "spec.executor.volumeMounts", config.LocalDirs.Field)
+				assert.Len(t, config.LocalDirs.Overridden, 1)
+			},
+		},
+		{
+			name: "local dirs from the environment",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{
+				"spark.local.dir": "/tmp/spark",
+				"spark.kubernetes.driverEnv.SPARK_LOCAL_DIRS": "/tmp/driver",
+				"spark.executorEnv.SPARK_LOCAL_DIRS":          "/tmp/executor",
+			}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, "/tmp/driver", config.LocalDirs.Value)
+				assert.Equal(t, []Setting{{Value: "/tmp/spark", Source: SourceSparkConf, Field: "spec.sparkConf[spark.local.dir]"}}, config.LocalDirs.Overridden)
+			},
+		},
+		{
+			name: "default local dirs",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, Setting{Value: DefaultLocalDirs, Source: SourceDefault}, config.LocalDirs)
+			},
+		},
+		{
+			name: "pyspark memory of python executors",
+			role: common.SparkExecutorRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypePython, SparkConf: map[string]string{"spark.executor.pyspark.memory": "512m"}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.Equal(t, "512m", config.PySparkMemory.Value)
+			},
+		},
+		{
+			name: "no pyspark memory for the driver",
+			role: common.SparkDriverRole,
+			spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypePython, SparkConf: map[string]string{"spark.executor.pyspark.memory": "512m"}},
+			check: func(t *testing.T, config RoleConfig) {
+				assert.False(t, config.PySparkMemory.IsSet())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, Resolve(&v1beta2.SparkApplication{Spec: tt.spec}).Role(tt.role))
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{
+		Type:      v1beta2.SparkApplicationTypePython,
+		SparkConf: map[string]string{"spark.kubernetes.container.image.pullPolicy": "Always", "spark.kubernetes.memoryOverheadFactor": "0.2"},
+	}}
+	config := Resolve(app)
+	assert.Equal(t, Setting{Value: "Always", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.container.image.pullPolicy]"}, config.ImagePullPolicy)
+	assert.Equal(t, Setting{Value: "0.2", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.memoryOverheadFactor]"}, config.MemoryOverheadFactor)
+	assert.Equal(t, common.SparkDriverRole, config.Driver.Role)
+	assert.Equal(t, common.SparkExecutorRole, config.Executor.Role)
+
+	app.Spec.SparkConf = nil
+	config = Resolve(app)
+	assert.Equal(t, Setting{Value: DefaultImagePullPolicy, Source: SourceDefault}, config.ImagePullPolicy)
+	assert.Equal(t, Setting{Value: common.OtherLanguageMemoryOverheadFactor, Source: SourceDefault}, config.MemoryOverheadFactor)
+}
+
+func TestResolvePodMetadata(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "data", "tier": "batch"}},
+		Spec: v1beta2.SparkApplicationSpec{
+			SparkConf: map[string]string{
+				"spark.kubernetes.scheduler.name":                      "default-scheduler",
+				"spark.kubernetes.executor.scheduler.name":             "bin-packing",
+				"spark.kubernetes.driver.label.tier":                   "interactive",
+				"spark.kubernetes.driver.label.app.kubernetes.io/name": "etl",
+				"spark.kubernetes.executor.annotation.owner":           "conf",
+			},
+			Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+				Labels:        map[string]string{"team": "platform"},
+				SchedulerName: stringPtr("gang"),
+			}},
+			Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+				Annotations: map[string]string{"owner": "spec"},
+			}},
+		},
+	}
+	config := Resolve(app)
+	assert.Equal(t, "gang", config.Driver.SchedulerName.Value)
+	assert.Equal(t, "spec.sparkConf[spark.kubernetes.executor.scheduler.name]", config.Executor.SchedulerName.Field)
+	assert.Equal(t, map[string]string{"team": "platform", "tier": "interactive", "app.kubernetes.io/name": "etl"}, Values(config.Driver.Labels))
+	assert.Equal(t, map[string]string{"team": "data", "tier": "batch"}, Values(config.Executor.Labels))
+	assert.Equal(t, Setting{
+		Value:      "spec",
+		Source:     SourceSpec,
+		Field:      "spec.executor.annotations[owner]",
+		Overridden: []Setting{{Value: "conf", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.executor.annotation.owner]"}},
+	}, config.Executor.Annotations["owner"])
+	assert.Nil(t, config.Driver.Annotations)
+
+	overridden := config.Driver.SchedulerName.Override("volcano", SourceBatchScheduler, "spec.batchScheduler")
+	assert.Equal(t, Setting{
+		Value:  "volcano",
+		Source: SourceBatchScheduler,
+		Field:  "spec.batchScheduler",
+		Overridden: []Setting{
+			{Value: "gang", Source: SourceSpec, Field: "spec.driver.schedulerName"},
+			{Value: "default-scheduler", Source: SourceSparkConf, Field: "spec.sparkConf[spark.kubernetes.scheduler.name]"},
+		},
+	}, overridden)
+	assert.Equal(t, Setting{Value: "volcano", Source: SourceBatchScheduler, Field: "spec.batchScheduler"}, Setting{}.Override("volcano", SourceBatchScheduler, "spec.batchScheduler"))
+}
+
+func TestResolveSparkDefaults(t *testing.T) {
+	defer func(file string) { SparkDefaultsFile = file }(SparkDefaultsFile)
+	SparkDefaultsFile = "testdata/spark-defaults.conf"
+
+	app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{
+		NodeSelector: map[string]string{"disktype": "ssd"},
+		SparkConf:    map[string]string{"spark.driver.memory": "4g"},
+	}}
+	resolved := Resolve(app)
+	assert.Equal(t, "2g", resolved.SparkDefaults["spark.driver.memory"])
+	config := resolved.Driver
+	assert.Equal(t, Setting{
+		Value:      "4g",
+		Source:     SourceSparkConf,
+		Field:      "spec.sparkConf[spark.driver.memory]",
+		Overridden: []Setting{{Value: "2g", Source: SourceSparkDefaults, Field: "spark-defaults.conf[spark.driver.memory]"}},
+	}, config.Memory)
+	assert.Equal(t, Setting{Value: "spark:defaults", Source: SourceSparkDefaults, Field: "spark-defaults.conf[spark.kubernetes.container.image]"}, config.Image)
+	assert.Equal(t, map[string]string{"disktype": "ssd", "pool": "spark"}, Values(config.NodeSelector))
+	assert.Equal(t, SourceSparkDefaults, config.NodeSelector["disktype"].Overridden[0].Source)
+	assert.Equal(t, "/tmp/defaults", config.LocalDirs.Value)
+
+	SparkDefaultsFile = "testdata/missing.conf"
+	resolved = Resolve(app)
+	assert.Empty(t, resolved.SparkDefaults)
+	assert.Equal(t, Setting{Value: "4g", Source: SourceSparkConf, Field: "spec.sparkConf[spark.driver.memory]"}, resolved.Driver.Memory)
+}
+
+func TestParsePort(t *testing.T) {
+	port, err := ParsePort(Setting{Value: "7078", Source: SourceDefault})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7078), port)
+
+	for _, value := range []string{"rpc", "0", "70000"} {
+		_, err = ParsePort(Setting{Value: value, Source: SourceSparkConf, Field: "spec.sparkConf[spark.driver.port]"})
+		assert.EqualError(t, err, fmt.Sprintf("invalid port %q in spec.sparkConf[spark.driver.port]", value))
+	}
+}
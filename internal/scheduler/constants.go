@@ -0,0 +1,57 @@
+package scheduler
+
+const (
+	SparkDriverCores            = "spark.driver.cores"
+	SparkDriverMemory           = "spark.driver.memory"
+	SparkExecutorCores          = "spark.executor.cores"
+	SparkExecutorMemory         = "spark.executor.memory"
+	SparkExecutorInstances      = "spark.executor.instances"
+	SparkExecutorCoreRequestKey = "spark.kubernetes.executor.request.cores"
+	// ExecutorDefaultInstances is the Spark default of spark.executor.instances.
+	ExecutorDefaultInstances = 2
+	// BatchSchedulerField is the field of the settings a batch scheduler sets in the resolved configuration.
+	BatchSchedulerField = "spec.batchScheduler"
+
+	// VolcanoSchedulerName is the batchScheduler value and the pod schedulerName of the Volcano batch scheduler.
+	VolcanoSchedulerName = "volcano"
+	// VolcanoGroupNameAnnotation ties the driver and executor pods to their PodGroup.
+	VolcanoGroupNameAnnotation = "scheduling.k8s.io/group-name"
+	VolcanoPodGroupGroup       = "scheduling.volcano.sh"
+	VolcanoPodGroupVersion     = "v1beta1"
+	VolcanoPodGroupResource    = "podgroups"
+	VolcanoPodGroupKind        = "PodGroup"
+	// VolcanoPodGroupMinMember only counts the driver, executors are created by the driver once it is scheduled.
+	VolcanoPodGroupMinMember = 1
+
+	// YuniKornSchedulerName is the batchScheduler value and the pod schedulerName of the Apache YuniKorn scheduler.
+	YuniKornSchedulerName        = "yunikorn"
+	YuniKornAnnotationPrefix     = "yunikorn.apache.org/"
+	YuniKornAppIDAnnotation      = YuniKornAnnotationPrefix + "app-id"
+	YuniKornQueueAnnotation      = YuniKornAnnotationPrefix + "queue"
+	YuniKornTaskGroupsAnnotation = YuniKornAnnotationPrefix + "task-groups"
+	// YuniKornTaskGroupNameAnnotation tells which of the driver's task groups a pod belongs to.
+	YuniKornTaskGroupNameAnnotation = YuniKornAnnotationPrefix + "task-group-name"
+	YuniKornDriverTaskGroupName     = "spark-driver"
+	YuniKornExecutorTaskGroupName   = "spark-executor"
+
+	// KueueBatchSchedulerName is the batchScheduler value queueing the application in Kueue, pods keep the default scheduler.
+	KueueBatchSchedulerName = "kueue"
+	// KueueQueueNameLabel names the LocalQueue of a pod, it is read from the SparkApplication or its namespace labels.
+	KueueQueueNameLabel = "kueue.x-k8s.io/queue-name"
+	// KueueAdmissionSchedulingGate holds the driver pod until Kueue admits it.
+	KueueAdmissionSchedulingGate = "kueue.x-k8s.io/admission"
+	// KueueManagedLabel is set by the Kueue pod webhook on the pods it manages.
+	KueueManagedLabel = "kueue.x-k8s.io/managed"
+)
+
+// Admission is the Kueue admission state of the driver pod when the submission finished.
+type Admission string
+
+const (
+	// AdmissionNone is reported when no queue is involved, without Kueue or when Kueue does not manage the driver pod.
+	AdmissionNone Admission = ""
+	// AdmissionQueued is reported while the driver pod keeps its Kueue admission gate.
+	AdmissionQueued Admission = "queued"
+	// AdmissionAdmitted is reported once Kueue removed the admission gate of the driver pod.
+	AdmissionAdmitted Admission = "admitted"
+)
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"nativesubmit/internal/resolver"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Helper func to prepare the batch scheduler set in the Spark Application spec, it must run before the driver labels,
+// ConfigMap and driver pod are created as it sets the driver and executors schedulerName, labels and annotations. They
+// are set in the resolved configuration the scheduler resources are sized from, which the builders then read them from.
+func Create(app *v1beta2.SparkApplication, config *resolver.Config, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, createdApplicationId string) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+	if GetName(app) == "" {
+		return nil
+	}
+
+	switch *app.Spec.BatchScheduler {
+	case VolcanoSchedulerName:
+		return CreateVolcanoPodGroup(app, config, dynamicClient, createdApplicationId)
+	case YuniKornSchedulerName:
+		return ConfigureYuniKorn(app, config, createdApplicationId)
+	case KueueBatchSchedulerName:
+		return ConfigureKueue(app, config, kubeClient)
+	default:
+		log.Printf("Batch scheduler %s is not supported, skipping", GetName(app))
+		return nil
+	}
+}
+
+// GetName returns the batch scheduler of the Spark Application, empty when it has none
+func GetName(app *v1beta2.SparkApplication) string {
+	if app == nil || app.Spec.BatchScheduler == nil {
+		return ""
+	}
+	return *app.Spec.BatchScheduler
+}
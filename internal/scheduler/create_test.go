@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name              string
+		batchScheduler    *string
+		wantSchedulerName string
+		queueName         string
+		wantActions       int
+	}{
+		{name: "no batch scheduler"},
+		{name: "unsupported batch scheduler", batchScheduler: stringPtr("kube-batch")},
+		{name: "volcano", batchScheduler: stringPtr(VolcanoSchedulerName), wantSchedulerName: VolcanoSchedulerName, wantActions: 2},
+		{name: "yunikorn", batchScheduler: stringPtr(YuniKornSchedulerName), wantSchedulerName: YuniKornSchedulerName},
+		{name: "kueue keeps the default scheduler", batchScheduler: stringPtr(KueueBatchSchedulerName), queueName: "team-queue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeDynamicClient()
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{BatchScheduler: tt.batchScheduler})
+			if tt.queueName != "" {
+				app.Labels = map[string]string{KueueQueueNameLabel: tt.queueName}
+			}
+			config := resolver.Resolve(app)
+			require.NoError(t, Create(app, &config, fake.NewSimpleClientset(), client, "spark-app-id"))
+			assert.Equal(t, tt.wantSchedulerName, config.Driver.SchedulerName.Value)
+			assert.Len(t, client.Actions(), tt.wantActions)
+			assert.Equal(t, tt.queueName, config.Driver.Labels[KueueQueueNameLabel].Value)
+		})
+	}
+
+	assert.Error(t, Create(nil, &resolver.Config{}, fake.NewSimpleClientset(), newFakeDynamicClient(), "spark-app-id"))
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// IsKueue reports whether the Spark Application is queued in Kueue
+func IsKueue(app *v1beta2.SparkApplication) bool {
+	return app.Spec.BatchScheduler != nil && *app.Spec.BatchScheduler == KueueBatchSchedulerName
+}
+
+// GetKueueQueueName returns the LocalQueue of the Spark Application: its queue-name label, then
+// batchSchedulerOptions.queue, then the queue-name label of its namespace
+func GetKueueQueueName(app *v1beta2.SparkApplication, kubeClient kubernetes.Interface) (string, error) {
+	if queueName, ok := app.Labels[KueueQueueNameLabel]; ok && queueName != "" {
+		return queueName, nil
+	}
+	if options := app.Spec.BatchSchedulerOptions; options != nil && options.Queue != nil && *options.Queue != "" {
+		return *options.Queue, nil
+	}
+
+	namespace := common.GetAppNamespace(app)
+	appNamespace, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error while getting namespace %s for its default kueue queue: %w", namespace, err)
+	}
+	if queueName, ok := appNamespace.Labels[KueueQueueNameLabel]; ok && queueName != "" {
+		return queueName, nil
+	}
+	return "", fmt.Errorf("no kueue queue for app %s: set the %s label on the application or its namespace, or batchSchedulerOptions.queue", app.Name, KueueQueueNameLabel)
+}
+
+// Helper func to label the driver and executors with the Kueue LocalQueue when batchScheduler is kueue. The driver
+// pod is then created with the Kueue admission gate, so it must run before the driver labels are built. The
+// labels are recorded in the resolved configuration.
+func ConfigureKueue(app *v1beta2.SparkApplication, config *resolver.Config, kubeClient kubernetes.Interface) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+	if !IsKueue(app) {
+		return nil
+	}
+	log.Printf("=== Starting Kueue configuration for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	queueName, err := GetKueueQueueName(app, kubeClient)
+	if err != nil {
+		return err
+	}
+	log.Printf("Kueue queue: %s", queueName)
+
+	// Executors carry the queue label through spark.kubernetes.executor.label.* and are admitted in the same queue,
+	// each as its own workload. They cannot join a pod group with the driver: Kueue only admits a group once all its
+	// pods exist, while the executors are only created by the admitted driver.
+	config.Driver.Labels = withEntry(config.Driver.Labels, KueueQueueNameLabel, queueName)
+	config.Executor.Labels = withEntry(config.Executor.Labels, KueueQueueNameLabel, queueName)
+
+	log.Printf("=== Successfully completed Kueue configuration ===")
+	return nil
+}
+
+// GetAdmission reports the Kueue admission state of the driver pod when it is called. Kueue admits pods
+// asynchronously, so a driver pod checked right after its creation is usually still queued. When the Kueue pod
+// integration is off for the namespace, nothing would ever remove the admission gate: it is removed here so the driver
+// still runs, and no queue is reported.
+func GetAdmission(app *v1beta2.SparkApplication, kubeClient kubernetes.Interface) (Admission, error) {
+	if !IsKueue(app) {
+		return AdmissionNone, nil
+	}
+	pods := kubeClient.CoreV1().Pods(common.GetAppNamespace(app))
+	driverPod, err := pods.Get(context.TODO(), common.GetDriverPodName(app), metav1.GetOptions{})
+	if err != nil {
+		return AdmissionNone, fmt.Errorf("error while getting driver pod for its kueue admission: %w", err)
+	}
+
+	var gates []apiv1.PodSchedulingGate
+	queued := false
+	for _, gate := range driverPod.Spec.SchedulingGates {
+		if gate.Name == KueueAdmissionSchedulingGate {
+			queued = true
+			continue
+		}
+		gates = append(gates, gate)
+	}
+
+	if driverPod.Labels[KueueManagedLabel] != "true" {
+		log.Printf("WARNING: driver pod %s is not managed by kueue, enable the kueue pod integration for namespace %s", driverPod.Name, driverPod.Namespace)
+		if queued {
+			driverPod.Spec.SchedulingGates = gates
+			if _, err := pods.Update(context.TODO(), driverPod, metav1.UpdateOptions{}); err != nil {
+				return AdmissionNone, fmt.Errorf("error while removing the kueue admission gate of driver pod %s: %w", driverPod.Name, err)
+			}
+		}
+		return AdmissionNone, nil
+	}
+	if queued {
+		return AdmissionQueued, nil
+	}
+	return AdmissionAdmitted, nil
+}
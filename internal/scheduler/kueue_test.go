@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetKueueQueueName(t *testing.T) {
+	tests := []struct {
+		name            string
+		appLabels       map[string]string
+		options         *v1beta2.BatchSchedulerConfiguration
+		namespaceLabels map[string]string
+		want            string
+		wantErr         bool
+	}{
+		{
+			name:            "application label",
+			appLabels:       map[string]string{KueueQueueNameLabel: "app-queue"},
+			options:         &v1beta2.BatchSchedulerConfiguration{Queue: stringPtr("options-queue")},
+			namespaceLabels: map[string]string{KueueQueueNameLabel: "namespace-queue"},
+			want:            "app-queue",
+		},
+		{
+			name:            "batch scheduler options",
+			options:         &v1beta2.BatchSchedulerConfiguration{Queue: stringPtr("options-queue")},
+			namespaceLabels: map[string]string{KueueQueueNameLabel: "namespace-queue"},
+			want:            "options-queue",
+		},
+		{
+			name:            "namespace default",
+			namespaceLabels: map[string]string{KueueQueueNameLabel: "namespace-queue"},
+			want:            "namespace-queue",
+		},
+		{name: "no queue", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(&apiv1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: tt.namespaceLabels},
+			})
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{BatchScheduler: stringPtr(KueueBatchSchedulerName), BatchSchedulerOptions: tt.options})
+			app.Labels = tt.appLabels
+			got, err := GetKueueQueueName(app, kubeClient)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfigureKueue(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{BatchScheduler: stringPtr(KueueBatchSchedulerName), BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{Queue: stringPtr("team-queue")}})
+	app.Spec.Executor.Labels = map[string]string{"team": "data"}
+	config := resolver.Resolve(app)
+	require.NoError(t, ConfigureKueue(app, &config, fake.NewSimpleClientset()))
+
+	assert.Equal(t, map[string]string{KueueQueueNameLabel: "team-queue"}, resolver.Values(config.Driver.Labels))
+	assert.Equal(t, map[string]string{"team": "data", KueueQueueNameLabel: "team-queue"}, resolver.Values(config.Executor.Labels))
+	assert.False(t, config.Driver.SchedulerName.IsSet(), "pods keep the default scheduler")
+
+	noQueue := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{BatchScheduler: stringPtr(KueueBatchSchedulerName)})
+	noQueueConfig := resolver.Resolve(noQueue)
+	assert.Error(t, ConfigureKueue(noQueue, &noQueueConfig, fake.NewSimpleClientset()), "the namespace cannot be read")
+}
+
+func TestGetAdmission(t *testing.T) {
+	newDriverPod := func(managed bool, gates ...string) *apiv1.Pod {
+		pod := &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-app-driver", Namespace: "default"}}
+		if managed {
+			pod.Labels = map[string]string{KueueManagedLabel: "true"}
+		}
+		for _, gate := range gates {
+			pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, apiv1.PodSchedulingGate{Name: gate})
+		}
+		return pod
+	}
+	kueueApp := func() *v1beta2.SparkApplication {
+		return testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{BatchScheduler: stringPtr(KueueBatchSchedulerName)})
+	}
+
+	t.Run("not kueue", func(t *testing.T) {
+		admission, err := GetAdmission(testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{}), fake.NewSimpleClientset())
+		require.NoError(t, err)
+		assert.Equal(t, AdmissionNone, admission)
+	})
+
+	t.Run("waiting for admission", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newDriverPod(true, "example.com/other", KueueAdmissionSchedulingGate))
+		admission, err := GetAdmission(kueueApp(), kubeClient)
+		require.NoError(t, err)
+		assert.Equal(t, AdmissionQueued, admission)
+	})
+
+	t.Run("admitted", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newDriverPod(true, "example.com/other"))
+		admission, err := GetAdmission(kueueApp(), kubeClient)
+		require.NoError(t, err)
+		assert.Equal(t, AdmissionAdmitted, admission)
+	})
+
+	t.Run("not managed by kueue", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newDriverPod(false, "example.com/other", KueueAdmissionSchedulingGate))
+		admission, err := GetAdmission(kueueApp(), kubeClient)
+		require.NoError(t, err)
+		assert.Equal(t, AdmissionNone, admission)
+
+		// Nothing else would remove the admission gate, the driver pod must still be scheduled
+		pod, err := kubeClient.CoreV1().Pods("default").Get(context.TODO(), "test-app-driver", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []apiv1.PodSchedulingGate{{Name: "example.com/other"}}, pod.Spec.SchedulingGates)
+	})
+
+	t.Run("missing driver pod", func(t *testing.T) {
+		_, err := GetAdmission(kueueApp(), fake.NewSimpleClientset())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"fmt"
+	"nativesubmit/common"
+	"nativesubmit/internal/memory"
+	"nativesubmit/internal/resolver"
+	"strconv"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// GetDriverResources returns the cpu and memory requested by the driver pod, memory overhead included
+func GetDriverResources(config resolver.Config) (apiv1.ResourceList, error) {
+	return getResourceList(config.Driver)
+}
+
+// GetExecutorResources returns the cpu and memory requested by a single executor pod, memory overhead included
+func GetExecutorResources(config resolver.Config) (apiv1.ResourceList, error) {
+	return getResourceList(config.Executor)
+}
+
+// GetExecutorInstances returns the number of executors the application starts with. With dynamic allocation this is
+// the initial, or else the minimum, number of executors.
+func GetExecutorInstances(app *v1beta2.SparkApplication) (int, error) {
+	if dynamicAllocation := app.Spec.DynamicAllocation; dynamicAllocation != nil && dynamicAllocation.Enabled {
+		if dynamicAllocation.InitialExecutors != nil {
+			return int(*dynamicAllocation.InitialExecutors), nil
+		}
+		if dynamicAllocation.MinExecutors != nil {
+			return int(*dynamicAllocation.MinExecutors), nil
+		}
+		return 0, nil
+	}
+	if app.Spec.Executor.Instances != nil {
+		return int(*app.Spec.Executor.Instances), nil
+	}
+	if common.CheckSparkConf(app.Spec.SparkConf, SparkExecutorInstances) {
+		instances, err := strconv.Atoi(app.Spec.SparkConf[SparkExecutorInstances])
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for %s: %w", app.Spec.SparkConf[SparkExecutorInstances], SparkExecutorInstances, err)
+		}
+		return instances, nil
+	}
+	return ExecutorDefaultInstances, nil
+}
+
+// GetApplicationResources returns the resources of the driver and all the executors the application starts with
+func GetApplicationResources(app *v1beta2.SparkApplication, config resolver.Config) (apiv1.ResourceList, error) {
+	driverResources, err := GetDriverResources(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver resources: %w", err)
+	}
+	executorResources, err := GetExecutorResources(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid executor resources: %w", err)
+	}
+	instances, err := GetExecutorInstances(app)
+	if err != nil {
+		return nil, err
+	}
+
+	total := driverResources.DeepCopy()
+	for name, quantity := range executorResources {
+		sum := total[name]
+		for i := 0; i < instances; i++ {
+			sum.Add(quantity)
+		}
+		total[name] = sum
+	}
+	return total, nil
+}
+
+// getResourceList returns the core request, or else the cores, and the container memory of the role, memory overhead
+// included
+func getResourceList(roleConfig resolver.RoleConfig) (apiv1.ResourceList, error) {
+	cores := roleConfig.Cores
+	if roleConfig.CoreRequest.IsSet() {
+		cores = roleConfig.CoreRequest
+	}
+	cpu, err := resource.ParseQuantity(cores.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cores %q in %s: %w", cores.Value, cores.Describe(), err)
+	}
+	memoryMiB, err := memory.GetContainerMemoryMiB(roleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory: %w", err)
+	}
+	return apiv1.ResourceList{
+		apiv1.ResourceCPU:    cpu,
+		apiv1.ResourceMemory: *resource.NewQuantity(memoryMiB*memory.MiB, resource.BinarySI),
+	}, nil
+}
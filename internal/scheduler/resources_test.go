@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func stringPtr(s string) *string { return &s }
+func int32Ptr(i int32) *int32    { return &i }
+
+func assertResources(t *testing.T, wantCPU string, wantMemory string, got apiv1.ResourceList) {
+	t.Helper()
+	assert.True(t, resource.MustParse(wantCPU).Equal(got[apiv1.ResourceCPU]), "cpu: want %s, got %s", wantCPU, got.Cpu().String())
+	assert.True(t, resource.MustParse(wantMemory).Equal(got[apiv1.ResourceMemory]), "memory: want %s, got %s", wantMemory, got.Memory().String())
+}
+
+func TestGetDriverResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       v1beta2.SparkApplicationSpec
+		wantCPU    string
+		wantMemory string
+		wantErr    bool
+	}{
+		{name: "defaults", spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeJava}, wantCPU: "1", wantMemory: "1408Mi"},
+		{
+			name: "memory with minimum overhead",
+			spec: v1beta2.SparkApplicationSpec{
+				Type:   v1beta2.SparkApplicationTypeScala,
+				Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(2), Memory: stringPtr("1g")}},
+			},
+			wantCPU:    "2",
+			wantMemory: "1408Mi",
+		},
+		{
+			name: "memory with explicit overhead and core request",
+			spec: v1beta2.SparkApplicationSpec{
+				Driver: v1beta2.DriverSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{Memory: stringPtr("2g"), MemoryOverhead: stringPtr("512m")},
+					CoreRequest:  stringPtr("500m"),
+				},
+			},
+			wantCPU:    "500m",
+			wantMemory: "2560Mi",
+		},
+		{
+			name: "sparkConf memory with overhead factor",
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{SparkDriverMemory: "8g", SparkDriverCores: "3", "spark.driver.memoryOverheadFactor": "0.25"},
+			},
+			wantCPU:    "3",
+			wantMemory: "10Gi",
+		},
+		{
+			name:    "invalid cores",
+			spec:    v1beta2.SparkApplicationSpec{SparkConf: map[string]string{SparkDriverCores: "many"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDriverResources(resolver.Resolve(testutil.NewSparkApplication(tt.spec)))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assertResources(t, tt.wantCPU, tt.wantMemory, got)
+		})
+	}
+}
+
+func TestGetExecutorResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       v1beta2.SparkApplicationSpec
+		wantCPU    string
+		wantMemory string
+	}{
+		{name: "defaults", spec: v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeJava}, wantCPU: "1", wantMemory: "1408Mi"},
+		{
+			name: "python overhead factor",
+			spec: v1beta2.SparkApplicationSpec{
+				Type:     v1beta2.SparkApplicationTypePython,
+				Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(4), Memory: stringPtr("4g")}},
+			},
+			wantCPU:    "4",
+			wantMemory: "5734Mi",
+		},
+		{
+			name: "sparkConf values and shared overhead",
+			spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{
+					SparkExecutorMemory:               "2g",
+					SparkExecutorCores:                "2",
+					SparkExecutorCoreRequestKey:       "1500m",
+					"spark.kubernetes.memoryOverhead": "1g",
+				},
+			},
+			wantCPU:    "1500m",
+			wantMemory: "3Gi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExecutorResources(resolver.Resolve(testutil.NewSparkApplication(tt.spec)))
+			require.NoError(t, err)
+			assertResources(t, tt.wantCPU, tt.wantMemory, got)
+		})
+	}
+}
+
+func TestGetExecutorInstances(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1beta2.SparkApplicationSpec
+		want    int
+		wantErr bool
+	}{
+		{name: "spark default", want: ExecutorDefaultInstances},
+		{name: "spec", spec: v1beta2.SparkApplicationSpec{Executor: v1beta2.ExecutorSpec{Instances: int32Ptr(5)}}, want: 5},
+		{name: "sparkConf", spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{SparkExecutorInstances: "3"}}, want: 3},
+		{name: "invalid sparkConf", spec: v1beta2.SparkApplicationSpec{SparkConf: map[string]string{SparkExecutorInstances: "three"}}, wantErr: true},
+		{
+			name: "dynamic allocation initial executors",
+			spec: v1beta2.SparkApplicationSpec{
+				Executor:          v1beta2.ExecutorSpec{Instances: int32Ptr(5)},
+				DynamicAllocation: &v1beta2.DynamicAllocation{Enabled: true, InitialExecutors: int32Ptr(2), MinExecutors: int32Ptr(1)},
+			},
+			want: 2,
+		},
+		{
+			name: "dynamic allocation min executors",
+			spec: v1beta2.SparkApplicationSpec{DynamicAllocation: &v1beta2.DynamicAllocation{Enabled: true, MinExecutors: int32Ptr(1)}},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetExecutorInstances(testutil.NewSparkApplication(tt.spec))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetApplicationResources(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+		Type:   v1beta2.SparkApplicationTypeScala,
+		Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(1), Memory: stringPtr("1g")}},
+		Executor: v1beta2.ExecutorSpec{
+			SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(2), Memory: stringPtr("2g"), MemoryOverhead: stringPtr("512m")},
+			Instances:    int32Ptr(3),
+		},
+	})
+	got, err := GetApplicationResources(app, resolver.Resolve(app))
+	require.NoError(t, err)
+	// driver 1 core and 1024Mi + 384Mi, executors 3 x (2 cores and 2048Mi + 512Mi)
+	assertResources(t, "7", "9088Mi", got)
+}
@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// VolcanoPodGroupGVR is the resource of the Volcano PodGroups
+var VolcanoPodGroupGVR = schema.GroupVersionResource{
+	Group:    VolcanoPodGroupGroup,
+	Version:  VolcanoPodGroupVersion,
+	Resource: VolcanoPodGroupResource,
+}
+
+// IsVolcano reports whether the Spark Application is gang scheduled by Volcano
+func IsVolcano(app *v1beta2.SparkApplication) bool {
+	return app.Spec.BatchScheduler != nil && *app.Spec.BatchScheduler == VolcanoSchedulerName
+}
+
+// GetVolcanoPodGroupName returns the name of the PodGroup of the Spark Application
+func GetVolcanoPodGroupName(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("spark-%s-pg", app.Name)
+}
+
+// Helper func to create the Volcano PodGroup of the Spark Application when batchScheduler is volcano. The driver and
+// executors are then pointed at the PodGroup through their schedulerName and group annotation, so it must run before
+// the ConfigMap and driver pod are created. Both are recorded in the resolved configuration.
+func CreateVolcanoPodGroup(app *v1beta2.SparkApplication, config *resolver.Config, dynamicClient dynamic.Interface, createdApplicationId string) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+	if !IsVolcano(app) {
+		return nil
+	}
+	log.Printf("=== Starting Volcano PodGroup creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	podGroup, err := buildVolcanoPodGroup(app, *config, createdApplicationId)
+	if err != nil {
+		return err
+	}
+	log.Printf("Volcano PodGroup name: %s, spec: %v", podGroup.GetName(), podGroup.Object["spec"])
+
+	podGroupClient := dynamicClient.Resource(VolcanoPodGroupGVR).Namespace(podGroup.GetNamespace())
+	createPodGroupErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existingPodGroup, err := podGroupClient.Get(context.TODO(), podGroup.GetName(), metav1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, createErr := podGroupClient.Create(context.TODO(), podGroup, metav1.CreateOptions{})
+			if createErr != nil {
+				log.Printf("ERROR: Failed to create PodGroup: %v", createErr)
+			}
+			return createErr
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to get existing PodGroup: %v", err)
+			return err
+		}
+
+		log.Printf("PodGroup exists, updating...")
+		existingPodGroup.SetLabels(podGroup.GetLabels())
+		existingPodGroup.SetOwnerReferences(podGroup.GetOwnerReferences())
+		existingPodGroup.Object["spec"] = podGroup.Object["spec"]
+		_, updateErr := podGroupClient.Update(context.TODO(), existingPodGroup, metav1.UpdateOptions{})
+		if updateErr != nil {
+			log.Printf("ERROR: Failed to update PodGroup: %v", updateErr)
+		}
+		return updateErr
+	})
+	if createPodGroupErr != nil {
+		return fmt.Errorf("error while creating volcano pod group %s: %w", podGroup.GetName(), createPodGroupErr)
+	}
+
+	setSchedulerName(config, VolcanoSchedulerName)
+	config.Driver.Annotations = withEntry(config.Driver.Annotations, VolcanoGroupNameAnnotation, podGroup.GetName())
+	config.Executor.Annotations = withEntry(config.Executor.Annotations, VolcanoGroupNameAnnotation, podGroup.GetName())
+
+	log.Printf("=== Successfully completed Volcano PodGroup creation ===")
+	return nil
+}
+
+// buildVolcanoPodGroup sizes the PodGroup from the driver and executor resources, unless batchSchedulerOptions sets them
+func buildVolcanoPodGroup(app *v1beta2.SparkApplication, config resolver.Config, createdApplicationId string) (*unstructured.Unstructured, error) {
+	var minResources apiv1.ResourceList
+	var queue, priorityClassName *string
+	if options := app.Spec.BatchSchedulerOptions; options != nil {
+		minResources = options.Resources
+		queue = options.Queue
+		priorityClassName = options.PriorityClassName
+	}
+	if len(minResources) == 0 {
+		applicationResources, err := GetApplicationResources(app, config)
+		if err != nil {
+			return nil, fmt.Errorf("error while sizing volcano pod group: %w", err)
+		}
+		minResources = applicationResources
+	}
+
+	podGroupMinResources := make(map[string]interface{}, len(minResources))
+	for name, quantity := range minResources {
+		podGroupMinResources[string(name)] = quantity.String()
+	}
+	spec := map[string]interface{}{
+		"minMember":    int64(VolcanoPodGroupMinMember),
+		"minResources": podGroupMinResources,
+	}
+	if queue != nil {
+		spec["queue"] = *queue
+	}
+	if priorityClassName != nil {
+		spec["priorityClassName"] = *priorityClassName
+	}
+
+	podGroup := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	podGroup.SetAPIVersion(VolcanoPodGroupGVR.GroupVersion().String())
+	podGroup.SetKind(VolcanoPodGroupKind)
+	podGroup.SetName(GetVolcanoPodGroupName(app))
+	podGroup.SetNamespace(common.GetAppNamespace(app))
+	podGroup.SetLabels(map[string]string{
+		common.SparkApplicationSelectorLabel: createdApplicationId,
+		common.SparkAppNameLabel:             app.Name,
+	})
+	// Owned by the SparkApplication as it is created before the driver pod
+	podGroup.SetOwnerReferences([]metav1.OwnerReference{*common.GetOwnerReference(app)})
+	return podGroup, nil
+}
+
+// withEntry returns the resolved labels or annotations with the given entry set by the batch scheduler, allocating
+// the map when needed
+func withEntry(entries map[string]resolver.Setting, key string, value string) map[string]resolver.Setting {
+	if entries == nil {
+		entries = make(map[string]resolver.Setting)
+	}
+	entries[key] = entries[key].Override(value, resolver.SourceBatchScheduler, BatchSchedulerField)
+	return entries
+}
+
+// setSchedulerName points the driver and executor pods at the batch scheduler
+func setSchedulerName(config *resolver.Config, schedulerName string) {
+	config.Driver.SchedulerName = config.Driver.SchedulerName.Override(schedulerName, resolver.SourceBatchScheduler, BatchSchedulerField)
+	config.Executor.SchedulerName = config.Executor.SchedulerName.Override(schedulerName, resolver.SourceBatchScheduler, BatchSchedulerField)
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		VolcanoPodGroupGVR: VolcanoPodGroupKind + "List",
+	}, objects...)
+}
+
+func getPodGroup(t *testing.T, client *dynamicfake.FakeDynamicClient, app *v1beta2.SparkApplication) *unstructured.Unstructured {
+	t.Helper()
+	podGroup, err := client.Resource(VolcanoPodGroupGVR).Namespace(app.Namespace).Get(context.TODO(), GetVolcanoPodGroupName(app), metav1.GetOptions{})
+	require.NoError(t, err)
+	return podGroup
+}
+
+func TestCreateVolcanoPodGroup(t *testing.T) {
+	volcano := VolcanoSchedulerName
+
+	t.Run("not volcano", func(t *testing.T) {
+		client := newFakeDynamicClient()
+		yunikorn := "yunikorn"
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{BatchScheduler: &yunikorn})
+		config := resolver.Resolve(app)
+		require.NoError(t, CreateVolcanoPodGroup(app, &config, client, "spark-app-id"))
+		assert.Empty(t, client.Actions())
+		assert.False(t, config.Driver.SchedulerName.IsSet())
+		assert.Nil(t, config.Executor.Annotations)
+	})
+
+	t.Run("sized from driver and executors", func(t *testing.T) {
+		client := newFakeDynamicClient()
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			Type:           v1beta2.SparkApplicationTypeScala,
+			BatchScheduler: &volcano,
+			Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+				Cores:       int32Ptr(1),
+				Memory:      stringPtr("1g"),
+				Annotations: map[string]string{"team": "data"},
+			}},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: int32Ptr(2), Memory: stringPtr("2g"), MemoryOverhead: stringPtr("512m")},
+				Instances:    int32Ptr(3),
+			},
+		})
+		config := resolver.Resolve(app)
+		require.NoError(t, CreateVolcanoPodGroup(app, &config, client, "spark-app-id"))
+
+		podGroup := getPodGroup(t, client, app)
+		assert.Equal(t, "spark-test-app-pg", podGroup.GetName())
+		assert.Equal(t, "scheduling.volcano.sh/v1beta1", podGroup.GetAPIVersion())
+		assert.Equal(t, "spark-app-id", podGroup.GetLabels()[common.SparkApplicationSelectorLabel])
+		require.Len(t, podGroup.GetOwnerReferences(), 1)
+		assert.Equal(t, "SparkApplication", podGroup.GetOwnerReferences()[0].Kind)
+
+		minMember, _, _ := unstructured.NestedInt64(podGroup.Object, "spec", "minMember")
+		assert.Equal(t, int64(VolcanoPodGroupMinMember), minMember)
+		minResources, _, _ := unstructured.NestedStringMap(podGroup.Object, "spec", "minResources")
+		assert.True(t, resource.MustParse("7").Equal(resource.MustParse(minResources["cpu"])))
+		assert.True(t, resource.MustParse("9088Mi").Equal(resource.MustParse(minResources["memory"])))
+		_, hasQueue, _ := unstructured.NestedString(podGroup.Object, "spec", "queue")
+		assert.False(t, hasQueue)
+
+		assert.Equal(t, volcano, config.Driver.SchedulerName.Value)
+		assert.Equal(t, volcano, config.Executor.SchedulerName.Value)
+		assert.Equal(t, map[string]string{"team": "data", VolcanoGroupNameAnnotation: "spark-test-app-pg"}, resolver.Values(config.Driver.Annotations))
+		assert.Equal(t, map[string]string{VolcanoGroupNameAnnotation: "spark-test-app-pg"}, resolver.Values(config.Executor.Annotations))
+	})
+
+	t.Run("batch scheduler options", func(t *testing.T) {
+		client := newFakeDynamicClient()
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			BatchScheduler: &volcano,
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{
+				Queue:             stringPtr("analytics"),
+				PriorityClassName: stringPtr("high-priority"),
+				Resources: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("10"),
+					apiv1.ResourceMemory: resource.MustParse("20Gi"),
+				},
+			},
+		})
+		config := resolver.Resolve(app)
+		require.NoError(t, CreateVolcanoPodGroup(app, &config, client, "spark-app-id"))
+
+		podGroup := getPodGroup(t, client, app)
+		queue, _, _ := unstructured.NestedString(podGroup.Object, "spec", "queue")
+		assert.Equal(t, "analytics", queue)
+		priorityClassName, _, _ := unstructured.NestedString(podGroup.Object, "spec", "priorityClassName")
+		assert.Equal(t, "high-priority", priorityClassName)
+		minResources, _, _ := unstructured.NestedStringMap(podGroup.Object, "spec", "minResources")
+		assert.Equal(t, map[string]string{"cpu": "10", "memory": "20Gi"}, minResources)
+	})
+
+	t.Run("updates existing pod group", func(t *testing.T) {
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			BatchScheduler:        &volcano,
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{Queue: stringPtr("new-queue")},
+		})
+		existing, err := buildVolcanoPodGroup(app, resolver.Resolve(app), "old-app-id")
+		require.NoError(t, err)
+		require.NoError(t, unstructured.SetNestedField(existing.Object, "old-queue", "spec", "queue"))
+		client := newFakeDynamicClient(existing)
+
+		config := resolver.Resolve(app)
+		require.NoError(t, CreateVolcanoPodGroup(app, &config, client, "spark-app-id"))
+
+		podGroup := getPodGroup(t, client, app)
+		queue, _, _ := unstructured.NestedString(podGroup.Object, "spec", "queue")
+		assert.Equal(t, "new-queue", queue)
+		assert.Equal(t, "spark-app-id", podGroup.GetLabels()[common.SparkApplicationSelectorLabel])
+	})
+
+	t.Run("invalid resources", func(t *testing.T) {
+		client := newFakeDynamicClient()
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			BatchScheduler: &volcano,
+			SparkConf:      map[string]string{SparkExecutorCores: "lots"},
+		})
+		config := resolver.Resolve(app)
+		assert.Error(t, CreateVolcanoPodGroup(app, &config, client, "spark-app-id"))
+		assert.False(t, config.Driver.SchedulerName.IsSet())
+	})
+
+	t.Run("nil application", func(t *testing.T) {
+		assert.Error(t, CreateVolcanoPodGroup(nil, &resolver.Config{}, newFakeDynamicClient(), "spark-app-id"))
+	})
+}
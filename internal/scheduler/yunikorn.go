@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"nativesubmit/internal/resolver"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// yuniKornTaskGroup is a task group of the yunikorn.apache.org/task-groups annotation
+type yuniKornTaskGroup struct {
+	Name         string             `json:"name"`
+	MinMember    int32              `json:"minMember"`
+	MinResource  map[string]string  `json:"minResource"`
+	NodeSelector map[string]string  `json:"nodeSelector,omitempty"`
+	Tolerations  []apiv1.Toleration `json:"tolerations,omitempty"`
+	Affinity     *apiv1.Affinity    `json:"affinity,omitempty"`
+}
+
+// IsYuniKorn reports whether the Spark Application is gang scheduled by Apache YuniKorn
+func IsYuniKorn(app *v1beta2.SparkApplication) bool {
+	return app.Spec.BatchScheduler != nil && *app.Spec.BatchScheduler == YuniKornSchedulerName
+}
+
+// Helper func to point the driver and executors at YuniKorn when batchScheduler is yunikorn. YuniKorn reserves the
+// task groups declared on the driver, so nothing is created in the cluster, but it must run before the ConfigMap and
+// driver pod are created. The scheduler name and annotations are recorded in the resolved configuration.
+func ConfigureYuniKorn(app *v1beta2.SparkApplication, config *resolver.Config, createdApplicationId string) error {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return fmt.Errorf("spark application cannot be nil")
+	}
+	if !IsYuniKorn(app) {
+		return nil
+	}
+	log.Printf("=== Starting YuniKorn task groups configuration for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	taskGroups, err := buildYuniKornTaskGroups(app, *config)
+	if err != nil {
+		return fmt.Errorf("error while sizing yunikorn task groups: %w", err)
+	}
+	taskGroupsJSON, err := json.Marshal(taskGroups)
+	if err != nil {
+		return fmt.Errorf("error while encoding yunikorn task groups: %w", err)
+	}
+	log.Printf("YuniKorn task groups: %s", taskGroupsJSON)
+
+	annotations := map[string]string{YuniKornAppIDAnnotation: createdApplicationId}
+	if options := app.Spec.BatchSchedulerOptions; options != nil && options.Queue != nil {
+		annotations[YuniKornQueueAnnotation] = *options.Queue
+	}
+	for key, value := range annotations {
+		config.Driver.Annotations = withEntry(config.Driver.Annotations, key, value)
+		config.Executor.Annotations = withEntry(config.Executor.Annotations, key, value)
+	}
+	config.Driver.Annotations = withEntry(config.Driver.Annotations, YuniKornTaskGroupsAnnotation, string(taskGroupsJSON))
+	config.Driver.Annotations = withEntry(config.Driver.Annotations, YuniKornTaskGroupNameAnnotation, YuniKornDriverTaskGroupName)
+	config.Executor.Annotations = withEntry(config.Executor.Annotations, YuniKornTaskGroupNameAnnotation, YuniKornExecutorTaskGroupName)
+
+	setSchedulerName(config, YuniKornSchedulerName)
+
+	log.Printf("=== Successfully completed YuniKorn task groups configuration ===")
+	return nil
+}
+
+// buildYuniKornTaskGroups sizes a driver task group and, when the application starts with executors, an executor
+// task group with one member per initial executor. The node selectors are the resolved ones the pods get.
+func buildYuniKornTaskGroups(app *v1beta2.SparkApplication, config resolver.Config) ([]yuniKornTaskGroup, error) {
+	driverResources, err := GetDriverResources(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver resources: %w", err)
+	}
+	taskGroups := []yuniKornTaskGroup{{
+		Name:         YuniKornDriverTaskGroupName,
+		MinMember:    1,
+		MinResource:  toMinResource(driverResources),
+		NodeSelector: resolver.Values(config.Driver.NodeSelector),
+		Tolerations:  app.Spec.Driver.Tolerations,
+		Affinity:     app.Spec.Driver.Affinity,
+	}}
+
+	instances, err := GetExecutorInstances(app)
+	if err != nil {
+		return nil, err
+	}
+	if instances == 0 {
+		return taskGroups, nil
+	}
+	executorResources, err := GetExecutorResources(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid executor resources: %w", err)
+	}
+	return append(taskGroups, yuniKornTaskGroup{
+		Name:         YuniKornExecutorTaskGroupName,
+		MinMember:    int32(instances),
+		MinResource:  toMinResource(executorResources),
+		NodeSelector: resolver.Values(config.Executor.NodeSelector),
+		Tolerations:  app.Spec.Executor.Tolerations,
+		Affinity:     app.Spec.Executor.Affinity,
+	}), nil
+}
+
+func toMinResource(resources apiv1.ResourceList) map[string]string {
+	minResource := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		minResource[string(name)] = quantity.String()
+	}
+	return minResource
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func TestConfigureYuniKorn(t *testing.T) {
+	yunikorn := YuniKornSchedulerName
+
+	t.Run("not yunikorn", func(t *testing.T) {
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+		config := resolver.Resolve(app)
+		require.NoError(t, ConfigureYuniKorn(app, &config, "spark-app-id"))
+		assert.False(t, config.Driver.SchedulerName.IsSet())
+		assert.Nil(t, config.Driver.Annotations)
+	})
+
+	t.Run("task groups", func(t *testing.T) {
+		toleration := apiv1.Toleration{Key: "spark", Operator: apiv1.TolerationOpExists}
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			Type:                  v1beta2.SparkApplicationTypeScala,
+			BatchScheduler:        &yunikorn,
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{Queue: stringPtr("root.analytics")},
+			NodeSelector:          map[string]string{"pool": "spark"},
+			Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{
+				Cores:       int32Ptr(1),
+				Memory:      stringPtr("1g"),
+				Annotations: map[string]string{"team": "data"},
+			}},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Cores:        int32Ptr(2),
+					Memory:       stringPtr("2g"),
+					NodeSelector: map[string]string{"pool": "executors"},
+					Tolerations:  []apiv1.Toleration{toleration},
+				},
+				Instances: int32Ptr(3),
+			},
+		})
+		config := resolver.Resolve(app)
+		require.NoError(t, ConfigureYuniKorn(app, &config, "spark-app-id"))
+
+		assert.Equal(t, yunikorn, config.Driver.SchedulerName.Value)
+		assert.Equal(t, yunikorn, config.Executor.SchedulerName.Value)
+		assert.Equal(t, "data", config.Driver.Annotations["team"].Value)
+		assert.Equal(t, "spark-app-id", config.Driver.Annotations[YuniKornAppIDAnnotation].Value)
+		assert.Equal(t, "root.analytics", config.Driver.Annotations[YuniKornQueueAnnotation].Value)
+		assert.Equal(t, YuniKornDriverTaskGroupName, config.Driver.Annotations[YuniKornTaskGroupNameAnnotation].Value)
+		assert.Equal(t, map[string]string{
+			YuniKornAppIDAnnotation:         "spark-app-id",
+			YuniKornQueueAnnotation:         "root.analytics",
+			YuniKornTaskGroupNameAnnotation: YuniKornExecutorTaskGroupName,
+		}, resolver.Values(config.Executor.Annotations))
+
+		var taskGroups []yuniKornTaskGroup
+		require.NoError(t, json.Unmarshal([]byte(config.Driver.Annotations[YuniKornTaskGroupsAnnotation].Value), &taskGroups))
+		assert.Equal(t, []yuniKornTaskGroup{
+			{
+				Name:         YuniKornDriverTaskGroupName,
+				MinMember:    1,
+				MinResource:  map[string]string{"cpu": "1", "memory": "1408Mi"},
+				NodeSelector: map[string]string{"pool": "spark"},
+			},
+			{
+				Name:         YuniKornExecutorTaskGroupName,
+				MinMember:    3,
+				MinResource:  map[string]string{"cpu": "2", "memory": "2432Mi"},
+				NodeSelector: map[string]string{"pool": "executors"},
+				Tolerations:  []apiv1.Toleration{toleration},
+			},
+		}, taskGroups)
+	})
+
+	t.Run("no initial executors", func(t *testing.T) {
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			BatchScheduler:    &yunikorn,
+			DynamicAllocation: &v1beta2.DynamicAllocation{Enabled: true, MinExecutors: int32Ptr(0)},
+		})
+		config := resolver.Resolve(app)
+		require.NoError(t, ConfigureYuniKorn(app, &config, "spark-app-id"))
+
+		var taskGroups []yuniKornTaskGroup
+		require.NoError(t, json.Unmarshal([]byte(config.Driver.Annotations[YuniKornTaskGroupsAnnotation].Value), &taskGroups))
+		require.Len(t, taskGroups, 1)
+		assert.Equal(t, YuniKornDriverTaskGroupName, taskGroups[0].Name)
+		_, hasQueue := config.Driver.Annotations[YuniKornQueueAnnotation]
+		assert.False(t, hasQueue)
+	})
+
+	t.Run("invalid resources", func(t *testing.T) {
+		app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+			BatchScheduler: &yunikorn,
+			SparkConf:      map[string]string{SparkDriverCores: "lots"},
+		})
+		config := resolver.Resolve(app)
+		assert.Error(t, ConfigureYuniKorn(app, &config, "spark-app-id"))
+		assert.False(t, config.Driver.SchedulerName.IsSet())
+	})
+}
@@ -5,11 +5,10 @@ import (
 	"fmt"
 	"log"
 	"nativesubmit/common"
-	"strconv"
+	"nativesubmit/internal/resolver"
 	"strings"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	apiv1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -19,8 +18,8 @@ import (
 	"k8s.io/client-go/util/retry"
 )
 
-// Helper func to create Service for the Driver Pod of the Spark Application
-func Create(app *v1beta2.SparkApplication, serviceSelectorLabels map[string]string, kubeClient *kubernetes.Clientset, createdApplicationId string, serviceName string, driverPodUID string) error {
+// Helper func to create Service for the Driver Pod of the Spark Application, with the ports of the resolved configuration
+func Create(app *v1beta2.SparkApplication, config resolver.Config, serviceSelectorLabels map[string]string, kubeClient kubernetes.Interface, createdApplicationId string, serviceName string, driverPodUID string) error {
 	log.Printf("=== Starting Driver Service creation for app: %s, namespace: %s ===", app.Name, app.Namespace)
 	log.Printf("Service name: %s, Application ID: %s, Driver Pod UID: %s", serviceName, createdApplicationId, driverPodUID)
 	log.Printf("Service selector labels count: %d", len(serviceSelectorLabels))
@@ -39,78 +38,50 @@ func Create(app *v1beta2.SparkApplication, serviceSelectorLabels map[string]stri
 	serviceObjectMetaData.Namespace = common.GetAppNamespace(app)
 	// Service Schema Owner References - Use service-specific owner reference for proper garbage collection protection
 	serviceObjectMetaData.OwnerReferences = []metav1.OwnerReference{*common.GetServiceOwnerReference(app, driverPodUID)}
-	//Service Schema label
-	serviceLabels := map[string]string{SparkApplicationSelectorLabel: createdApplicationId}
-	log.Printf("Service object metadata - Name: %s, Namespace: %s", serviceObjectMetaData.Name, serviceObjectMetaData.Namespace)
+	serviceObjectMetaData.Labels = getServiceLabels(app, createdApplicationId)
+	log.Printf("Service object metadata - Name: %s, Namespace: %s, labels count: %d", serviceObjectMetaData.Name, serviceObjectMetaData.Namespace, len(serviceObjectMetaData.Labels))
 
-	// labels passed in sparkConf
-	sparkConfKeyValuePairs := app.Spec.SparkConf
-	for sparkConfKey, sparkConfValue := range sparkConfKeyValuePairs {
-		if strings.Contains(sparkConfKey, "spark.kubernetes.driver.service.label.") {
-			lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
-			labelKey := sparkConfKey[lastDotIndex+1:]
-			labelValue := sparkConfValue
-			serviceLabels[labelKey] = labelValue
-		}
-	}
-	log.Printf("Service labels count after sparkConf processing: %d", len(serviceLabels))
-
-	serviceObjectMetaData.Labels = serviceLabels
-	annotations := map[string]string{
-		"kubernetes.io/change-cause": "spark-driver-service-created-by-native-submit",
-	}
-
-	// Merge driver annotations if they exist
-	if app.Spec.Driver.Annotations != nil {
-		for key, value := range app.Spec.Driver.Annotations {
-			annotations[key] = value
-		}
-		log.Printf("Using driver annotations for service")
-	}
-
-	serviceObjectMetaData.Annotations = annotations
-	log.Printf("Service annotations set: %+v", annotations)
+	serviceObjectMetaData.Annotations = getServiceAnnotations(app)
+	log.Printf("Service annotations set: %+v", serviceObjectMetaData.Annotations)
 
 	// // Add finalizer to prevent garbage collection
 	// serviceObjectMetaData.Finalizers = []string{"service.native-submit.io/finalizer"}
 	// log.Printf("Service finalizers set: %+v", serviceObjectMetaData.Finalizers)
 
+	servicePorts, err := getServicePorts(config.Driver)
+	if err != nil {
+		return fmt.Errorf("invalid ports for the driver service %s: %w", serviceName, err)
+	}
+
 	//Service Schema Creation
 	driverPodService := &apiv1.Service{
 		ObjectMeta: serviceObjectMetaData,
 		Spec: apiv1.ServiceSpec{
-			ClusterIP: None,
-			Ports: []apiv1.ServicePort{
-				{
-					Name:     DriverPortName,
-					Port:     getDriverNBlockManagerPort(app, DriverPortProperty, common.DefaultDriverPort),
-					Protocol: Protocol,
-					TargetPort: intstr.IntOrString{
-						IntVal: common.DefaultDriverPort,
-					},
-				},
-				{
-					Name:     BlockManagerPortName,
-					Port:     getDriverPodBlockManagerPort(app),
-					Protocol: Protocol,
-					TargetPort: intstr.IntOrString{
-						IntVal: common.DefaultBlockManagerPort,
-					},
-				},
-				{
-					Name:     UiPortName,
-					Port:     UiPort,
-					Protocol: Protocol,
-					TargetPort: intstr.IntOrString{
-						IntVal: UiPort,
-					},
-				},
-			},
+			ClusterIP:       None,
+			Ports:           servicePorts,
 			Selector:        serviceSelectorLabels,
 			SessionAffinity: None,
 			Type:            ClusterIP,
 		},
 	}
+	//Prometheus JMX exporter port
+	if common.ExposesDriverMetrics(app) {
+		driverPodService.Spec.Ports = append(driverPodService.Spec.Ports, apiv1.ServicePort{
+			Name:       common.GetPrometheusPortName(app),
+			Port:       common.GetPrometheusPort(app),
+			Protocol:   Protocol,
+			TargetPort: intstr.FromInt32(common.GetPrometheusPort(app)),
+		})
+	}
+	//Additional ports declared in the driver spec, validated against the reserved ports on driver pod creation
+	for _, port := range app.Spec.Driver.Ports {
+		driverPodService.Spec.Ports = append(driverPodService.Spec.Ports, apiv1.ServicePort{
+			Name:       port.Name,
+			Port:       port.ContainerPort,
+			Protocol:   apiv1.Protocol(common.GetPortProtocol(port)),
+			TargetPort: intstr.FromInt32(port.ContainerPort),
+		})
+	}
 	log.Printf("Driver service object created with %d ports", len(driverPodService.Spec.Ports))
 	log.Printf("Driver service object: %+v", driverPodService)
 
@@ -157,7 +128,45 @@ func Create(app *v1beta2.SparkApplication, serviceSelectorLabels map[string]stri
 	return createServiceErr
 }
 
-func createAndCheckDriverService(kubeClient *kubernetes.Clientset, app *v1beta2.SparkApplication, driverPodService *apiv1.Service, attemptCount int, serviceName string) error {
+// getServiceLabels returns the driver service labels. Priority wise: the application selector label, then
+// app.Spec.Driver.ServiceLabels, then spark.kubernetes.driver.service.label.* in sparkConf
+func getServiceLabels(app *v1beta2.SparkApplication, createdApplicationId string) map[string]string {
+	serviceLabels := getSparkConfWithPrefix(app.Spec.SparkConf, DriverServiceLabelPrefix)
+	for key, value := range app.Spec.Driver.ServiceLabels {
+		serviceLabels[key] = value
+	}
+	serviceLabels[common.SparkApplicationSelectorLabel] = createdApplicationId
+	return serviceLabels
+}
+
+// getServiceAnnotations returns the driver service annotations. Priority wise: app.Spec.Driver.ServiceAnnotations,
+// then spark.kubernetes.driver.service.annotation.* in sparkConf, then the default change-cause annotation.
+// Driver pod annotations are not copied onto the service.
+func getServiceAnnotations(app *v1beta2.SparkApplication) map[string]string {
+	annotations := map[string]string{
+		ChangeCauseAnnotation: DriverServiceChangeCause,
+	}
+	for key, value := range getSparkConfWithPrefix(app.Spec.SparkConf, DriverServiceAnnotationPrefix) {
+		annotations[key] = value
+	}
+	for key, value := range app.Spec.Driver.ServiceAnnotations {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+// getSparkConfWithPrefix returns the sparkConf entries starting with prefix, keyed by the remainder of the key
+func getSparkConfWithPrefix(sparkConf map[string]string, prefix string) map[string]string {
+	values := make(map[string]string)
+	for key, value := range sparkConf {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			values[strings.TrimPrefix(key, prefix)] = value
+		}
+	}
+	return values
+}
+
+func createAndCheckDriverService(kubeClient kubernetes.Interface, app *v1beta2.SparkApplication, driverPodService *apiv1.Service, attemptCount int, serviceName string) error {
 	const sleepDuration = 2000 * time.Millisecond
 
 	log.Printf("=== Starting createAndCheckDriverService for app: %s, service: %s, namespace: %s ===", app.Name, serviceName, app.Namespace)
@@ -238,22 +247,22 @@ func createAndCheckDriverService(kubeClient *kubernetes.Clientset, app *v1beta2.
 	return nil
 }
 
-func getDriverPodBlockManagerPort(app *v1beta2.SparkApplication) int32 {
-	if common.CheckSparkConf(app.Spec.SparkConf, DriverBlockManagerPortProperty) {
-		return getDriverNBlockManagerPort(app, DriverBlockManagerPortProperty, common.DefaultBlockManagerPort)
-	}
-	return common.DefaultBlockManagerPort
-}
-
-func getDriverNBlockManagerPort(app *v1beta2.SparkApplication, portConfig string, defaultPort int32) int32 {
-	if common.CheckSparkConf(app.Spec.SparkConf, portConfig) {
-		value, _ := app.Spec.SparkConf[portConfig]
-		portVal, parseError := strconv.ParseInt(value, 10, 64)
-		if parseError != nil {
-			glog.Errorf("failed to parse %s in namespace %s: %v", portConfig, app.Namespace, parseError)
-			return defaultPort
+// getServicePorts returns the driver RPC, block manager and Spark UI ports of the driver, as resolved for the driver
+// container. Each service port targets the same container port.
+func getServicePorts(driverConfig resolver.RoleConfig) ([]apiv1.ServicePort, error) {
+	ports := driverConfig.Ports
+	var servicePorts []apiv1.ServicePort
+	for _, name := range []string{DriverPortName, BlockManagerPortName, UiPortName} {
+		port, err := resolver.ParsePort(ports[name])
+		if err != nil {
+			return nil, err
 		}
-		return int32(portVal)
+		servicePorts = append(servicePorts, apiv1.ServicePort{
+			Name:       name,
+			Port:       port,
+			Protocol:   Protocol,
+			TargetPort: intstr.FromInt32(port),
+		})
 	}
-	return defaultPort
+	return servicePorts, nil
 }
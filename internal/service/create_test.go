@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetServiceLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		sparkConf map[string]string
+		driver    v1beta2.DriverSpec
+		want      map[string]string
+	}{
+		{
+			name: "application selector only",
+			want: map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id"},
+		},
+		{
+			name:      "sparkConf labels keep dotted keys",
+			sparkConf: map[string]string{"spark.kubernetes.driver.service.label.app.kubernetes.io/name": "spark"},
+			want:      map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id", "app.kubernetes.io/name": "spark"},
+		},
+		{
+			name:      "service labels override sparkConf",
+			sparkConf: map[string]string{"spark.kubernetes.driver.service.label.team": "from-conf"},
+			driver:    v1beta2.DriverSpec{ServiceLabels: map[string]string{"team": "from-spec"}},
+			want:      map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id", "team": "from-spec"},
+		},
+		{
+			name:   "application selector cannot be overridden",
+			driver: v1beta2.DriverSpec{ServiceLabels: map[string]string{common.SparkApplicationSelectorLabel: "spoofed"}},
+			want:   map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id"},
+		},
+		{
+			name: "driver pod labels are not used",
+			driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Labels: map[string]string{"pod-only": "true"}},
+			},
+			want: map[string]string{common.SparkApplicationSelectorLabel: "spark-app-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getServiceLabels(testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: tt.sparkConf, Driver: tt.driver}), "spark-app-id"))
+		})
+	}
+}
+
+func TestGetServiceAnnotations(t *testing.T) {
+	tests := []struct {
+		name      string
+		sparkConf map[string]string
+		driver    v1beta2.DriverSpec
+		want      map[string]string
+	}{
+		{
+			name: "default change cause",
+			want: map[string]string{ChangeCauseAnnotation: DriverServiceChangeCause},
+		},
+		{
+			name:      "service annotations override sparkConf",
+			sparkConf: map[string]string{"spark.kubernetes.driver.service.annotation.mesh.io/inject": "false"},
+			driver:    v1beta2.DriverSpec{ServiceAnnotations: map[string]string{"mesh.io/inject": "true", ChangeCauseAnnotation: "custom"}},
+			want:      map[string]string{ChangeCauseAnnotation: "custom", "mesh.io/inject": "true"},
+		},
+		{
+			name: "driver pod annotations are not leaked",
+			driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Annotations: map[string]string{"pod-only": "true"}},
+			},
+			want: map[string]string{ChangeCauseAnnotation: DriverServiceChangeCause},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getServiceAnnotations(testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: tt.sparkConf, Driver: tt.driver})))
+		})
+	}
+}
+
+func TestCreateAppliesServiceLabelsAndAnnotations(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Driver: v1beta2.DriverSpec{
+		SparkPodSpec:       v1beta2.SparkPodSpec{Annotations: map[string]string{"pod-only": "true"}},
+		ServiceLabels:      map[string]string{"monitoring": "enabled"},
+		ServiceAnnotations: map[string]string{"prometheus.io/scrape": "true"},
+	}})
+	kubeClient := fake.NewSimpleClientset()
+	require.NoError(t, Create(app, resolver.Resolve(app), map[string]string{"spark-role": "driver"}, kubeClient, "spark-app-id", "test-app-driver-svc", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-driver-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "enabled", service.Labels["monitoring"])
+	assert.Equal(t, "spark-app-id", service.Labels[common.SparkApplicationSelectorLabel])
+	assert.Equal(t, "true", service.Annotations["prometheus.io/scrape"])
+	assert.NotContains(t, service.Annotations, "pod-only")
+	assert.Equal(t, map[string]string{"spark-role": "driver"}, service.Spec.Selector)
+}
+
+func TestCreateExposesPrometheusPort(t *testing.T) {
+	port := int32(9100)
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{})
+	app.Spec.Monitoring = &v1beta2.MonitoringSpec{
+		ExposeDriverMetrics: true,
+		Prometheus:          &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar", Port: &port},
+	}
+	kubeClient := fake.NewSimpleClientset()
+	require.NoError(t, Create(app, resolver.Resolve(app), map[string]string{"spark-role": "driver"}, kubeClient, "spark-app-id", "test-app-driver-svc", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-driver-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, service.Spec.Ports, 4)
+	assert.Equal(t, "jmx-exporter", service.Spec.Ports[3].Name)
+	assert.Equal(t, int32(9100), service.Spec.Ports[3].Port)
+	assert.Equal(t, int32(9100), service.Spec.Ports[3].TargetPort.IntVal)
+}
+
+func TestCreateTargetsResolvedPorts(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: map[string]string{"spark.driver.port": "7000", "spark.blockManager.port": "7100", "spark.ui.port": "4050"}})
+	kubeClient := fake.NewSimpleClientset()
+	require.NoError(t, Create(app, resolver.Resolve(app), map[string]string{"spark-role": "driver"}, kubeClient, "spark-app-id", "test-app-driver-svc", "driver-pod-uid"))
+
+	service, err := kubeClient.CoreV1().Services("default").Get(context.TODO(), "test-app-driver-svc", metav1.GetOptions{})
+	require.NoError(t, err)
+	ports := make(map[string][2]int32)
+	for _, port := range service.Spec.Ports {
+		ports[port.Name] = [2]int32{port.Port, port.TargetPort.IntVal}
+	}
+	assert.Equal(t, map[string][2]int32{"driver-rpc-port": {7000, 7000}, "blockmanager": {7100, 7100}, "spark-ui": {4050, 4050}}, ports)
+
+	app = testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{SparkConf: map[string]string{"spark.driver.port": "rpc"}})
+	assert.ErrorContains(t, Create(app, resolver.Resolve(app), nil, fake.NewSimpleClientset(), "spark-app-id", "test-app-driver-svc", "driver-pod-uid"), `invalid port "rpc" in spec.sparkConf[spark.driver.port]`)
+}
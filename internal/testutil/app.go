@@ -0,0 +1,25 @@
+// Package testutil holds the fixtures shared by the package tests.
+package testutil
+
+import (
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AppName      = "test-app"
+	AppNamespace = "default"
+	AppUID       = "test-uid-123"
+)
+
+// NewSparkApplication returns the test-app SparkApplication of the default namespace with the given spec
+func NewSparkApplication(spec v1beta2.SparkApplicationSpec) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AppName,
+			Namespace: AppNamespace,
+			UID:       AppUID,
+		},
+		Spec: spec,
+	}
+}
@@ -0,0 +1,22 @@
+package validation
+
+const (
+	// ConfigMapNameExtension and ServiceNameExtension are appended to the driver pod name by the submission
+	ConfigMapNameExtension = "-conf-map"
+	ServiceNameExtension   = "-svc"
+
+	SparkDriverCores                   = "spark.driver.cores"
+	SparkExecutorCores                 = "spark.executor.cores"
+	SparkExecutorCoreRequestKey        = "spark.kubernetes.executor.request.cores"
+	SparkExecutorCoreLimitKey          = "spark.kubernetes.executor.limit.cores"
+	SparkDriverMemory                  = "spark.driver.memory"
+	SparkExecutorMemory                = "spark.executor.memory"
+	SparkDriverMemoryOverhead          = "spark.driver.memoryOverhead"
+	SparkExecutorMemoryOverhead        = "spark.executor.memoryOverhead"
+	SparkDriverMemoryOverheadFactor    = "spark.driver.memoryOverheadFactor"
+	SparkExecutorMemoryOverheadFactor  = "spark.executor.memoryOverheadFactor"
+	SparkExecutorInstances             = "spark.executor.instances"
+	SparkFilesFetchTimeout             = "spark.files.fetchTimeout"
+	PythonMainApplicationFileExtension = ".py"
+	RMainApplicationFileExtension      = ".R"
+)
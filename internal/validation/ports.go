@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"fmt"
+	"nativesubmit/common"
+	"nativesubmit/internal/resolver"
+	"strconv"
+	"strings"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// driverPort is a port of the driver container with the field setting it
+type driverPort struct {
+	name     string
+	number   int32
+	protocol string
+	path     *field.Path
+}
+
+// reservedPortKeys are the sparkConf keys of the ports the driver container always exposes, in the order the
+// container declares them. Defaulted ports are reported against these keys.
+var reservedPortKeys = []struct {
+	name string
+	key  string
+}{
+	{resolver.DriverPortName, common.SparkDriverPort},
+	{resolver.BlockManagerPortName, resolver.SparkBlockManagerPortKey},
+	{resolver.UIPortName, common.SparkUIPortKey},
+}
+
+// ValidatePorts checks the resolved driver ports parse and that none of the reserved and user declared driver ports
+// collide, as well as the Spark UI and driver ingress Service ports
+func ValidatePorts(app *v1beta2.SparkApplication, config resolver.Config) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	var ports []driverPort
+	for _, reserved := range reservedPortKeys {
+		setting := config.Driver.Ports[reserved.name]
+		port, errs := parsePortSetting(setting, reserved.key)
+		allErrs = append(allErrs, errs...)
+		if len(errs) == 0 {
+			ports = append(ports, driverPort{name: reserved.name, number: port, protocol: common.DefaultPortProtocol, path: settingPath(setting, reserved.key)})
+		}
+	}
+	// The executors listen on spark.blockManager.port even when the driver overrides it
+	if setting := config.Executor.Ports[resolver.BlockManagerPortName]; setting.Field != config.Driver.Ports[resolver.BlockManagerPortName].Field {
+		_, errs := parsePortSetting(setting, resolver.SparkBlockManagerPortKey)
+		allErrs = append(allErrs, errs...)
+	}
+	if common.ExposesDriverMetrics(app) {
+		prometheusPath := specPath.Child("monitoring", "prometheus", "port")
+		prometheusPort := common.GetPrometheusPort(app)
+		if errs := validatePortNumber(prometheusPath, prometheusPort); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+		} else {
+			ports = append(ports, driverPort{name: common.GetPrometheusPortName(app), number: prometheusPort, protocol: common.DefaultPortProtocol, path: prometheusPath})
+		}
+	}
+
+	for i, port := range app.Spec.Driver.Ports {
+		portPath := specPath.Child("driver", "ports").Index(i)
+		valid := true
+		for _, msg := range k8svalidation.IsValidPortName(port.Name) {
+			allErrs = append(allErrs, field.Invalid(portPath.Child("name"), port.Name, msg))
+			valid = false
+		}
+		if errs := validatePortNumber(portPath.Child("containerPort"), port.ContainerPort); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+			valid = false
+		}
+		protocol := common.GetPortProtocol(port)
+		switch protocol {
+		case "TCP", "UDP", "SCTP":
+		default:
+			allErrs = append(allErrs, field.NotSupported(portPath.Child("protocol"), port.Protocol, []string{"TCP", "UDP", "SCTP"}))
+			valid = false
+		}
+		if valid {
+			ports = append(ports, driverPort{name: port.Name, number: port.ContainerPort, protocol: protocol, path: portPath})
+		}
+	}
+	allErrs = append(allErrs, validatePortCollisions(ports)...)
+
+	if uiOptions := app.Spec.SparkUIOptions; uiOptions != nil && uiOptions.ServicePort != nil {
+		allErrs = append(allErrs, validatePortNumber(specPath.Child("sparkUIOptions", "servicePort"), *uiOptions.ServicePort)...)
+	}
+	for i, ingressOptions := range app.Spec.DriverIngressOptions {
+		servicePortPath := specPath.Child("driverIngressOptions").Index(i).Child("servicePort")
+		if ingressOptions.ServicePort == nil {
+			allErrs = append(allErrs, field.Required(servicePortPath, "the service port is required"))
+			continue
+		}
+		allErrs = append(allErrs, validatePortNumber(servicePortPath, *ingressOptions.ServicePort)...)
+	}
+	return allErrs
+}
+
+// parsePortSetting parses a resolved port, reporting it against the field it is read from
+func parsePortSetting(setting resolver.Setting, defaultKey string) (int32, field.ErrorList) {
+	port, err := strconv.ParseInt(strings.TrimSpace(setting.Value), 10, 32)
+	if err != nil {
+		return 0, field.ErrorList{field.Invalid(settingPath(setting, defaultKey), setting.Value, "must be a port number")}
+	}
+	return int32(port), validatePortNumber(settingPath(setting, defaultKey), int32(port))
+}
+
+// settingPath returns the path of the sparkConf or spark-defaults.conf key a setting is read from, the sparkConf key
+// setting it for defaults
+func settingPath(setting resolver.Setting, defaultKey string) *field.Path {
+	if setting.Field == "" {
+		return field.NewPath("spec", "sparkConf").Key(defaultKey)
+	}
+	return field.NewPath(setting.Field)
+}
+
+func validatePortNumber(path *field.Path, port int32) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range k8svalidation.IsValidPortNum(int(port)) {
+		allErrs = append(allErrs, field.Invalid(path, port, msg))
+	}
+	return allErrs
+}
+
+// validatePortCollisions reports every port whose name, or number and protocol, is taken by an earlier port
+func validatePortCollisions(ports []driverPort) field.ErrorList {
+	var allErrs field.ErrorList
+	usedNames := make(map[string]driverPort)
+	usedNumbers := make(map[string]driverPort)
+	for _, port := range ports {
+		if existing, ok := usedNames[port.name]; ok {
+			allErrs = append(allErrs, field.Invalid(port.path, port.name, fmt.Sprintf("port name is already used by %s", existing.path)))
+			continue
+		}
+		portKey := fmt.Sprintf("%d/%s", port.number, port.protocol)
+		if existing, ok := usedNumbers[portKey]; ok {
+			allErrs = append(allErrs, field.Invalid(port.path, port.number, fmt.Sprintf("port %s conflicts with port %s of %s", portKey, existing.name, existing.path)))
+			continue
+		}
+		usedNames[port.name] = port
+		usedNumbers[portKey] = port
+	}
+	return allErrs
+}
@@ -0,0 +1,395 @@
+package validation
+
+import (
+	"fmt"
+	"log"
+	"nativesubmit/common"
+	"nativesubmit/internal/ingress"
+	"nativesubmit/internal/memory"
+	"nativesubmit/internal/networkpolicy"
+	"nativesubmit/internal/pdb"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/scheduler"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Helper func to validate the converted Spark Application and its resolved configuration before anything is created.
+// Every problem is reported with the path of the offending field, instead of failing later with a panic or a silently
+// ignored value.
+func ValidateSparkApplication(app *v1beta2.SparkApplication, config resolver.Config) field.ErrorList {
+	if app == nil {
+		log.Printf("ERROR: Spark application is nil")
+		return field.ErrorList{field.Required(field.NewPath("sparkApplication"), "spark application cannot be nil")}
+	}
+	log.Printf("=== Starting validation for app: %s, namespace: %s ===", app.Name, app.Namespace)
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateNames(app)...)
+	allErrs = append(allErrs, validateMainApplication(app)...)
+	allErrs = append(allErrs, validateResources(app)...)
+	allErrs = append(allErrs, ValidatePorts(app, config)...)
+	allErrs = append(allErrs, validateDynamicAllocation(app)...)
+	allErrs = append(allErrs, validateServerLocalFiles(app)...)
+	allErrs = append(allErrs, validateFetchTimeout(app)...)
+	allErrs = append(allErrs, validateDriverProbes(app)...)
+
+	if len(allErrs) > 0 {
+		log.Printf("Validation found %d problems: %v", len(allErrs), allErrs.ToAggregate())
+		return allErrs
+	}
+	log.Printf("=== Successfully completed validation ===")
+	return nil
+}
+
+// validateNames checks the names of the objects created for the application, as suffixed by the submission
+func validateNames(app *v1beta2.SparkApplication) field.ErrorList {
+	var allErrs field.ErrorList
+	namePath := field.NewPath("metadata", "name")
+	if app.Name == "" {
+		return append(allErrs, field.Required(namePath, "the application name is required"))
+	}
+	// The application name is also the value of the app-name label of every object
+	for _, msg := range k8svalidation.IsValidLabelValue(app.Name) {
+		allErrs = append(allErrs, field.Invalid(namePath, app.Name, msg))
+	}
+
+	driverPodName := common.GetDriverPodName(app)
+	driverPodNamePath := namePath
+	if app.Spec.Driver.PodName != nil && len(*app.Spec.Driver.PodName) > 0 {
+		driverPodNamePath = field.NewPath("spec", "driver", "podName")
+	} else if app.Spec.SparkConf[common.SparkDriverPodNameKey] != "" {
+		driverPodNamePath = field.NewPath("spec", "sparkConf").Key(common.SparkDriverPodNameKey)
+	}
+	allErrs = append(allErrs, validateSubdomain(driverPodNamePath, driverPodName)...)
+	allErrs = append(allErrs, validateSubdomain(driverPodNamePath, driverPodName+ConfigMapNameExtension)...)
+	// Longer service names are replaced by a generated one
+	if serviceName := driverPodName + ServiceNameExtension; len(serviceName) <= k8svalidation.DNS1035LabelMaxLength {
+		allErrs = append(allErrs, validateLabel(driverPodNamePath, serviceName)...)
+	}
+
+	pdbEnabled, errs := validateToggle(app, pdb.DriverPDBEnabledAnnotation, pdb.DriverPDBEnabledProperty)
+	allErrs = append(allErrs, errs...)
+	if pdbEnabled {
+		allErrs = append(allErrs, validateSubdomain(driverPodNamePath, pdb.GetName(app))...)
+	}
+	networkPolicyEnabled, errs := validateToggle(app, networkpolicy.NetworkPolicyEnabledAnnotation, networkpolicy.NetworkPolicyEnabledProperty)
+	allErrs = append(allErrs, errs...)
+	if networkPolicyEnabled {
+		allErrs = append(allErrs, validateSubdomain(driverPodNamePath, networkpolicy.GetName(app))...)
+	}
+
+	if app.Spec.SparkUIOptions != nil {
+		allErrs = append(allErrs, validateLabel(namePath, ingress.GetSparkUIServiceName(app))...)
+		allErrs = append(allErrs, validateSubdomain(namePath, ingress.GetSparkUIIngressName(app))...)
+	}
+	for _, ingressOptions := range app.Spec.DriverIngressOptions {
+		if ingressOptions.ServicePort != nil {
+			allErrs = append(allErrs, validateLabel(namePath, ingress.GetDriverIngressServiceName(app, *ingressOptions.ServicePort))...)
+			allErrs = append(allErrs, validateSubdomain(namePath, ingress.GetDriverIngressName(app, *ingressOptions.ServicePort))...)
+		}
+	}
+	if scheduler.IsVolcano(app) {
+		allErrs = append(allErrs, validateSubdomain(namePath, scheduler.GetVolcanoPodGroupName(app))...)
+	}
+	return allErrs
+}
+
+// validateSubdomain checks a derived object name is a DNS-1123 subdomain, reporting it against the field it comes from
+func validateSubdomain(path *field.Path, name string) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range k8svalidation.IsDNS1123Subdomain(name) {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	return allErrs
+}
+
+// validateLabel checks a derived Service name is a DNS-1035 label, reporting it against the field it comes from
+func validateLabel(path *field.Path, name string) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range k8svalidation.IsDNS1035Label(name) {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	return allErrs
+}
+
+// validateToggle checks an annotation or sparkConf toggle is a boolean and reports whether it is enabled
+func validateToggle(app *v1beta2.SparkApplication, annotation string, property string) (bool, field.ErrorList) {
+	enabled, err := common.IsEnabledByAnnotationOrConf(app, annotation, property)
+	if err == nil {
+		return enabled, nil
+	}
+	if value, ok := app.Annotations[annotation]; ok {
+		return false, field.ErrorList{field.Invalid(field.NewPath("metadata", "annotations").Key(annotation), value, "must be a boolean")}
+	}
+	return false, field.ErrorList{field.Invalid(field.NewPath("spec", "sparkConf").Key(property), app.Spec.SparkConf[property], "must be a boolean")}
+}
+
+// validateMainApplication checks the main application file and class required by the application type
+func validateMainApplication(app *v1beta2.SparkApplication) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	mainFilePath := specPath.Child("mainApplicationFile")
+	var mainFile string
+	if app.Spec.MainApplicationFile != nil {
+		mainFile = strings.TrimSpace(*app.Spec.MainApplicationFile)
+	}
+	if mainFile == "" {
+		allErrs = append(allErrs, field.Required(mainFilePath, "the main application file is required"))
+	}
+
+	switch app.Spec.Type {
+	case v1beta2.SparkApplicationTypeJava, v1beta2.SparkApplicationTypeScala:
+		if app.Spec.MainClass == nil || strings.TrimSpace(*app.Spec.MainClass) == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("mainClass"), fmt.Sprintf("the main class is required for %s applications", app.Spec.Type)))
+		}
+	case v1beta2.SparkApplicationTypePython:
+		if mainFile != "" && filepath.Ext(mainFile) != PythonMainApplicationFileExtension {
+			allErrs = append(allErrs, field.Invalid(mainFilePath, mainFile, fmt.Sprintf("must be a %s file for Python applications", PythonMainApplicationFileExtension)))
+		}
+	case v1beta2.SparkApplicationTypeR:
+		if mainFile != "" && !strings.EqualFold(filepath.Ext(mainFile), RMainApplicationFileExtension) {
+			allErrs = append(allErrs, field.Invalid(mainFilePath, mainFile, fmt.Sprintf("must be a %s file for R applications", RMainApplicationFileExtension)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("type"), app.Spec.Type, []string{
+			string(v1beta2.SparkApplicationTypeJava),
+			string(v1beta2.SparkApplicationTypeScala),
+			string(v1beta2.SparkApplicationTypePython),
+			string(v1beta2.SparkApplicationTypeR),
+		}))
+	}
+	return allErrs
+}
+
+// validateResources checks the cores, memory, memory overhead and executor instances of the spec and sparkConf parse
+func validateResources(app *v1beta2.SparkApplication) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	sparkConfPath := specPath.Child("sparkConf")
+
+	allErrs = append(allErrs, validatePodResources(app.Spec.Driver.SparkPodSpec, app.Spec.Driver.CoreRequest, specPath.Child("driver"))...)
+	allErrs = append(allErrs, validatePodResources(app.Spec.Executor.SparkPodSpec, app.Spec.Executor.CoreRequest, specPath.Child("executor"))...)
+	if app.Spec.Executor.Instances != nil && *app.Spec.Executor.Instances < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("executor", "instances"), *app.Spec.Executor.Instances, "must be greater than or equal to 0"))
+	}
+	if app.Spec.MemoryOverheadFactor != nil {
+		allErrs = append(allErrs, validateFactor(specPath.Child("memoryOverheadFactor"), *app.Spec.MemoryOverheadFactor)...)
+	}
+
+	for _, key := range []string{SparkDriverCores, common.SparkDriverCoreRequestKey, common.SparkDriverCoreLimitKey,
+		SparkExecutorCores, SparkExecutorCoreRequestKey, SparkExecutorCoreLimitKey} {
+		if value, ok := app.Spec.SparkConf[key]; ok {
+			allErrs = append(allErrs, validateQuantity(sparkConfPath.Key(key), value)...)
+		}
+	}
+	for _, key := range []string{SparkDriverMemory, SparkExecutorMemory, SparkDriverMemoryOverhead,
+		SparkExecutorMemoryOverhead, common.SparkMemoryOverheadKey, resolver.SparkPySparkExecutorMemoryKey} {
+		if value, ok := app.Spec.SparkConf[key]; ok {
+			allErrs = append(allErrs, validateMemory(sparkConfPath.Key(key), value)...)
+		}
+	}
+	for _, key := range []string{SparkDriverMemoryOverheadFactor, SparkExecutorMemoryOverheadFactor,
+		resolver.SparkMemoryOverheadFactorKey} {
+		if value, ok := app.Spec.SparkConf[key]; ok {
+			allErrs = append(allErrs, validateFactor(sparkConfPath.Key(key), value)...)
+		}
+	}
+	if value, ok := app.Spec.SparkConf[SparkExecutorInstances]; ok {
+		if instances, err := strconv.Atoi(strings.TrimSpace(value)); err != nil || instances < 0 {
+			allErrs = append(allErrs, field.Invalid(sparkConfPath.Key(SparkExecutorInstances), value, "must be a non-negative integer"))
+		}
+	}
+	return allErrs
+}
+
+// validatePodResources checks the driver or executor cores, core request and limit, memory and memory overhead
+func validatePodResources(podSpec v1beta2.SparkPodSpec, coreRequest *string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if podSpec.Cores != nil && *podSpec.Cores <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("cores"), *podSpec.Cores, "must be greater than 0"))
+	}
+	if coreRequest != nil {
+		allErrs = append(allErrs, validateQuantity(path.Child("coreRequest"), *coreRequest)...)
+	}
+	if podSpec.CoreLimit != nil {
+		allErrs = append(allErrs, validateQuantity(path.Child("coreLimit"), *podSpec.CoreLimit)...)
+	}
+	if podSpec.Memory != nil {
+		allErrs = append(allErrs, validateMemory(path.Child("memory"), *podSpec.Memory)...)
+	}
+	if podSpec.MemoryOverhead != nil {
+		allErrs = append(allErrs, validateMemory(path.Child("memoryOverhead"), *podSpec.MemoryOverhead)...)
+	}
+	return allErrs
+}
+
+func validateQuantity(path *field.Path, value string) field.ErrorList {
+	quantity, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, "must be a quantity, e.g. 1, 500m or 1.5")}
+	}
+	if quantity.Sign() <= 0 {
+		return field.ErrorList{field.Invalid(path, value, "must be greater than 0")}
+	}
+	return nil
+}
+
+func validateMemory(path *field.Path, value string) field.ErrorList {
+	if _, err := memory.ToMiB(value); err != nil {
+		return field.ErrorList{field.Invalid(path, value, "must be a whole JVM memory string with an optional b, k, m, g, t or p unit, e.g. 512m or 2g")}
+	}
+	return nil
+}
+
+func validateFactor(path *field.Path, value string) field.ErrorList {
+	factor, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || factor <= 0 {
+		return field.ErrorList{field.Invalid(path, value, "must be a positive number")}
+	}
+	return nil
+}
+
+// validateDynamicAllocation checks min <= initial <= max executors. The settings are written to the properties file
+// whenever dynamic allocation is set, so they are checked even when it is not enabled.
+func validateDynamicAllocation(app *v1beta2.SparkApplication) field.ErrorList {
+	dynamicAllocation := app.Spec.DynamicAllocation
+	if dynamicAllocation == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	path := field.NewPath("spec", "dynamicAllocation")
+	initialPath, minPath, maxPath := path.Child("initialExecutors"), path.Child("minExecutors"), path.Child("maxExecutors")
+
+	for _, executors := range []struct {
+		path  *field.Path
+		value *int32
+	}{{initialPath, dynamicAllocation.InitialExecutors}, {minPath, dynamicAllocation.MinExecutors}, {maxPath, dynamicAllocation.MaxExecutors}} {
+		if executors.value != nil && *executors.value < 0 {
+			allErrs = append(allErrs, field.Invalid(executors.path, *executors.value, "must be greater than or equal to 0"))
+		}
+	}
+
+	initial, min, max := dynamicAllocation.InitialExecutors, dynamicAllocation.MinExecutors, dynamicAllocation.MaxExecutors
+	if min != nil && max != nil && *min > *max {
+		allErrs = append(allErrs, field.Invalid(maxPath, *max, fmt.Sprintf("must be greater than or equal to minExecutors %d", *min)))
+	}
+	if initial != nil && min != nil && *initial < *min {
+		allErrs = append(allErrs, field.Invalid(initialPath, *initial, fmt.Sprintf("must be greater than or equal to minExecutors %d", *min)))
+	}
+	if initial != nil && max != nil && *initial > *max {
+		allErrs = append(allErrs, field.Invalid(initialPath, *initial, fmt.Sprintf("must be less than or equal to maxExecutors %d", *max)))
+	}
+	return allErrs
+}
+
+// validateServerLocalFiles rejects the sparkConf properties that would make the submission server read its own files
+func validateServerLocalFiles(app *v1beta2.SparkApplication) field.ErrorList {
+	var allErrs field.ErrorList
+	sparkConfPath := field.NewPath("spec", "sparkConf")
+	for _, file := range common.GetServerLocalFiles(app.Spec.SparkConf) {
+		allErrs = append(allErrs, field.Forbidden(sparkConfPath.Key(file.ConfKey), fmt.Sprintf("files of the submission server cannot be read, use %s instead", file.Alternative)))
+	}
+	return allErrs
+}
+
+// validateFetchTimeout checks spark.files.fetchTimeout, which bounds the download of a remote pod template
+func validateFetchTimeout(app *v1beta2.SparkApplication) field.ErrorList {
+	fetchTimeout, ok := app.Spec.SparkConf[SparkFilesFetchTimeout]
+	if !ok {
+		return nil
+	}
+	path := field.NewPath("spec", "sparkConf").Key(SparkFilesFetchTimeout)
+	duration, err := common.ParseTimeString(fetchTimeout, time.Second)
+	if err != nil || duration <= 0 {
+		return field.ErrorList{field.Invalid(path, fetchTimeout, "must be a positive Spark time string, in seconds when it has no unit, e.g. 60 or 2min")}
+	}
+	return nil
+}
+
+// validateDriverProbes checks the probes the driver container takes from spec.driver.template, as the API server would
+// only reject them once the driver pod is created
+func validateDriverProbes(app *v1beta2.SparkApplication) field.ErrorList {
+	if app.Spec.Driver.Template == nil || len(app.Spec.Driver.Template.Spec.Containers) == 0 {
+		return nil
+	}
+	containersPath := field.NewPath("spec", "driver", "template", "spec", "containers")
+	index, container := common.GetDriverTemplateContainer(app)
+	if container == nil {
+		return field.ErrorList{field.Required(containersPath, fmt.Sprintf("must have a container named %s", common.SparkDriverContainerName))}
+	}
+
+	var allErrs field.ErrorList
+	containerPath := containersPath.Index(index)
+	allErrs = append(allErrs, validateProbe(containerPath.Child("readinessProbe"), container.ReadinessProbe, false)...)
+	allErrs = append(allErrs, validateProbe(containerPath.Child("livenessProbe"), container.LivenessProbe, true)...)
+	allErrs = append(allErrs, validateProbe(containerPath.Child("startupProbe"), container.StartupProbe, true)...)
+	return allErrs
+}
+
+// validateProbe checks a probe has exactly one handler, a valid port and no negative settings. Liveness and startup
+// probes must succeed once, as the API server requires.
+func validateProbe(path *field.Path, probe *apiv1.Probe, singleSuccess bool) field.ErrorList {
+	if probe == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	handlers := 0
+	if probe.Exec != nil {
+		handlers++
+	}
+	if probe.HTTPGet != nil {
+		handlers++
+		allErrs = append(allErrs, validateProbePort(path.Child("httpGet", "port"), probe.HTTPGet.Port)...)
+	}
+	if probe.TCPSocket != nil {
+		handlers++
+		allErrs = append(allErrs, validateProbePort(path.Child("tcpSocket", "port"), probe.TCPSocket.Port)...)
+	}
+	if probe.GRPC != nil {
+		handlers++
+		allErrs = append(allErrs, validatePortNumber(path.Child("grpc", "port"), probe.GRPC.Port)...)
+	}
+	if handlers != 1 {
+		allErrs = append(allErrs, field.Invalid(path, handlers, "must have exactly one of exec, httpGet, tcpSocket or grpc"))
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value int32
+	}{
+		{"initialDelaySeconds", probe.InitialDelaySeconds},
+		{"timeoutSeconds", probe.TimeoutSeconds},
+		{"periodSeconds", probe.PeriodSeconds},
+		{"successThreshold", probe.SuccessThreshold},
+		{"failureThreshold", probe.FailureThreshold},
+	} {
+		if setting.value < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child(setting.name), setting.value, "must be greater than or equal to 0"))
+		}
+	}
+	if singleSuccess && probe.SuccessThreshold > 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("successThreshold"), probe.SuccessThreshold, "must be 1"))
+	}
+	return allErrs
+}
+
+// validateProbePort checks a probe port is a valid port number or port name
+func validateProbePort(path *field.Path, port intstr.IntOrString) field.ErrorList {
+	if port.Type == intstr.Int {
+		return validatePortNumber(path, port.IntVal)
+	}
+	if msgs := k8svalidation.IsValidPortName(port.StrVal); len(msgs) > 0 {
+		return field.ErrorList{field.Invalid(path, port.StrVal, strings.Join(msgs, "; "))}
+	}
+	return nil
+}
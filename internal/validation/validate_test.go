@@ -0,0 +1,311 @@
+package validation
+
+import (
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/testutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestValidateSparkApplication(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(app *v1beta2.SparkApplication)
+		wantFields []string
+	}{
+		{name: "valid application"},
+		{
+			name:       "name not DNS-1123 compliant",
+			modify:     func(app *v1beta2.SparkApplication) { app.Name = "Test_App" },
+			wantFields: []string{"metadata.name", "metadata.name", "metadata.name"},
+		},
+		{
+			name:       "driver pod name too long once suffixed",
+			modify:     func(app *v1beta2.SparkApplication) { app.Spec.Driver.PodName = stringPtr(strings.Repeat("a", 250)) },
+			wantFields: []string{"spec.driver.podName"},
+		},
+		{
+			name: "service name must start with a letter",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{"spark.kubernetes.driver.pod.name": "1-driver"}
+			},
+			wantFields: []string{"spec.sparkConf[spark.kubernetes.driver.pod.name]"},
+		},
+		{
+			name: "invalid toggle",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Annotations = map[string]string{"sparkoperator.k8s.io/driver-pdb-enabled": "yes"}
+			},
+			wantFields: []string{"metadata.annotations[sparkoperator.k8s.io/driver-pdb-enabled]"},
+		},
+		{
+			name:       "missing main class and file",
+			modify:     func(app *v1beta2.SparkApplication) { app.Spec.MainClass, app.Spec.MainApplicationFile = nil, nil },
+			wantFields: []string{"spec.mainApplicationFile", "spec.mainClass"},
+		},
+		{
+			name: "python main file",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypePython
+				app.Spec.MainClass = nil
+			},
+			wantFields: []string{"spec.mainApplicationFile"},
+		},
+		{
+			name: "R main file",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypeR
+				app.Spec.MainApplicationFile = stringPtr("local:///opt/app/main.r")
+			},
+		},
+		{
+			name:       "unsupported type",
+			modify:     func(app *v1beta2.SparkApplication) { app.Spec.Type = "Go" },
+			wantFields: []string{"spec.type"},
+		},
+		{
+			name: "unparseable resources",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.CoreLimit = stringPtr("two")
+				app.Spec.Driver.Memory = stringPtr("1.5g")
+				app.Spec.Executor.Cores = int32Ptr(0)
+				app.Spec.Executor.CoreRequest = stringPtr("-1")
+				app.Spec.SparkConf = map[string]string{"spark.executor.memory": "lots", "spark.executor.instances": "-2"}
+				app.Spec.MemoryOverheadFactor = stringPtr("0.1x")
+			},
+			wantFields: []string{
+				"spec.driver.coreLimit", "spec.driver.memory", "spec.executor.cores", "spec.executor.coreRequest",
+				"spec.memoryOverheadFactor", "spec.sparkConf[spark.executor.memory]", "spec.sparkConf[spark.executor.instances]",
+			},
+		},
+		{
+			name: "unparseable port",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{"spark.driver.port": "70000"}
+			},
+			wantFields: []string{"spec.sparkConf[spark.driver.port]"},
+		},
+		{
+			name: "port collisions",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{"spark.blockManager.port": "7078"}
+				app.Spec.Driver.Ports = []v1beta2.Port{
+					{Name: "spark-ui", ContainerPort: 8080},
+					{Name: "metrics", ContainerPort: 4040},
+					{Name: "dns", ContainerPort: 4040, Protocol: "UDP"},
+				}
+			},
+			wantFields: []string{"spec.sparkConf[spark.blockManager.port]", "spec.driver.ports[0]", "spec.driver.ports[1]"},
+		},
+		{
+			name: "driver ingress options without service port",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.DriverIngressOptions = []v1beta2.DriverIngressConfiguration{{IngressURLFormat: "{{$appName}}.example.com"}}
+			},
+			wantFields: []string{"spec.driverIngressOptions[0].servicePort"},
+		},
+		{
+			name: "files of the submission server",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{
+					"spark.kubernetes.authenticate.driver.oauthTokenFile": "/var/run/secrets/kubernetes.io/serviceaccount/token",
+					"spark.kubernetes.authenticate.driver.clientKeyFile":  "/etc/pki/client.key",
+					"spark.kubernetes.kerberos.krb5.path":                 "/etc/krb5.conf",
+					"spark.kerberos.keytab":                               "/etc/krb5.keytab",
+					"spark.kerberos.principal":                            "user@EXAMPLE.COM",
+				}
+			},
+			wantFields: []string{
+				"spec.sparkConf[spark.kubernetes.kerberos.krb5.path]", "spec.sparkConf[spark.kerberos.keytab]",
+				"spec.sparkConf[spark.kubernetes.authenticate.driver.oauthTokenFile]", "spec.sparkConf[spark.kubernetes.authenticate.driver.clientKeyFile]",
+			},
+		},
+		{
+			name: "pod template fetch timeout",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{"spark.files.fetchTimeout": "-5s"}
+			},
+			wantFields: []string{"spec.sparkConf[spark.files.fetchTimeout]"},
+		},
+		{
+			name: "pod template fetch timeout in seconds",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{"spark.files.fetchTimeout": "120"}
+			},
+		},
+		{
+			name: "driver probes from the template",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Template = &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Containers: []apiv1.Container{{
+					Name: "spark-kubernetes-driver",
+					ReadinessProbe: &apiv1.Probe{
+						ProbeHandler:  apiv1.ProbeHandler{TCPSocket: &apiv1.TCPSocketAction{Port: intstr.FromString("driver-rpc-port")}},
+						PeriodSeconds: -10,
+					},
+					LivenessProbe: &apiv1.Probe{
+						ProbeHandler:     apiv1.ProbeHandler{HTTPGet: &apiv1.HTTPGetAction{Port: intstr.FromInt32(70000)}},
+						SuccessThreshold: 2,
+					},
+					StartupProbe: &apiv1.Probe{},
+				}}}}
+			},
+			wantFields: []string{
+				"spec.driver.template.spec.containers[0].readinessProbe.periodSeconds",
+				"spec.driver.template.spec.containers[0].livenessProbe.httpGet.port",
+				"spec.driver.template.spec.containers[0].livenessProbe.successThreshold",
+				"spec.driver.template.spec.containers[0].startupProbe",
+			},
+		},
+		{
+			name: "template without a driver container",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Template = &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Containers: []apiv1.Container{{Name: "a"}, {Name: "b"}}}}
+			},
+			wantFields: []string{"spec.driver.template.spec.containers"},
+		},
+		{
+			name: "dynamic allocation min <= initial <= max",
+			modify: func(app *v1beta2.SparkApplication) {
+				app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true, InitialExecutors: int32Ptr(1), MinExecutors: int32Ptr(2), MaxExecutors: int32Ptr(0)}
+			},
+			wantFields: []string{"spec.dynamicAllocation.maxExecutors", "spec.dynamicAllocation.initialExecutors", "spec.dynamicAllocation.initialExecutors"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+				Type:                v1beta2.SparkApplicationTypeScala,
+				MainClass:           stringPtr("org.apache.spark.examples.SparkPi"),
+				MainApplicationFile: stringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+			})
+			if tt.modify != nil {
+				tt.modify(app)
+			}
+			errs := ValidateSparkApplication(app, resolver.Resolve(app))
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if len(tt.wantFields) == 0 {
+				assert.Empty(t, fields, "unexpected errors: %v", errs)
+				return
+			}
+			assert.Equal(t, tt.wantFields, fields, "errors: %v", errs)
+		})
+	}
+}
+
+func TestValidateSparkApplicationMessages(t *testing.T) {
+	app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{
+		Type:                v1beta2.SparkApplicationTypeScala,
+		MainClass:           stringPtr("org.apache.spark.examples.SparkPi"),
+		MainApplicationFile: stringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+	})
+	app.Spec.Driver.Ports = []v1beta2.Port{{Name: "metrics", ContainerPort: 7079}}
+	errs := ValidateSparkApplication(app, resolver.Resolve(app))
+	assert.Equal(t, field.ErrorList{
+		field.Invalid(field.NewPath("spec", "driver", "ports").Index(0), int32(7079), "port 7079/TCP conflicts with port blockmanager of spec.sparkConf[spark.blockManager.port]"),
+	}, errs)
+}
+
+func TestValidateSparkApplicationNil(t *testing.T) {
+	assert.Len(t, ValidateSparkApplication(nil, resolver.Config{}), 1)
+}
+
+func TestValidatePorts(t *testing.T) {
+	tests := []struct {
+		name          string
+		sparkConf     map[string]string
+		sparkDefaults string
+		ports         []v1beta2.Port
+		monitoring    *v1beta2.MonitoringSpec
+		wantErrs      []string
+	}{
+		{name: "valid ports", ports: []v1beta2.Port{{Name: "thrift", ContainerPort: 10000}, {Name: "rest", Protocol: "tcp", ContainerPort: 8080}}},
+		{name: "same number different protocol", ports: []v1beta2.Port{{Name: "rpc-udp", Protocol: "UDP", ContainerPort: 7078}}},
+		{
+			name:      "conflicts with configured driver port",
+			sparkConf: map[string]string{"spark.driver.port": "10000"},
+			ports:     []v1beta2.Port{{Name: "thrift", ContainerPort: 10000}},
+			wantErrs:  []string{"spec.driver.ports[0]: Invalid value: 10000: port 10000/TCP conflicts with port driver-rpc-port of spec.sparkConf[spark.driver.port]"},
+		},
+		{
+			name:      "configured UI port frees the default one",
+			sparkConf: map[string]string{"spark.ui.port": "4041"},
+			ports:     []v1beta2.Port{{Name: "extra", ContainerPort: 4040}},
+		},
+		{
+			name:     "conflicts with UI port name",
+			ports:    []v1beta2.Port{{Name: "spark-ui", ContainerPort: 4041}},
+			wantErrs: []string{"spec.driver.ports[0]: Invalid value: \"spark-ui\": port name is already used by spec.sparkConf[spark.ui.port]"},
+		},
+		{
+			name:          "conflicts with spark-defaults.conf UI port",
+			sparkDefaults: "spark.ui.port=4041\n",
+			ports:         []v1beta2.Port{{Name: "extra", ContainerPort: 4041}},
+			wantErrs:      []string{"spec.driver.ports[0]: Invalid value: 4041: port 4041/TCP conflicts with port spark-ui of spark-defaults.conf[spark.ui.port]"},
+		},
+		{
+			name:          "invalid spark-defaults.conf port",
+			sparkDefaults: "spark.driver.port=70000\n",
+			wantErrs:      []string{"spark-defaults.conf[spark.driver.port]: Invalid value: 70000: must be between 1 and 65535, inclusive"},
+		},
+		{
+			name:      "invalid executor block manager port overridden for the driver",
+			sparkConf: map[string]string{"spark.driver.blockManager.port": "7090", "spark.blockManager.port": "any"},
+			wantErrs:  []string{"spec.sparkConf[spark.blockManager.port]: Invalid value: \"any\": must be a port number"},
+		},
+		{
+			name:  "conflicts with prometheus port",
+			ports: []v1beta2.Port{{Name: "rest", ContainerPort: 8090}},
+			monitoring: &v1beta2.MonitoringSpec{
+				ExposeDriverMetrics: true,
+				Prometheus:          &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx.jar"},
+			},
+			wantErrs: []string{"spec.driver.ports[0]: Invalid value: 8090: port 8090/TCP conflicts with port jmx-exporter of spec.monitoring.prometheus.port"},
+		},
+		{
+			name:     "duplicate user port",
+			ports:    []v1beta2.Port{{Name: "thrift", ContainerPort: 10000}, {Name: "thrift-http", ContainerPort: 10000}},
+			wantErrs: []string{"spec.driver.ports[1]: Invalid value: 10000: port 10000/TCP conflicts with port thrift of spec.driver.ports[0]"},
+		},
+		{
+			name:     "invalid name and protocol",
+			ports:    []v1beta2.Port{{Name: "Thrift_Server", Protocol: "HTTP", ContainerPort: 10000}},
+			wantErrs: []string{"spec.driver.ports[0].name", "spec.driver.ports[0].protocol"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(file string) { resolver.SparkDefaultsFile = file }(resolver.SparkDefaultsFile)
+			resolver.SparkDefaultsFile = filepath.Join(t.TempDir(), "spark-defaults.conf")
+			require.NoError(t, os.WriteFile(resolver.SparkDefaultsFile, []byte(tt.sparkDefaults), 0o644))
+			app := testutil.NewSparkApplication(v1beta2.SparkApplicationSpec{Type: v1beta2.SparkApplicationTypeScala, SparkConf: tt.sparkConf, Monitoring: tt.monitoring})
+			app.Spec.Driver.Ports = tt.ports
+
+			errs := ValidatePorts(app, resolver.Resolve(app))
+
+			require.Len(t, errs, len(tt.wantErrs), "errors: %v", errs)
+			for i, want := range tt.wantErrs {
+				assert.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
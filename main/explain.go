@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"nativesubmit/internal/explain"
+	"nativesubmit/internal/resolver"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// explainCommand runs the explain command instead of the gRPC service
+	explainCommand = "explain"
+	// stdinFileName reads the SparkApplication from the standard input
+	stdinFileName = "-"
+)
+
+// runExplainCommand prints where each effective setting of a SparkApplication manifest comes from, returning the exit
+// code. Usage: explain [-f app.yaml] [-o table|json]
+func runExplainCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(explainCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fileName := flags.String("f", stdinFileName, "SparkApplication manifest, YAML or JSON, - for the standard input")
+	output := flags.String("o", "table", "output format, table or json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "unknown output format %q, must be table or json\n", *output)
+		return 2
+	}
+
+	app, err := readSparkApplication(*fileName, stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	entries, err := explain.Explain(resolver.Resolve(app))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(entries)
+	} else {
+		err = explain.WriteTable(stdout, entries)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// readSparkApplication reads a SparkApplication manifest from the file, or the standard input
+func readSparkApplication(fileName string, stdin io.Reader) (*v1beta2.SparkApplication, error) {
+	var content []byte
+	var err error
+	if fileName == stdinFileName {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+
+	app := &v1beta2.SparkApplication{}
+	if err := yaml.Unmarshal(content, app); err != nil {
+		return nil, fmt.Errorf("invalid SparkApplication in %s: %w", fileName, err)
+	}
+	return app, nil
+}
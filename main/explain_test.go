@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const explainTestManifest = `apiVersion: sparkoperator.k8s.io/v1beta2
+kind: SparkApplication
+metadata:
+  name: pi
+spec:
+  type: Scala
+  image: spark:3.5.1
+  sparkConf:
+    spark.driver.memory: 4g
+  driver:
+    memory: 2g
+`
+
+func TestRunExplainCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runExplainCommand(nil, strings.NewReader(explainTestManifest), &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Regexp(t, `driver\s+memory\s+2g\s+spec\s+spec.driver.memory\n\s+overrides\s+4g\s+sparkConf\s+spec.sparkConf\[spark.driver.memory\]\n`, stdout.String())
+	assert.Regexp(t, `executor\s+containerMemory\s+1408Mi\s+derived\s+1024Mi memory \+ 384Mi overhead\n`, stdout.String())
+
+	stdout.Reset()
+	code = runExplainCommand([]string{"-o", "json"}, strings.NewReader(explainTestManifest), &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &entries))
+	assert.Equal(t, map[string]any{"name": "imagePullPolicy", "value": "IfNotPresent", "source": "default"}, entries[0])
+}
+
+func TestRunExplainCommandErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "unknown output", args: []string{"-o", "yaml"}, wantCode: 2, wantErr: `unknown output format "yaml"`},
+		{name: "unknown flag", args: []string{"-x"}, wantCode: 2, wantErr: "flag provided but not defined: -x"},
+		{name: "missing file", args: []string{"-f", "testdata/missing.yaml"}, wantCode: 1, wantErr: "failed to read testdata/missing.yaml"},
+		{name: "invalid manifest", stdin: "spec: [", wantCode: 1, wantErr: "invalid SparkApplication in -"},
+		{name: "invalid setting", stdin: "spec:\n  executor:\n    memory: lots\n", wantCode: 1, wantErr: "invalid executor settings: spec.executor.memory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, tt.wantCode, runExplainCommand(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr))
+			assert.Contains(t, stderr.String(), tt.wantErr)
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
@@ -8,9 +8,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"nativesubmit/internal/explain"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/validation"
 	pb "nativesubmit/proto/spark"
 
 	"github.com/google/uuid"
@@ -18,14 +23,17 @@ import (
 	"google.golang.org/grpc"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Helper: Convert proto SparkApplication to v1beta2.SparkApplication
-func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.SparkApplication {
+func convertProtoToSparkApplication(protoApp *pb.SparkApplication) (*v1beta2.SparkApplication, error) {
 	if protoApp == nil {
-		return nil
+		return nil, nil
 	}
 
 	// Convert SparkApplicationType enum to string
@@ -65,6 +73,14 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		return nil
 	}
 
+	// Helper function to convert a plain string to a pointer, empty being unset
+	getNonEmptyStringPtr := func(value string) *string {
+		if value != "" {
+			return &value
+		}
+		return nil
+	}
+
 	// Helper function to convert wrapper to int32 pointer
 	getInt32Ptr := func(wrapper *wrapperspb.Int32Value) *int32 {
 		if wrapper != nil {
@@ -98,6 +114,39 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		uid = uuid.New().String()
 	}
 
+	// Helper function to convert proto ServiceType to apiv1.ServiceType, unspecified keeps the default
+	convertServiceType := func(serviceType pb.ServiceType) *apiv1.ServiceType {
+		var converted apiv1.ServiceType
+		switch serviceType {
+		case pb.ServiceType_SERVICE_TYPE_CLUSTER_IP:
+			converted = apiv1.ServiceTypeClusterIP
+		case pb.ServiceType_SERVICE_TYPE_NODE_PORT:
+			converted = apiv1.ServiceTypeNodePort
+		case pb.ServiceType_SERVICE_TYPE_LOAD_BALANCER:
+			converted = apiv1.ServiceTypeLoadBalancer
+		case pb.ServiceType_SERVICE_TYPE_EXTERNAL_NAME:
+			converted = apiv1.ServiceTypeExternalName
+		default:
+			return nil
+		}
+		return &converted
+	}
+
+	// Helper function to convert proto IngressTLS to networkingv1.IngressTLS
+	convertIngressTLS := func(protoTLS []*pb.IngressTLS) []networkingv1.IngressTLS {
+		var ingressTLS []networkingv1.IngressTLS
+		for _, tls := range protoTLS {
+			if tls == nil {
+				continue
+			}
+			ingressTLS = append(ingressTLS, networkingv1.IngressTLS{
+				Hosts:      tls.GetHosts(),
+				SecretName: tls.GetSecretName(),
+			})
+		}
+		return ingressTLS
+	}
+
 	// Helper function to convert proto Volume to apiv1.Volume
 	convertVolume := func(protoVol *pb.Volume) apiv1.Volume {
 		if protoVol == nil {
@@ -134,6 +183,114 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		}
 	}
 
+	// Helper function to convert proto IntOrString to intstr.IntOrString
+	convertIntOrString := func(protoIntOrString *pb.IntOrString) intstr.IntOrString {
+		if _, isString := protoIntOrString.GetType().(*pb.IntOrString_StrVal); isString {
+			return intstr.FromString(protoIntOrString.GetStrVal())
+		}
+		return intstr.FromInt32(protoIntOrString.GetIntVal())
+	}
+
+	// Helper function to convert proto ExecAction, HTTPGetAction and TCPSocketAction to their apiv1 equivalents
+	convertExecAction := func(protoExec *pb.ExecAction) *apiv1.ExecAction {
+		if protoExec == nil {
+			return nil
+		}
+		return &apiv1.ExecAction{Command: protoExec.GetCommand()}
+	}
+	convertHTTPGetAction := func(protoHTTPGet *pb.HTTPGetAction) *apiv1.HTTPGetAction {
+		if protoHTTPGet == nil {
+			return nil
+		}
+		httpGet := &apiv1.HTTPGetAction{
+			Path: protoHTTPGet.GetPath(),
+			Port: convertIntOrString(protoHTTPGet.GetPort()),
+			Host: protoHTTPGet.GetHost(),
+		}
+		switch protoHTTPGet.GetScheme() {
+		case pb.URIScheme_URISCHEME_HTTP:
+			httpGet.Scheme = apiv1.URISchemeHTTP
+		case pb.URIScheme_URISCHEME_HTTPS:
+			httpGet.Scheme = apiv1.URISchemeHTTPS
+		}
+		for _, header := range protoHTTPGet.GetHttpHeaders() {
+			httpGet.HTTPHeaders = append(httpGet.HTTPHeaders, apiv1.HTTPHeader{Name: header.GetName(), Value: header.GetValue()})
+		}
+		return httpGet
+	}
+	convertTCPSocketAction := func(protoTCPSocket *pb.TCPSocketAction) *apiv1.TCPSocketAction {
+		if protoTCPSocket == nil {
+			return nil
+		}
+		return &apiv1.TCPSocketAction{
+			Port: convertIntOrString(protoTCPSocket.GetPort()),
+			Host: protoTCPSocket.GetHost(),
+		}
+	}
+
+	// Helper function to convert proto Lifecycle to apiv1.Lifecycle
+	convertLifecycleHandler := func(protoHandler *pb.LifecycleHandler) *apiv1.LifecycleHandler {
+		if protoHandler == nil {
+			return nil
+		}
+		handler := &apiv1.LifecycleHandler{
+			Exec:      convertExecAction(protoHandler.GetExec()),
+			HTTPGet:   convertHTTPGetAction(protoHandler.GetHttpGet()),
+			TCPSocket: convertTCPSocketAction(protoHandler.GetTcpSocket()),
+		}
+		if protoHandler.GetSleep() != nil {
+			handler.Sleep = &apiv1.SleepAction{Seconds: protoHandler.GetSleep().GetSeconds()}
+		}
+		return handler
+	}
+	convertLifecycle := func(protoLifecycle *pb.Lifecycle) *apiv1.Lifecycle {
+		if protoLifecycle == nil {
+			return nil
+		}
+		return &apiv1.Lifecycle{
+			PostStart: convertLifecycleHandler(protoLifecycle.GetPostStart()),
+			PreStop:   convertLifecycleHandler(protoLifecycle.GetPreStop()),
+		}
+	}
+
+	// Helper function to convert proto EnvFromSource to apiv1.EnvFromSource
+	convertEnvFromSource := func(protoEnvFrom *pb.EnvFromSource) apiv1.EnvFromSource {
+		envFrom := apiv1.EnvFromSource{Prefix: protoEnvFrom.GetPrefix()}
+		if configMapRef := protoEnvFrom.GetConfigMapRef(); configMapRef != nil {
+			envFrom.ConfigMapRef = &apiv1.ConfigMapEnvSource{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: configMapRef.GetLocalObjectReference().GetName()},
+				Optional:             getBoolPtr(configMapRef.GetOptional()),
+			}
+		}
+		if secretRef := protoEnvFrom.GetSecretRef(); secretRef != nil {
+			envFrom.SecretRef = &apiv1.SecretEnvSource{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: secretRef.GetLocalObjectReference().GetName()},
+				Optional:             getBoolPtr(secretRef.GetOptional()),
+			}
+		}
+		return envFrom
+	}
+
+	// Helper function to convert proto Probe to apiv1.Probe
+	convertProbe := func(protoProbe *pb.Probe) *apiv1.Probe {
+		if protoProbe == nil {
+			return nil
+		}
+		return &apiv1.Probe{
+			ProbeHandler: apiv1.ProbeHandler{
+				Exec:      convertExecAction(protoProbe.GetProbeHandler().GetExec()),
+				HTTPGet:   convertHTTPGetAction(protoProbe.GetProbeHandler().GetHttpGet()),
+				TCPSocket: convertTCPSocketAction(protoProbe.GetProbeHandler().GetTcpSocket()),
+			},
+			InitialDelaySeconds:           protoProbe.GetInitialDelaySeconds(),
+			TimeoutSeconds:                protoProbe.GetTimeoutSeconds(),
+			PeriodSeconds:                 protoProbe.GetPeriodSeconds(),
+			SuccessThreshold:              protoProbe.GetSuccessThreshold(),
+			FailureThreshold:              protoProbe.GetFailureThreshold(),
+			TerminationGracePeriodSeconds: getInt64Ptr(protoProbe.GetTerminationGracePeriodSeconds()),
+		}
+	}
+
 	// Helper function to convert proto Container to apiv1.Container
 	convertContainer := func(protoContainer *pb.Container) apiv1.Container {
 		if protoContainer == nil {
@@ -158,11 +315,21 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 			container.Env = append(container.Env, convertEnvVar(envVar))
 		}
 
+		for _, envFrom := range protoContainer.GetEnvFrom() {
+			container.EnvFrom = append(container.EnvFrom, convertEnvFromSource(envFrom))
+		}
+
 		// Convert volume mounts
 		for _, volMount := range protoContainer.GetVolumeMounts() {
 			container.VolumeMounts = append(container.VolumeMounts, convertVolumeMount(volMount))
 		}
 
+		// Convert probes and lifecycle hooks
+		container.LivenessProbe = convertProbe(protoContainer.GetLivenessProbe())
+		container.ReadinessProbe = convertProbe(protoContainer.GetReadinessProbe())
+		container.StartupProbe = convertProbe(protoContainer.GetStartupProbe())
+		container.Lifecycle = convertLifecycle(protoContainer.GetLifeCycle())
+
 		return container
 	}
 
@@ -174,6 +341,10 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 
 		podSpec := v1beta2.SparkPodSpec{
 			Cores:                 getInt32Ptr(protoPodSpec.GetCores()),
+			CoreLimit:             getNonEmptyStringPtr(protoPodSpec.GetCoreLimit()),
+			Memory:                getNonEmptyStringPtr(protoPodSpec.GetMemory()),
+			MemoryOverhead:        getNonEmptyStringPtr(protoPodSpec.GetMemoryOverhead()),
+			Image:                 getNonEmptyStringPtr(protoPodSpec.GetImage()),
 			Labels:                protoPodSpec.GetLabels(),
 			Annotations:           protoPodSpec.GetAnnotations(),
 			NodeSelector:          protoPodSpec.GetNodeSelector(),
@@ -188,6 +359,15 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 			podSpec.Env = append(podSpec.Env, convertEnvVar(envVar))
 		}
 
+		for _, envFrom := range protoPodSpec.GetEnvFrom() {
+			podSpec.EnvFrom = append(podSpec.EnvFrom, convertEnvFromSource(envFrom))
+		}
+
+		// Convert configmaps to be mounted
+		for _, configMap := range protoPodSpec.GetConfigmaps() {
+			podSpec.ConfigMaps = append(podSpec.ConfigMaps, v1beta2.NamePath{Name: configMap.GetName(), Path: configMap.GetPath()})
+		}
+
 		// Convert volume mounts
 		for _, volMount := range protoPodSpec.GetVolumeMounts() {
 			podSpec.VolumeMounts = append(podSpec.VolumeMounts, convertVolumeMount(volMount))
@@ -202,21 +382,32 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 			podSpec.InitContainers = append(podSpec.InitContainers, convertContainer(container))
 		}
 
+		// Convert the containers of the pod template, the driver container takes its probes from it
+		if templateContainers := protoPodSpec.GetTemplate().GetPodSpec().GetContainers(); len(templateContainers) > 0 {
+			podSpec.Template = &apiv1.PodTemplateSpec{}
+			for _, container := range templateContainers {
+				podSpec.Template.Spec.Containers = append(podSpec.Template.Spec.Containers, convertContainer(container))
+			}
+		}
+
 		return podSpec
 	}
 
-	// Helper function to convert string to int32
-	stringToInt32 := func(s string) int32 {
-		if s == "" {
-			return 0
-		}
-		// Simple conversion - in production you might want more robust parsing
-		var result int32
-		_, err := fmt.Sscanf(s, "%d", &result)
-		if err != nil {
-			return 0
+	// Helper function to convert proto Ports, rejecting container ports that are not valid port numbers
+	convertPorts := func(protoPorts []*pb.Ports) ([]v1beta2.Port, error) {
+		var ports []v1beta2.Port
+		for _, port := range protoPorts {
+			containerPort, err := strconv.ParseInt(strings.TrimSpace(port.GetContainerPort()), 10, 32)
+			if err != nil || containerPort < 1 || containerPort > 65535 {
+				return nil, fmt.Errorf("invalid container port %q for port %s: must be a number between 1 and 65535", port.GetContainerPort(), port.GetName())
+			}
+			ports = append(ports, v1beta2.Port{
+				Name:          port.GetName(),
+				Protocol:      port.GetProtocol(),
+				ContainerPort: int32(containerPort),
+			})
 		}
-		return result
+		return ports, nil
 	}
 
 	// Helper function to convert proto PrometheusSpec to v1beta2.PrometheusSpec
@@ -233,6 +424,42 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		}
 	}
 
+	// Helper function to convert proto Quantity to resource.Quantity, preferring its string form
+	convertQuantity := func(protoQuantity *pb.Quantity) (resource.Quantity, error) {
+		if protoQuantity.GetS() != "" {
+			return resource.ParseQuantity(protoQuantity.GetS())
+		}
+		if amount := protoQuantity.GetI(); amount != nil {
+			return *resource.NewScaledQuantity(amount.GetValue(), resource.Scale(amount.GetScale().GetValue())), nil
+		}
+		if protoQuantity.GetD().GetDec() != "" {
+			return resource.ParseQuantity(protoQuantity.GetD().GetDec())
+		}
+		return resource.Quantity{}, fmt.Errorf("quantity has no value")
+	}
+
+	// Helper function to convert proto BatchSchedulerConfiguration to v1beta2.BatchSchedulerConfiguration
+	convertBatchSchedulerOptions := func(protoOptions *pb.BatchSchedulerConfiguration) (*v1beta2.BatchSchedulerConfiguration, error) {
+		if protoOptions == nil {
+			return nil, nil
+		}
+		options := &v1beta2.BatchSchedulerConfiguration{
+			Queue:             getStringPtr(protoOptions.GetQueue()),
+			PriorityClassName: getStringPtr(protoOptions.GetPriorityClassName()),
+		}
+		if len(protoOptions.GetResources()) > 0 {
+			options.Resources = make(apiv1.ResourceList, len(protoOptions.GetResources()))
+			for name, protoQuantity := range protoOptions.GetResources() {
+				quantity, err := convertQuantity(protoQuantity)
+				if err != nil {
+					return nil, fmt.Errorf("invalid batch scheduler resource %s: %w", name, err)
+				}
+				options.Resources[apiv1.ResourceName(name)] = quantity
+			}
+		}
+		return options, nil
+	}
+
 	// Helper function to convert proto MonitoringSpec to v1beta2.MonitoringSpec
 	convertMonitoringSpec := func(protoMonitoring *pb.MonitoringSpec) *v1beta2.MonitoringSpec {
 		if protoMonitoring == nil {
@@ -315,6 +542,13 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		}
 	}
 
+	// Handle BatchSchedulerOptions
+	batchSchedulerOptions, err := convertBatchSchedulerOptions(protoApp.GetSpec().GetBatchSchedulerConfiguration())
+	if err != nil {
+		return nil, err
+	}
+	app.Spec.BatchSchedulerOptions = batchSchedulerOptions
+
 	// Handle MonitoringSpec
 	if monitoring := protoApp.GetSpec().GetMonitoring(); monitoring != nil {
 		app.Spec.Monitoring = convertMonitoringSpec(monitoring)
@@ -342,6 +576,9 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		if driverSpec.GetPriorityClassName() != nil {
 			app.Spec.Driver.PriorityClassName = getStringPtr(driverSpec.GetPriorityClassName())
 		}
+		if driverSpec.GetLifeCycle() != nil {
+			app.Spec.Driver.Lifecycle = convertLifecycle(driverSpec.GetLifeCycle())
+		}
 
 		// Convert service annotations and labels
 		if len(driverSpec.GetServiceAnnotations()) > 0 {
@@ -352,13 +589,11 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		}
 
 		// Convert ports
-		for _, port := range driverSpec.GetPorts() {
-			app.Spec.Driver.Ports = append(app.Spec.Driver.Ports, v1beta2.Port{
-				Name:          port.GetName(),
-				Protocol:      port.GetProtocol(),
-				ContainerPort: stringToInt32(port.GetContainerPort()),
-			})
+		driverPorts, err := convertPorts(driverSpec.GetPorts())
+		if err != nil {
+			return nil, fmt.Errorf("invalid driver ports: %w", err)
 		}
+		app.Spec.Driver.Ports = driverPorts
 	}
 
 	// Handle ExecutorSpec
@@ -383,15 +618,16 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		if executorSpec.GetPriorityClassName() != nil {
 			app.Spec.Executor.PriorityClassName = getStringPtr(executorSpec.GetPriorityClassName())
 		}
+		if executorSpec.GetLifeCycle() != nil {
+			app.Spec.Executor.Lifecycle = convertLifecycle(executorSpec.GetLifeCycle())
+		}
 
 		// Convert ports
-		for _, port := range executorSpec.GetPorts() {
-			app.Spec.Executor.Ports = append(app.Spec.Executor.Ports, v1beta2.Port{
-				Name:          port.GetName(),
-				Protocol:      port.GetProtocol(),
-				ContainerPort: stringToInt32(port.GetContainerPort()),
-			})
+		executorPorts, err := convertPorts(executorSpec.GetPorts())
+		if err != nil {
+			return nil, fmt.Errorf("invalid executor ports: %w", err)
 		}
+		app.Spec.Executor.Ports = executorPorts
 	}
 
 	// Handle Volumes
@@ -399,7 +635,32 @@ func convertProtoToSparkApplication(protoApp *pb.SparkApplication) *v1beta2.Spar
 		app.Spec.Volumes = append(app.Spec.Volumes, convertVolume(volume))
 	}
 
-	return app
+	// Convert Spark UI and driver ingress options
+	if uiConfig := protoApp.GetSpec().GetSparkUiConfiguration(); uiConfig != nil {
+		app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{
+			ServicePort:        getInt32Ptr(uiConfig.GetServicePort()),
+			ServicePortName:    getStringPtr(uiConfig.GetServicePortName()),
+			ServiceType:        convertServiceType(uiConfig.GetServiceType()),
+			ServiceAnnotations: uiConfig.GetServiceAnnotations(),
+			ServiceLabels:      uiConfig.GetServiceLabels(),
+			IngressAnnotations: uiConfig.GetIngressAnnotations(),
+			IngressTLS:         convertIngressTLS(uiConfig.GetIngressTls()),
+		}
+	}
+	for _, ingressConfig := range protoApp.GetSpec().GetDriverIngressConfiguration() {
+		app.Spec.DriverIngressOptions = append(app.Spec.DriverIngressOptions, v1beta2.DriverIngressConfiguration{
+			ServicePort:        getInt32Ptr(ingressConfig.GetServicePort()),
+			ServicePortName:    getStringPtr(ingressConfig.GetServicePortName()),
+			ServiceType:        convertServiceType(ingressConfig.GetServiceType()),
+			ServiceAnnotations: ingressConfig.GetServiceAnnotations(),
+			ServiceLabels:      ingressConfig.GetServiceLabels(),
+			IngressURLFormat:   ingressConfig.GetIngressUrlFormat(),
+			IngressAnnotations: ingressConfig.GetIngressAnnotations(),
+			IngressTLS:         convertIngressTLS(ingressConfig.GetIngressTls()),
+		})
+	}
+
+	return app, nil
 }
 
 type server struct {
@@ -409,8 +670,15 @@ type server struct {
 func (s *server) RunAltSparkSubmit(ctx context.Context, req *pb.RunAltSparkSubmitRequest) (*pb.RunAltSparkSubmitResponse, error) {
 	start := time.Now()
 
-	app := convertProtoToSparkApplication(req.GetSparkApplication())
-	success, err := runAltSparkSubmit(app, req.GetSubmissionId())
+	app, err := convertProtoToSparkApplication(req.GetSparkApplication())
+	if err != nil {
+		RecordSparkApplicationMetrics("unknown", false, time.Since(start))
+		return &pb.RunAltSparkSubmitResponse{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+	success, admissionState, err := runAltSparkSubmit(app, req.GetSubmissionId())
 
 	// Record metrics
 	appType := "unknown"
@@ -426,11 +694,77 @@ func (s *server) RunAltSparkSubmit(ctx context.Context, req *pb.RunAltSparkSubmi
 		}, nil
 	}
 	return &pb.RunAltSparkSubmitResponse{
-		Success:      success,
-		ErrorMessage: "",
+		Success:        success,
+		ErrorMessage:   "",
+		AdmissionState: admissionState,
 	}, nil
 }
 
+func (s *server) ValidateSparkApplication(ctx context.Context, req *pb.ValidateSparkApplicationRequest) (*pb.ValidateSparkApplicationResponse, error) {
+	app, err := convertProtoToSparkApplication(req.GetSparkApplication())
+	if err != nil {
+		return &pb.ValidateSparkApplicationResponse{
+			Valid:        false,
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+
+	// A missing application is reported by the validation itself
+	var config resolver.Config
+	if app != nil {
+		config = resolver.Resolve(app)
+	}
+	validationErrs := validation.ValidateSparkApplication(app, config)
+	fieldErrors := make([]*pb.FieldError, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		fieldErrors = append(fieldErrors, &pb.FieldError{
+			Field:   validationErr.Field,
+			Message: validationErr.ErrorBody(),
+		})
+	}
+	return &pb.ValidateSparkApplicationResponse{
+		Valid:  len(validationErrs) == 0,
+		Errors: fieldErrors,
+	}, nil
+}
+
+func (s *server) ExplainSparkApplication(ctx context.Context, req *pb.ExplainSparkApplicationRequest) (*pb.ExplainSparkApplicationResponse, error) {
+	app, err := convertProtoToSparkApplication(req.GetSparkApplication())
+	if err != nil {
+		return &pb.ExplainSparkApplicationResponse{ErrorMessage: err.Error()}, nil
+	}
+
+	if app == nil {
+		return &pb.ExplainSparkApplicationResponse{ErrorMessage: "spark application cannot be nil"}, nil
+	}
+
+	entries, err := explain.Explain(resolver.Resolve(app))
+	if err != nil {
+		return &pb.ExplainSparkApplicationResponse{ErrorMessage: err.Error()}, nil
+	}
+	settings := make([]*pb.ExplainedSetting, 0, len(entries))
+	for _, entry := range entries {
+		setting := toExplainedSetting(entry.Setting)
+		setting.Role = entry.Role
+		setting.Name = entry.Name
+		setting.Note = entry.Note
+		settings = append(settings, setting)
+	}
+	return &pb.ExplainSparkApplicationResponse{Settings: settings}, nil
+}
+
+func toExplainedSetting(setting resolver.Setting) *pb.ExplainedSetting {
+	explained := &pb.ExplainedSetting{
+		Value:  setting.Value,
+		Source: string(setting.Source),
+		Field:  setting.Field,
+	}
+	for _, overridden := range setting.Overridden {
+		explained.Overridden = append(explained.Overridden, toExplainedSetting(overridden))
+	}
+	return explained
+}
+
 // HTTP health check handler
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
@@ -445,6 +779,10 @@ func readinessCheckHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == explainCommand {
+		os.Exit(runExplainCommand(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	log.Println("Starting native-submit gRPC service...")
 
 	// Get port from environment or use default
@@ -485,7 +823,8 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(metricsUnaryInterceptor()),
+		// Panics are recovered inside the metrics interceptor so they are counted as internal errors
+		grpc.ChainUnaryInterceptor(metricsUnaryInterceptor(), recoveryUnaryInterceptor()),
 	)
 	pb.RegisterSparkSubmitServiceServer(grpcServer, &server{})
 
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "nativesubmit/proto/spark"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestConvertProtoToSparkApplicationLifecycleAndProbes(t *testing.T) {
+	protoApp := &pb.SparkApplication{
+		Spec: &pb.SparkApplicationSpec{
+			Driver: &pb.DriverSpec{
+				LifeCycle: &pb.Lifecycle{
+					PreStop: &pb.LifecycleHandler{
+						HttpGet: &pb.HTTPGetAction{
+							Path:   "/flush",
+							Port:   &pb.IntOrString{Type: &pb.IntOrString_StrVal{StrVal: "spark-ui"}},
+							Scheme: pb.URIScheme_URISCHEME_HTTPS,
+						},
+					},
+					PostStart: &pb.LifecycleHandler{
+						Sleep: &pb.SleepAction{Seconds: 3},
+					},
+				},
+				SparkPodSpec: &pb.SparkPodSpec{
+					Template: &pb.PodTemplateSpec{PodSpec: &pb.PodSpec{Containers: []*pb.Container{
+						{
+							Name: "spark-kubernetes-driver",
+							ReadinessProbe: &pb.Probe{
+								ProbeHandler: &pb.ProbeHandler{
+									TcpSocket: &pb.TCPSocketAction{Port: &pb.IntOrString{Type: &pb.IntOrString_StrVal{StrVal: "driver-rpc-port"}}},
+								},
+								InitialDelaySeconds: 5,
+							},
+						},
+					}}},
+					Sidecars: []*pb.Container{
+						{
+							Name: "log-shipper",
+							ReadinessProbe: &pb.Probe{
+								ProbeHandler: &pb.ProbeHandler{
+									TcpSocket: &pb.TCPSocketAction{Port: &pb.IntOrString{Type: &pb.IntOrString_IntVal{IntVal: 24224}}},
+								},
+								PeriodSeconds:                 10,
+								TerminationGracePeriodSeconds: wrapperspb.Int64(30),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	app, err := convertProtoToSparkApplication(protoApp)
+	require.NoError(t, err)
+
+	require.NotNil(t, app.Spec.Driver.Lifecycle)
+	assert.Equal(t, &apiv1.LifecycleHandler{
+		HTTPGet: &apiv1.HTTPGetAction{
+			Path:   "/flush",
+			Port:   intstr.FromString("spark-ui"),
+			Scheme: apiv1.URISchemeHTTPS,
+		},
+	}, app.Spec.Driver.Lifecycle.PreStop)
+	assert.Equal(t, &apiv1.LifecycleHandler{Sleep: &apiv1.SleepAction{Seconds: 3}}, app.Spec.Driver.Lifecycle.PostStart)
+
+	require.Len(t, app.Spec.Driver.Sidecars, 1)
+	probe := app.Spec.Driver.Sidecars[0].ReadinessProbe
+	require.NotNil(t, probe)
+	assert.Equal(t, intstr.FromInt32(24224), probe.TCPSocket.Port)
+	assert.Equal(t, int32(10), probe.PeriodSeconds)
+	require.NotNil(t, probe.TerminationGracePeriodSeconds)
+	assert.Equal(t, int64(30), *probe.TerminationGracePeriodSeconds)
+	assert.Nil(t, app.Spec.Driver.Sidecars[0].LivenessProbe)
+
+	require.NotNil(t, app.Spec.Driver.Template)
+	require.Len(t, app.Spec.Driver.Template.Spec.Containers, 1)
+	driverProbe := app.Spec.Driver.Template.Spec.Containers[0].ReadinessProbe
+	require.NotNil(t, driverProbe)
+	assert.Equal(t, intstr.FromString("driver-rpc-port"), driverProbe.TCPSocket.Port)
+	assert.Equal(t, int32(5), driverProbe.InitialDelaySeconds)
+}
+
+func TestConvertProtoToSparkApplicationPorts(t *testing.T) {
+	tests := []struct {
+		name          string
+		containerPort string
+		want          int32
+		wantErr       bool
+	}{
+		{"valid port", "10000", 10000, false},
+		{"not a number", "thrift", 0, true},
+		{"empty", "", 0, true},
+		{"out of range", "65536", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protoApp := &pb.SparkApplication{
+				Spec: &pb.SparkApplicationSpec{
+					Driver: &pb.DriverSpec{
+						Ports: []*pb.Ports{{Name: "thrift", Protocol: "TCP", ContainerPort: tt.containerPort}},
+					},
+				},
+			}
+
+			app, err := convertProtoToSparkApplication(protoApp)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, app.Spec.Driver.Ports, 1)
+			assert.Equal(t, tt.want, app.Spec.Driver.Ports[0].ContainerPort)
+		})
+	}
+}
+
+func TestConvertProtoToSparkApplicationConfigMapsAndEnvFrom(t *testing.T) {
+	protoApp := &pb.SparkApplication{
+		Spec: &pb.SparkApplicationSpec{
+			Driver: &pb.DriverSpec{
+				SparkPodSpec: &pb.SparkPodSpec{
+					Configmaps: []*pb.NamePath{{Name: "app-config", Path: "/etc/app"}},
+					EnvFrom: []*pb.EnvFromSource{
+						{ConfigMapRef: &pb.ConfigMapEnvSource{LocalObjectReference: &pb.LocalObjectReference{Name: "app-env"}}},
+						{Prefix: "DB_", SecretRef: &pb.SecretEnvSource{LocalObjectReference: &pb.LocalObjectReference{Name: "db-credentials"}, Optional: wrapperspb.Bool(true)}},
+					},
+				},
+			},
+		},
+	}
+
+	app, err := convertProtoToSparkApplication(protoApp)
+
+	require.NoError(t, err)
+	assert.Equal(t, []v1beta2.NamePath{{Name: "app-config", Path: "/etc/app"}}, app.Spec.Driver.ConfigMaps)
+	require.Len(t, app.Spec.Driver.EnvFrom, 2)
+	assert.Equal(t, "app-env", app.Spec.Driver.EnvFrom[0].ConfigMapRef.Name)
+	assert.Nil(t, app.Spec.Driver.EnvFrom[0].SecretRef)
+	assert.Equal(t, "DB_", app.Spec.Driver.EnvFrom[1].Prefix)
+	assert.Equal(t, "db-credentials", app.Spec.Driver.EnvFrom[1].SecretRef.Name)
+	require.NotNil(t, app.Spec.Driver.EnvFrom[1].SecretRef.Optional)
+	assert.True(t, *app.Spec.Driver.EnvFrom[1].SecretRef.Optional)
+}
+
+func TestConvertProtoToSparkApplicationUIAndIngressOptions(t *testing.T) {
+	protoApp := &pb.SparkApplication{
+		Spec: &pb.SparkApplicationSpec{
+			SparkUiConfiguration: &pb.SparkUIConfiguration{
+				ServicePort:        wrapperspb.Int32(80),
+				ServiceType:        pb.ServiceType_SERVICE_TYPE_NODE_PORT,
+				ServiceLabels:      map[string]string{"team": "data"},
+				IngressAnnotations: map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+				IngressTls:         []*pb.IngressTLS{{Hosts: []string{"spark.example.com"}, SecretName: "spark-tls"}},
+			},
+			DriverIngressConfiguration: []*pb.DriverIngressConfiguration{
+				{
+					ServicePort:      wrapperspb.Int32(8888),
+					ServicePortName:  wrapperspb.String("notebook"),
+					IngressUrlFormat: "{{$appName}}.example.com",
+				},
+			},
+		},
+	}
+
+	app, err := convertProtoToSparkApplication(protoApp)
+	require.NoError(t, err)
+
+	uiOptions := app.Spec.SparkUIOptions
+	require.NotNil(t, uiOptions)
+	assert.Equal(t, int32(80), *uiOptions.ServicePort)
+	assert.Nil(t, uiOptions.ServicePortName)
+	assert.Equal(t, apiv1.ServiceTypeNodePort, *uiOptions.ServiceType)
+	assert.Equal(t, map[string]string{"team": "data"}, uiOptions.ServiceLabels)
+	assert.Equal(t, map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}, uiOptions.IngressAnnotations)
+	assert.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"spark.example.com"}, SecretName: "spark-tls"}}, uiOptions.IngressTLS)
+
+	require.Len(t, app.Spec.DriverIngressOptions, 1)
+	ingressOptions := app.Spec.DriverIngressOptions[0]
+	assert.Equal(t, int32(8888), *ingressOptions.ServicePort)
+	assert.Equal(t, "notebook", *ingressOptions.ServicePortName)
+	assert.Nil(t, ingressOptions.ServiceType, "unspecified service type keeps the default")
+	assert.Equal(t, "{{$appName}}.example.com", ingressOptions.IngressURLFormat)
+}
+
+func TestConvertProtoToSparkApplicationBatchSchedulerOptions(t *testing.T) {
+	protoApp := &pb.SparkApplication{
+		Spec: &pb.SparkApplicationSpec{
+			BatchScheduler: wrapperspb.String("volcano"),
+			BatchSchedulerConfiguration: &pb.BatchSchedulerConfiguration{
+				Queue:             wrapperspb.String("analytics"),
+				PriorityClassName: wrapperspb.String("high-priority"),
+				Resources: map[string]*pb.Quantity{
+					"cpu":    {S: "4"},
+					"memory": {I: &pb.Int64Amount{Value: 8, Scale: &pb.Scale{Value: 9}}},
+				},
+			},
+		},
+	}
+
+	app, err := convertProtoToSparkApplication(protoApp)
+	require.NoError(t, err)
+	assert.Equal(t, "volcano", *app.Spec.BatchScheduler)
+
+	options := app.Spec.BatchSchedulerOptions
+	require.NotNil(t, options)
+	assert.Equal(t, "analytics", *options.Queue)
+	assert.Equal(t, "high-priority", *options.PriorityClassName)
+	assert.True(t, resource.MustParse("4").Equal(options.Resources[apiv1.ResourceCPU]))
+	assert.True(t, resource.MustParse("8G").Equal(options.Resources[apiv1.ResourceMemory]))
+
+	protoApp.Spec.BatchSchedulerConfiguration.Resources["memory"] = &pb.Quantity{}
+	_, err = convertProtoToSparkApplication(protoApp)
+	assert.Error(t, err)
+}
+
+func TestValidateSparkApplicationRPC(t *testing.T) {
+	protoApp := &pb.SparkApplication{
+		Metadata: &pb.ObjectMeta{Name: "test-app", Namespace: "default"},
+		Spec: &pb.SparkApplicationSpec{
+			Type:                pb.SparkApplicationType_SPARK_APPLICATION_TYPE_PYTHON,
+			MainApplicationFile: wrapperspb.String("local:///opt/app/main.jar"),
+			DynamicAllocation:   &pb.DynamicAllocation{Enabled: true, MinExecutors: 2, InitialExecutors: 2, MaxExecutors: 1},
+		},
+	}
+
+	resp, err := (&server{}).ValidateSparkApplication(context.Background(), &pb.ValidateSparkApplicationRequest{SparkApplication: protoApp})
+	require.NoError(t, err)
+	assert.False(t, resp.GetValid())
+	assert.Empty(t, resp.GetErrorMessage())
+	fields := make([]string, 0, len(resp.GetErrors()))
+	for _, fieldError := range resp.GetErrors() {
+		fields = append(fields, fieldError.GetField())
+	}
+	assert.Equal(t, []string{"spec.mainApplicationFile", "spec.dynamicAllocation.maxExecutors", "spec.dynamicAllocation.initialExecutors"}, fields)
+	assert.Contains(t, resp.GetErrors()[0].GetMessage(), "must be a .py file for Python applications")
+
+	protoApp.Spec.MainApplicationFile = wrapperspb.String("local:///opt/app/main.py")
+	protoApp.Spec.DynamicAllocation.MaxExecutors = 4
+	resp, err = (&server{}).ValidateSparkApplication(context.Background(), &pb.ValidateSparkApplicationRequest{SparkApplication: protoApp})
+	require.NoError(t, err)
+	assert.True(t, resp.GetValid())
+	assert.Empty(t, resp.GetErrors())
+}
+
+func TestExplainSparkApplicationRPC(t *testing.T) {
+	protoApp := &pb.SparkApplication{
+		Metadata: &pb.ObjectMeta{Name: "test-app", Namespace: "default"},
+		Spec: &pb.SparkApplicationSpec{
+			Type:      pb.SparkApplicationType_SPARK_APPLICATION_TYPE_SCALA,
+			SparkConf: map[string]string{"spark.driver.memory": "4g"},
+			Driver:    &pb.DriverSpec{SparkPodSpec: &pb.SparkPodSpec{Memory: "2g"}},
+		},
+	}
+
+	resp, err := (&server{}).ExplainSparkApplication(context.Background(), &pb.ExplainSparkApplicationRequest{SparkApplication: protoApp})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetErrorMessage())
+	settings := make(map[string]*pb.ExplainedSetting)
+	for _, setting := range resp.GetSettings() {
+		settings[setting.GetRole()+"/"+setting.GetName()] = setting
+	}
+	memory := settings["driver/memory"]
+	require.NotNil(t, memory)
+	assert.Equal(t, "2g", memory.GetValue())
+	assert.Equal(t, "spec", memory.GetSource())
+	assert.Equal(t, "spec.driver.memory", memory.GetField())
+	require.Len(t, memory.GetOverridden(), 1)
+	assert.Equal(t, "spec.sparkConf[spark.driver.memory]", memory.GetOverridden()[0].GetField())
+	assert.Equal(t, "1408Mi", settings["executor/containerMemory"].GetValue())
+	assert.Equal(t, "1024Mi memory + 384Mi overhead", settings["executor/containerMemory"].GetNote())
+
+	protoApp.Spec.SparkConf["spark.executor.memory"] = "lots"
+	resp, err = (&server{}).ExplainSparkApplication(context.Background(), &pb.ExplainSparkApplicationRequest{SparkApplication: protoApp})
+	require.NoError(t, err)
+	assert.Contains(t, resp.GetErrorMessage(), "spec.sparkConf[spark.executor.memory]")
+	assert.Empty(t, resp.GetSettings())
+
+	resp, err = (&server{}).ExplainSparkApplication(context.Background(), &pb.ExplainSparkApplicationRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "spark application cannot be nil", resp.GetErrorMessage())
+}
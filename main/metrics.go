@@ -30,6 +30,14 @@ var (
 		[]string{"method"},
 	)
 
+	grpcPanicsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_panics_total",
+			Help: "Total number of gRPC requests recovered from a panic",
+		},
+		[]string{"method"},
+	)
+
 	// Spark application metrics
 	sparkApplicationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoveryUnaryInterceptor turns a panic while serving a request into a codes.Internal error, so one bad spec cannot
+// crash the server. The returned correlation ID is logged with the panic and its stack trace.
+func recoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				correlationID := uuid.New().String()
+				grpcPanicsTotal.WithLabelValues(info.FullMethod).Inc()
+				log.Printf("ERROR: Recovered from panic in %s, correlation ID: %s: %v\n%s", info.FullMethod, correlationID, r, debug.Stack())
+				resp = nil
+				err = status.Errorf(codes.Internal, "internal error while serving %s, correlation ID: %s", info.FullMethod, correlationID)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
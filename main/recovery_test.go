@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/spark.SparkSubmitService/RunAltSparkSubmit"}
+	interceptor := recoveryUnaryInterceptor()
+
+	resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	panicsBefore := testutil.ToFloat64(grpcPanicsTotal.WithLabelValues(info.FullMethod))
+	resp, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("bad spec")
+	})
+	assert.Nil(t, resp)
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Contains(t, st.Message(), "correlation ID: ")
+	assert.NotContains(t, st.Message(), "bad spec", "panic details are only logged")
+	assert.Equal(t, panicsBefore+1, testutil.ToFloat64(grpcPanicsTotal.WithLabelValues(info.FullMethod)))
+}
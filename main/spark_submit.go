@@ -7,7 +7,16 @@ import (
 	"nativesubmit/common"
 	"nativesubmit/internal/configmap"
 	"nativesubmit/internal/driver"
+	"nativesubmit/internal/ingress"
+	"nativesubmit/internal/networkpolicy"
+	"nativesubmit/internal/pdb"
+	"nativesubmit/internal/preflight"
+	"nativesubmit/internal/reference"
+	"nativesubmit/internal/resolver"
+	"nativesubmit/internal/scheduler"
 	"nativesubmit/internal/service"
+	"nativesubmit/internal/validation"
+	pb "nativesubmit/proto/spark"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +24,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -25,8 +35,6 @@ const (
 	SparkAppDriverServiceNameExtension = "-driver-svc"
 	KubernetesDNSLabelNameMaxLength    = 63
 	ServiceNameExtension               = "-svc"
-	DotSeparator                       = "."
-	Version                            = "version"
 	SparkAppName                       = "spark-app-name"
 	ConfigMapExtension                 = "-conf-map"
 	SparkApplicationSelectorLabel      = "spark-app-selector"
@@ -52,17 +60,30 @@ const (
 // Logic involved in moving "New" Spark Application to "Submitted" state is implemented in Golang with this function RunAltSparkSubmit as starting step
 // 3 Resources are created in this logic per new Spark Application, in the order listed: ConfigMap for the Spark Application, Driver Pod, Driver Service
 
-func runAltSparkSubmit(app *v1beta2.SparkApplication, submissionID string) (bool, error) {
+func runAltSparkSubmit(app *v1beta2.SparkApplication, submissionID string) (bool, pb.AdmissionState, error) {
 	log.Printf("=== Starting Spark Application submission process ===")
 
 	if app == nil {
 		log.Printf("ERROR: Spark application is nil")
-		return false, fmt.Errorf("spark application cannot be nil")
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("spark application cannot be nil")
 	}
 
 	log.Printf("App name: %s, Namespace: %s, Submission ID: %s", app.Name, app.Namespace, submissionID)
 
-	kubeClient := getKubeClientOrDie()
+	//Invalid specs are rejected with every problem before any client is created
+	log.Printf("=== Step 1: Validating Spark Application ===")
+	//The configuration is resolved once, spark-defaults.conf included, and shared by the steps below
+	config := resolver.Resolve(app)
+	if validationErrs := validation.ValidateSparkApplication(app, config); len(validationErrs) > 0 {
+		log.Printf("ERROR: Spark application validation failed: %v", validationErrs.ToAggregate())
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("spark application %s is invalid: %w", app.Name, validationErrs.ToAggregate())
+	}
+
+	kubeClient, err := getKubeClient()
+	if err != nil {
+		log.Printf("ERROR: Failed to create Kubernetes client: %v", err)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, err
+	}
 
 	appSpecVolumeMounts := app.Spec.Driver.VolumeMounts
 	appSpecVolumes := app.Spec.Volumes
@@ -86,6 +107,36 @@ func runAltSparkSubmit(app *v1beta2.SparkApplication, submissionID string) (bool
 	// //Update Application CRD Instance with Submission ID
 	app.Status.SubmissionID = submissionID
 
+	//Every referenced Secret, ConfigMap, ServiceAccount and PVC must exist, unless the namespace makes the check advisory
+	log.Printf("=== Step 2: Resolving References ===")
+	referenceErr := reference.Check(app, config, kubeClient)
+	if referenceErr != nil {
+		log.Printf("ERROR: Reference checks failed: %v", referenceErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("spark application %s has missing references: %w", app.Name, referenceErr)
+	}
+
+	//Pre-flight checks against the namespace LimitRanges and ResourceQuotas, before anything is created
+	log.Printf("=== Step 3: Running Pre-flight Checks ===")
+	preflightErr := preflight.Check(app, config, kubeClient)
+	if preflightErr != nil {
+		log.Printf("ERROR: Pre-flight checks failed: %v", preflightErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("spark application %s does not fit namespace %s: %w", app.Name, app.Namespace, preflightErr)
+	}
+
+	//Batch scheduler resources, the driver and executors are pointed at them through the resolved configuration, so they
+	//are prepared before the driver labels
+	log.Printf("=== Step 4: Creating Batch Scheduler Resources ===")
+	dynamicClient, err := getKubeDynamicClient()
+	if err != nil {
+		log.Printf("ERROR: Failed to create Kubernetes dynamic client: %v", err)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, err
+	}
+	createSchedulerErr := scheduler.Create(app, &config, kubeClient, dynamicClient, string(app.ObjectMeta.GetUID()))
+	if createSchedulerErr != nil {
+		log.Printf("ERROR: Batch scheduler resources creation failed: %v", createSchedulerErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while preparing batch scheduler %s in namespace %s: %w", scheduler.GetName(app), app.Namespace, createSchedulerErr)
+	}
+
 	//Create Service Labels by aggregating Spark Application Specification level, driver specification level and dynamic lables
 	serviceLabels := map[string]string{
 		SparkAppNameLabel:              app.Name,
@@ -97,63 +148,78 @@ func runAltSparkSubmit(app *v1beta2.SparkApplication, submissionID string) (bool
 	}
 	log.Printf("Created base service labels: %v", getMapKeys(serviceLabels))
 
-	if app.Spec.Driver.Labels != nil {
-		if version, exists := app.Spec.Driver.Labels[Version]; exists {
-			serviceLabels[Version] = version
-		}
-		for key, val := range app.Spec.Driver.Labels {
-			serviceLabels[key] = val
-		}
-		log.Printf("Added driver labels: %d entries", len(app.Spec.Driver.Labels))
-	}
-
-	if app.Labels != nil {
-		for key, val := range app.Labels {
-			serviceLabels[key] = val
-		}
-		log.Printf("Added app labels: %d entries", len(app.Labels))
-	}
-
-	// labels passed in sparkConf
-	sparkConfKeyValuePairs := app.Spec.SparkConf
-	for sparkConfKey, sparkConfValue := range sparkConfKeyValuePairs {
-		if strings.Contains(sparkConfKey, "spark.kubernetes.driver.label.") {
-			lastDotIndex := strings.LastIndex(sparkConfKey, DotSeparator)
-			labelKey := sparkConfKey[lastDotIndex+1:]
-			serviceLabels[labelKey] = sparkConfValue
-		}
+	//Resolved driver labels: the application, sparkConf and driver labels, and those of the batch scheduler
+	for key, val := range resolver.Values(config.Driver.Labels) {
+		serviceLabels[key] = val
 	}
 	log.Printf("Final service labels count: %d", len(serviceLabels))
 
 	//Spark Application ConfigMap Creation
-	log.Printf("=== Step 1: Creating ConfigMap ===")
-	createErr := configmap.Create(app, submissionID, string(app.ObjectMeta.GetUID()), kubeClient, driverConfigMapName, serviceName)
+	log.Printf("=== Step 5: Creating ConfigMap ===")
+	configHash, createErr := configmap.Create(app, config, submissionID, string(app.ObjectMeta.GetUID()), kubeClient, driverConfigMapName, serviceName)
 	if createErr != nil {
 		log.Printf("ERROR: ConfigMap creation failed: %v", createErr)
-		return false, fmt.Errorf("error while creating configmap %s in namespace %s: %w", driverConfigMapName, app.Namespace, createErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while creating configmap %s in namespace %s: %w", driverConfigMapName, app.Namespace, createErr)
 	}
 	log.Printf("ConfigMap creation completed successfully")
 
 	//Spark Application Driver Pod Creation
-	log.Printf("=== Step 2: Creating Driver Pod ===")
-	driverPodUID, createPodErr := driver.Create(app, serviceLabels, driverConfigMapName, kubeClient, appSpecVolumeMounts, appSpecVolumes)
+	log.Printf("=== Step 6: Creating Driver Pod ===")
+	driverPodUID, createPodErr := driver.Create(app, config, serviceLabels, driverConfigMapName, configHash, kubeClient, appSpecVolumeMounts, appSpecVolumes)
 	if createPodErr != nil {
 		log.Printf("ERROR: Driver pod creation failed: %v", createPodErr)
-		return false, fmt.Errorf("error while creating driver pod %s in namespace %s: %w", common.GetDriverPodName(app), app.Namespace, createPodErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while creating driver pod %s in namespace %s: %w", common.GetDriverPodName(app), app.Namespace, createPodErr)
 	}
 	log.Printf("Driver pod creation completed successfully, Pod UID: %s", driverPodUID)
 
 	//Spark Application Driver Pod's Service Creation
-	log.Printf("=== Step 3: Creating Driver Service ===")
-	createServiceErr := service.Create(app, serviceLabels, kubeClient, string(app.ObjectMeta.GetUID()), serviceName, driverPodUID)
+	log.Printf("=== Step 7: Creating Driver Service ===")
+	createServiceErr := service.Create(app, config, serviceLabels, kubeClient, string(app.ObjectMeta.GetUID()), serviceName, driverPodUID)
 	if createServiceErr != nil {
 		log.Printf("ERROR: Driver service creation failed: %v", createServiceErr)
-		return false, fmt.Errorf("error while creating driver service %s in namespace %s: %w", serviceName, app.Namespace, createServiceErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while creating driver service %s in namespace %s: %w", serviceName, app.Namespace, createServiceErr)
 	}
 	log.Printf("Driver service creation completed successfully")
 
+	//Optional PodDisruptionBudget protecting the Driver Pod from voluntary evictions such as node drains
+	log.Printf("=== Step 8: Creating Driver PodDisruptionBudget ===")
+	createPDBErr := pdb.Create(app, kubeClient, string(app.ObjectMeta.GetUID()), driverPodUID)
+	if createPDBErr != nil {
+		log.Printf("ERROR: Driver PodDisruptionBudget creation failed: %v", createPDBErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while creating driver pod disruption budget %s in namespace %s: %w", pdb.GetName(app), app.Namespace, createPDBErr)
+	}
+
+	//Optional NetworkPolicy isolating the Driver and Executor Pods of the Spark Application
+	log.Printf("=== Step 9: Creating NetworkPolicy ===")
+	createNetworkPolicyErr := networkpolicy.Create(app, config, kubeClient, string(app.ObjectMeta.GetUID()), driverPodUID)
+	if createNetworkPolicyErr != nil {
+		log.Printf("ERROR: NetworkPolicy creation failed: %v", createNetworkPolicyErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while creating network policy %s in namespace %s: %w", networkpolicy.GetName(app), app.Namespace, createNetworkPolicyErr)
+	}
+
+	//Spark UI and driver ingress options Services and Ingresses
+	log.Printf("=== Step 10: Creating Spark UI and Driver Ingresses ===")
+	createIngressErr := ingress.Create(app, config, kubeClient, string(app.ObjectMeta.GetUID()), driverPodUID)
+	if createIngressErr != nil {
+		log.Printf("ERROR: Spark UI and driver ingress creation failed: %v", createIngressErr)
+		return false, pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED, fmt.Errorf("error while creating spark ui and driver ingresses in namespace %s: %w", app.Namespace, createIngressErr)
+	}
+
+	//Everything is created, a failed admission check is only reported as an unspecified admission state
+	admissionState := pb.AdmissionState_ADMISSION_STATE_UNSPECIFIED
+	admission, admissionErr := scheduler.GetAdmission(app, kubeClient)
+	switch {
+	case admissionErr != nil:
+		log.Printf("WARNING: Kueue admission check failed: %v", admissionErr)
+	case admission == scheduler.AdmissionQueued:
+		log.Printf("Driver pod is queued for Kueue admission")
+		admissionState = pb.AdmissionState_ADMISSION_STATE_QUEUED
+	case admission == scheduler.AdmissionAdmitted:
+		admissionState = pb.AdmissionState_ADMISSION_STATE_ADMITTED
+	}
+
 	log.Printf("=== Spark Application submission process completed successfully ===")
-	return true, nil
+	return true, admissionState, nil
 }
 
 // Helper function to get map keys for logging
@@ -190,18 +256,34 @@ func randomHex(n int) (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-func getKubeClientOrDie() *kubernetes.Clientset {
+func getKubeClient() (*kubernetes.Clientset, error) {
 	// Get the Kubernetes REST config (from kubeconfig or in-cluster)
 	cfg, err := ctrl.GetConfig()
 	if err != nil {
-		panic("failed to get kube config: " + err.Error())
+		return nil, fmt.Errorf("failed to get kube config: %w", err)
 	}
 
 	// Create the Kubernetes clientset
 	clientset, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
-		panic("failed to create clientset: " + err.Error())
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	return clientset, nil
+}
+
+func getKubeDynamicClient() (dynamic.Interface, error) {
+	// Get the Kubernetes REST config (from kubeconfig or in-cluster)
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube config: %w", err)
+	}
+
+	// Create the dynamic client for resources without a typed clientset, such as the Volcano PodGroups
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	return clientset
+	return dynamicClient, nil
 }
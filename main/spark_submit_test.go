@@ -49,7 +49,7 @@ func TestRunAltSparkSubmit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			success, err := runAltSparkSubmit(tt.app, tt.submissionID)
+			success, _, err := runAltSparkSubmit(tt.app, tt.submissionID)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -1221,6 +1221,55 @@ func (URIScheme) EnumDescriptor() ([]byte, []int) {
 	return file_proto_spark_submit_proto_rawDescGZIP(), []int{22}
 }
 
+type AdmissionState int32
+
+const (
+	AdmissionState_ADMISSION_STATE_UNSPECIFIED AdmissionState = 0
+	AdmissionState_ADMISSION_STATE_ADMITTED    AdmissionState = 1
+	AdmissionState_ADMISSION_STATE_QUEUED      AdmissionState = 2
+)
+
+// Enum value maps for AdmissionState.
+var (
+	AdmissionState_name = map[int32]string{
+		0: "ADMISSION_STATE_UNSPECIFIED",
+		1: "ADMISSION_STATE_ADMITTED",
+		2: "ADMISSION_STATE_QUEUED",
+	}
+	AdmissionState_value = map[string]int32{
+		"ADMISSION_STATE_UNSPECIFIED": 0,
+		"ADMISSION_STATE_ADMITTED":    1,
+		"ADMISSION_STATE_QUEUED":      2,
+	}
+)
+
+func (x AdmissionState) Enum() *AdmissionState {
+	p := new(AdmissionState)
+	*p = x
+	return p
+}
+
+func (x AdmissionState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AdmissionState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_spark_submit_proto_enumTypes[23].Descriptor()
+}
+
+func (AdmissionState) Type() protoreflect.EnumType {
+	return &file_proto_spark_submit_proto_enumTypes[23]
+}
+
+func (x AdmissionState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AdmissionState.Descriptor instead.
+func (AdmissionState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{23}
+}
+
 // SparkApplicationSpec
 type SparkApplicationSpec struct {
 	state                       protoimpl.MessageState        `protogen:"open.v1"`
@@ -7808,11 +7857,15 @@ func (x *RunAltSparkSubmitRequest) GetSubmissionId() string {
 
 // The response message indicating success or failure.
 type RunAltSparkSubmitResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// Whether the driver pod was admitted or waits in a Kueue queue when the submission finished, unspecified when the
+	// submission failed or no Kueue queue is involved. Kueue admits asynchronously, so a Kueue submission is usually
+	// QUEUED: watch the kueue.x-k8s.io/admission scheduling gate of the driver pod for its admission.
+	AdmissionState AdmissionState `protobuf:"varint,3,opt,name=admission_state,json=admissionState,proto3,enum=spark.AdmissionState" json:"admission_state,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *RunAltSparkSubmitResponse) Reset() {
@@ -7859,6 +7912,370 @@ func (x *RunAltSparkSubmitResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (x *RunAltSparkSubmitResponse) GetAdmissionState() AdmissionState {
+	if x != nil {
+		return x.AdmissionState
+	}
+	return AdmissionState_ADMISSION_STATE_UNSPECIFIED
+}
+
+// The request message containing the SparkApplication to validate.
+type ValidateSparkApplicationRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SparkApplication *SparkApplication      `protobuf:"bytes,1,opt,name=spark_application,json=sparkApplication,proto3" json:"spark_application,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ValidateSparkApplicationRequest) Reset() {
+	*x = ValidateSparkApplicationRequest{}
+	mi := &file_proto_spark_submit_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateSparkApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateSparkApplicationRequest) ProtoMessage() {}
+
+func (x *ValidateSparkApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spark_submit_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateSparkApplicationRequest.ProtoReflect.Descriptor instead.
+func (*ValidateSparkApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ValidateSparkApplicationRequest) GetSparkApplication() *SparkApplication {
+	if x != nil {
+		return x.SparkApplication
+	}
+	return nil
+}
+
+// The response message listing every validation problem of the SparkApplication.
+type ValidateSparkApplicationResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Valid  bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors []*FieldError          `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Set when the SparkApplication could not be converted, in which case it is not validated.
+	ErrorMessage  string `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateSparkApplicationResponse) Reset() {
+	*x = ValidateSparkApplicationResponse{}
+	mi := &file_proto_spark_submit_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateSparkApplicationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateSparkApplicationResponse) ProtoMessage() {}
+
+func (x *ValidateSparkApplicationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spark_submit_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateSparkApplicationResponse.ProtoReflect.Descriptor instead.
+func (*ValidateSparkApplicationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ValidateSparkApplicationResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateSparkApplicationResponse) GetErrors() []*FieldError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ValidateSparkApplicationResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// A validation problem of a SparkApplication field, e.g. spec.driver.ports[0].containerPort.
+type FieldError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldError) Reset() {
+	*x = FieldError{}
+	mi := &file_proto_spark_submit_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldError) ProtoMessage() {}
+
+func (x *FieldError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spark_submit_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldError.ProtoReflect.Descriptor instead.
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *FieldError) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// The request message containing the SparkApplication to explain.
+type ExplainSparkApplicationRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SparkApplication *SparkApplication      `protobuf:"bytes,1,opt,name=spark_application,json=sparkApplication,proto3" json:"spark_application,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ExplainSparkApplicationRequest) Reset() {
+	*x = ExplainSparkApplicationRequest{}
+	mi := &file_proto_spark_submit_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainSparkApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainSparkApplicationRequest) ProtoMessage() {}
+
+func (x *ExplainSparkApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spark_submit_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainSparkApplicationRequest.ProtoReflect.Descriptor instead.
+func (*ExplainSparkApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ExplainSparkApplicationRequest) GetSparkApplication() *SparkApplication {
+	if x != nil {
+		return x.SparkApplication
+	}
+	return nil
+}
+
+// The response message listing the effective settings of the driver and executors.
+type ExplainSparkApplicationResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Settings []*ExplainedSetting    `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty"`
+	// Set when the SparkApplication could not be converted or has invalid settings.
+	ErrorMessage  string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainSparkApplicationResponse) Reset() {
+	*x = ExplainSparkApplicationResponse{}
+	mi := &file_proto_spark_submit_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainSparkApplicationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainSparkApplicationResponse) ProtoMessage() {}
+
+func (x *ExplainSparkApplicationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spark_submit_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainSparkApplicationResponse.ProtoReflect.Descriptor instead.
+func (*ExplainSparkApplicationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *ExplainSparkApplicationResponse) GetSettings() []*ExplainedSetting {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *ExplainSparkApplicationResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// An effective setting and where its value comes from.
+type ExplainedSetting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// driver or executor, empty for the application wide settings.
+	Role  string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// spec, sparkConf, spark-defaults.conf, default or derived.
+	Source string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	// The spec field or property the value is read from, e.g. spec.sparkConf[spark.driver.memory].
+	Field string `protobuf:"bytes,5,opt,name=field,proto3" json:"field,omitempty"`
+	// How a derived value is computed.
+	Note string `protobuf:"bytes,6,opt,name=note,proto3" json:"note,omitempty"`
+	// The lower precedence inputs set to a different value.
+	Overridden    []*ExplainedSetting `protobuf:"bytes,7,rep,name=overridden,proto3" json:"overridden,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainedSetting) Reset() {
+	*x = ExplainedSetting{}
+	mi := &file_proto_spark_submit_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainedSetting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainedSetting) ProtoMessage() {}
+
+func (x *ExplainedSetting) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spark_submit_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainedSetting.ProtoReflect.Descriptor instead.
+func (*ExplainedSetting) Descriptor() ([]byte, []int) {
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *ExplainedSetting) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ExplainedSetting) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExplainedSetting) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ExplainedSetting) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ExplainedSetting) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ExplainedSetting) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ExplainedSetting) GetOverridden() []*ExplainedSetting {
+	if x != nil {
+		return x.Overridden
+	}
+	return nil
+}
+
 // Dependencies specifies all possible types of dependencies of a Spark application.
 type Dependencies struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -7875,7 +8292,7 @@ type Dependencies struct {
 
 func (x *Dependencies) Reset() {
 	*x = Dependencies{}
-	mi := &file_proto_spark_submit_proto_msgTypes[89]
+	mi := &file_proto_spark_submit_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7887,7 +8304,7 @@ func (x *Dependencies) String() string {
 func (*Dependencies) ProtoMessage() {}
 
 func (x *Dependencies) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_spark_submit_proto_msgTypes[89]
+	mi := &file_proto_spark_submit_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7900,7 +8317,7 @@ func (x *Dependencies) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Dependencies.ProtoReflect.Descriptor instead.
 func (*Dependencies) Descriptor() ([]byte, []int) {
-	return file_proto_spark_submit_proto_rawDescGZIP(), []int{89}
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{95}
 }
 
 func (x *Dependencies) GetJars() []string {
@@ -7965,7 +8382,7 @@ type DynamicAllocation struct {
 
 func (x *DynamicAllocation) Reset() {
 	*x = DynamicAllocation{}
-	mi := &file_proto_spark_submit_proto_msgTypes[90]
+	mi := &file_proto_spark_submit_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -7977,7 +8394,7 @@ func (x *DynamicAllocation) String() string {
 func (*DynamicAllocation) ProtoMessage() {}
 
 func (x *DynamicAllocation) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_spark_submit_proto_msgTypes[90]
+	mi := &file_proto_spark_submit_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7990,7 +8407,7 @@ func (x *DynamicAllocation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DynamicAllocation.ProtoReflect.Descriptor instead.
 func (*DynamicAllocation) Descriptor() ([]byte, []int) {
-	return file_proto_spark_submit_proto_rawDescGZIP(), []int{90}
+	return file_proto_spark_submit_proto_rawDescGZIP(), []int{96}
 }
 
 func (x *DynamicAllocation) GetEnabled() bool {
@@ -8687,10 +9104,36 @@ const file_proto_spark_submit_proto_rawDesc = "" +
 	"\x06status\x18\x03 \x01(\v2\x1d.spark.SparkApplicationStatusR\x06status\"\x85\x01\n" +
 	"\x18RunAltSparkSubmitRequest\x12D\n" +
 	"\x11spark_application\x18\x01 \x01(\v2\x17.spark.SparkApplicationR\x10sparkApplication\x12#\n" +
-	"\rsubmission_id\x18\x02 \x01(\tR\fsubmissionId\"Z\n" +
+	"\rsubmission_id\x18\x02 \x01(\tR\fsubmissionId\"\x9a\x01\n" +
 	"\x19RunAltSparkSubmitResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
-	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\xda\x01\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12>\n" +
+	"\x0fadmission_state\x18\x03 \x01(\x0e2\x15.spark.AdmissionStateR\x0eadmissionState\"g\n" +
+	"\x1fValidateSparkApplicationRequest\x12D\n" +
+	"\x11spark_application\x18\x01 \x01(\v2\x17.spark.SparkApplicationR\x10sparkApplication\"\x88\x01\n" +
+	" ValidateSparkApplicationResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12)\n" +
+	"\x06errors\x18\x02 \x03(\v2\x11.spark.FieldErrorR\x06errors\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"<\n" +
+	"\n" +
+	"FieldError\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"f\n" +
+	"\x1eExplainSparkApplicationRequest\x12D\n" +
+	"\x11spark_application\x18\x01 \x01(\v2\x17.spark.SparkApplicationR\x10sparkApplication\"{\n" +
+	"\x1fExplainSparkApplicationResponse\x123\n" +
+	"\bsettings\x18\x01 \x03(\v2\x17.spark.ExplainedSettingR\bsettings\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\xcb\x01\n" +
+	"\x10ExplainedSetting\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x14\n" +
+	"\x05field\x18\x05 \x01(\tR\x05field\x12\x12\n" +
+	"\x04note\x18\x06 \x01(\tR\x04note\x127\n" +
+	"\n" +
+	"overridden\x18\a \x03(\v2\x17.spark.ExplainedSettingR\n" +
+	"overridden\"\xda\x01\n" +
 	"\fDependencies\x12\x12\n" +
 	"\x04jars\x18\x01 \x03(\tR\x04jars\x12\x14\n" +
 	"\x05files\x18\x02 \x03(\tR\x05files\x12\x19\n" +
@@ -8823,9 +9266,15 @@ const file_proto_spark_submit_proto_rawDesc = "" +
 	"\tURIScheme\x12\x19\n" +
 	"\x15URISCHEME_UNSPECIFIED\x10\x00\x12\x12\n" +
 	"\x0eURISCHEME_HTTP\x10\x01\x12\x13\n" +
-	"\x0fURISCHEME_HTTPS\x10\x022l\n" +
+	"\x0fURISCHEME_HTTPS\x10\x02*k\n" +
+	"\x0eAdmissionState\x12\x1f\n" +
+	"\x1bADMISSION_STATE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18ADMISSION_STATE_ADMITTED\x10\x01\x12\x1a\n" +
+	"\x16ADMISSION_STATE_QUEUED\x10\x022\xc3\x02\n" +
 	"\x12SparkSubmitService\x12V\n" +
-	"\x11RunAltSparkSubmit\x12\x1f.spark.RunAltSparkSubmitRequest\x1a .spark.RunAltSparkSubmitResponseB\x1aZ\x18nativesubmit/proto/sparkb\x06proto3"
+	"\x11RunAltSparkSubmit\x12\x1f.spark.RunAltSparkSubmitRequest\x1a .spark.RunAltSparkSubmitResponse\x12k\n" +
+	"\x18ValidateSparkApplication\x12&.spark.ValidateSparkApplicationRequest\x1a'.spark.ValidateSparkApplicationResponse\x12h\n" +
+	"\x17ExplainSparkApplication\x12%.spark.ExplainSparkApplicationRequest\x1a&.spark.ExplainSparkApplicationResponseB\x1aZ\x18nativesubmit/proto/sparkb\x06proto3"
 
 var (
 	file_proto_spark_submit_proto_rawDescOnce sync.Once
@@ -8839,430 +9288,447 @@ func file_proto_spark_submit_proto_rawDescGZIP() []byte {
 	return file_proto_spark_submit_proto_rawDescData
 }
 
-var file_proto_spark_submit_proto_enumTypes = make([]protoimpl.EnumInfo, 23)
-var file_proto_spark_submit_proto_msgTypes = make([]protoimpl.MessageInfo, 113)
+var file_proto_spark_submit_proto_enumTypes = make([]protoimpl.EnumInfo, 24)
+var file_proto_spark_submit_proto_msgTypes = make([]protoimpl.MessageInfo, 119)
 var file_proto_spark_submit_proto_goTypes = []any{
-	(ManagedFieldsOperationType)(0),       // 0: spark.ManagedFieldsOperationType
-	(SparkApplicationType)(0),             // 1: spark.SparkApplicationType
-	(DeployMode)(0),                       // 2: spark.DeployMode
-	(ServiceType)(0),                      // 3: spark.ServiceType
-	(DNSPolicy)(0),                        // 4: spark.DNSPolicy
-	(PodConditionType)(0),                 // 5: spark.PodConditionType
-	(UnsatisfiableConstraintAction)(0),    // 6: spark.UnsatisfiableConstraintAction
-	(NodeInclusionPolicy)(0),              // 7: spark.NodeInclusionPolicy
-	(SecretType)(0),                       // 8: spark.SecretType
-	(LabelSelectorOperator)(0),            // 9: spark.LabelSelectorOperator
-	(NodeSelectorOperator)(0),             // 10: spark.NodeSelectorOperator
-	(TaintEffect)(0),                      // 11: spark.TaintEffect
-	(TolerationOperator)(0),               // 12: spark.TolerationOperator
-	(PodFSGroupChangePolicy)(0),           // 13: spark.PodFSGroupChangePolicy
-	(Protocol)(0),                         // 14: spark.Protocol
-	(Format)(0),                           // 15: spark.Format
-	(ResourceResizeRestartPolicy)(0),      // 16: spark.ResourceResizeRestartPolicy
-	(ContainerRestartPolicy)(0),           // 17: spark.ContainerRestartPolicy
-	(TerminationMessagePolicy)(0),         // 18: spark.TerminationMessagePolicy
-	(PullPolicy)(0),                       // 19: spark.PullPolicy
-	(ProcMountType)(0),                    // 20: spark.ProcMountType
-	(SeccompProfileType)(0),               // 21: spark.SeccompProfileType
-	(URIScheme)(0),                        // 22: spark.URIScheme
-	(*SparkApplicationSpec)(nil),          // 23: spark.SparkApplicationSpec
-	(*ObjectMeta)(nil),                    // 24: spark.ObjectMeta
-	(*FieldsV1)(nil),                      // 25: spark.FieldsV1
-	(*ManagedFieldsEntry)(nil),            // 26: spark.ManagedFieldsEntry
-	(*OwnerReference)(nil),                // 27: spark.OwnerReference
-	(*DriverIngressConfiguration)(nil),    // 28: spark.DriverIngressConfiguration
-	(*SparkUIConfiguration)(nil),          // 29: spark.SparkUIConfiguration
-	(*IngressTLS)(nil),                    // 30: spark.IngressTLS
-	(*BatchSchedulerConfiguration)(nil),   // 31: spark.BatchSchedulerConfiguration
-	(*MonitoringSpec)(nil),                // 32: spark.MonitoringSpec
-	(*PrometheusSpec)(nil),                // 33: spark.PrometheusSpec
-	(*RestartPolicy)(nil),                 // 34: spark.RestartPolicy
-	(*DriverSpec)(nil),                    // 35: spark.DriverSpec
-	(*SparkPodSpec)(nil),                  // 36: spark.SparkPodSpec
-	(*PodTemplateSpec)(nil),               // 37: spark.PodTemplateSpec
-	(*PodSpec)(nil),                       // 38: spark.PodSpec
-	(*EphemeralContainer)(nil),            // 39: spark.EphemeralContainer
-	(*EphemeralContainerCommon)(nil),      // 40: spark.EphemeralContainerCommon
-	(*PodReadinessGate)(nil),              // 41: spark.PodReadinessGate
-	(*TopologySpreadConstraint)(nil),      // 42: spark.TopologySpreadConstraint
-	(*PodSchedulingGate)(nil),             // 43: spark.PodSchedulingGate
-	(*PodOS)(nil),                         // 44: spark.PodOS
-	(*PodResourceClaim)(nil),              // 45: spark.PodResourceClaim
-	(*ClaimSource)(nil),                   // 46: spark.ClaimSource
-	(*GPUSpec)(nil),                       // 47: spark.GPUSpec
-	(*NamePath)(nil),                      // 48: spark.NamePath
-	(*SecretInfo)(nil),                    // 49: spark.SecretInfo
-	(*Affinity)(nil),                      // 50: spark.Affinity
-	(*PodAntiAffinity)(nil),               // 51: spark.PodAntiAffinity
-	(*PodAffinity)(nil),                   // 52: spark.PodAffinity
-	(*WeightedPodAffinityTerm)(nil),       // 53: spark.WeightedPodAffinityTerm
-	(*PodAffinityTerm)(nil),               // 54: spark.PodAffinityTerm
-	(*LabelSelector)(nil),                 // 55: spark.LabelSelector
-	(*LabelSelectorRequirement)(nil),      // 56: spark.LabelSelectorRequirement
-	(*NodeAffinity)(nil),                  // 57: spark.NodeAffinity
-	(*PreferredSchedulingTerm)(nil),       // 58: spark.PreferredSchedulingTerm
-	(*NodeSelector)(nil),                  // 59: spark.NodeSelector
-	(*NodeSelectorTerm)(nil),              // 60: spark.NodeSelectorTerm
-	(*NodeSelectorRequirement)(nil),       // 61: spark.NodeSelectorRequirement
-	(*Toleration)(nil),                    // 62: spark.Toleration
-	(*PodSecurityContext)(nil),            // 63: spark.PodSecurityContext
-	(*Sysctl)(nil),                        // 64: spark.Sysctl
-	(*Container)(nil),                     // 65: spark.Container
-	(*ContainerPort)(nil),                 // 66: spark.ContainerPort
-	(*ConfigMapEnvSource)(nil),            // 67: spark.ConfigMapEnvSource
-	(*EnvFromSource)(nil),                 // 68: spark.EnvFromSource
-	(*SecretEnvSource)(nil),               // 69: spark.SecretEnvSource
-	(*EnvVar)(nil),                        // 70: spark.EnvVar
-	(*EnvVarSource)(nil),                  // 71: spark.EnvVarSource
-	(*SecretKeySelector)(nil),             // 72: spark.SecretKeySelector
-	(*ConfigMapKeySelector)(nil),          // 73: spark.ConfigMapKeySelector
-	(*LocalObjectReference)(nil),          // 74: spark.LocalObjectReference
-	(*ResourceFieldSelector)(nil),         // 75: spark.ResourceFieldSelector
-	(*ObjectFieldSelector)(nil),           // 76: spark.ObjectFieldSelector
-	(*ResourceRequirements)(nil),          // 77: spark.ResourceRequirements
-	(*ResourceClaim)(nil),                 // 78: spark.ResourceClaim
-	(*ResourceListEntry)(nil),             // 79: spark.ResourceListEntry
-	(*Quantity)(nil),                      // 80: spark.Quantity
-	(*InfDecAmount)(nil),                  // 81: spark.InfDecAmount
-	(*Int64Amount)(nil),                   // 82: spark.Int64Amount
-	(*Scale)(nil),                         // 83: spark.Scale
-	(*ContainerResizePolicy)(nil),         // 84: spark.ContainerResizePolicy
-	(*VolumeDevice)(nil),                  // 85: spark.VolumeDevice
-	(*ProbeHandler)(nil),                  // 86: spark.ProbeHandler
-	(*Probe)(nil),                         // 87: spark.Probe
-	(*SecurityContext)(nil),               // 88: spark.SecurityContext
-	(*Capabilities)(nil),                  // 89: spark.Capabilities
-	(*SELinuxOptions)(nil),                // 90: spark.SELinuxOptions
-	(*WindowsSecurityContextOptions)(nil), // 91: spark.WindowsSecurityContextOptions
-	(*SeccompProfile)(nil),                // 92: spark.SeccompProfile
-	(*PodDNSConfig)(nil),                  // 93: spark.PodDNSConfig
-	(*PodDNSConfigOption)(nil),            // 94: spark.PodDNSConfigOption
-	(*HostAlias)(nil),                     // 95: spark.HostAlias
-	(*Lifecycle)(nil),                     // 96: spark.Lifecycle
-	(*LifecycleHandler)(nil),              // 97: spark.LifecycleHandler
-	(*SleepAction)(nil),                   // 98: spark.SleepAction
-	(*TCPSocketAction)(nil),               // 99: spark.TCPSocketAction
-	(*ExecAction)(nil),                    // 100: spark.ExecAction
-	(*HTTPGetAction)(nil),                 // 101: spark.HTTPGetAction
-	(*HTTPHeader)(nil),                    // 102: spark.HTTPHeader
-	(*IntOrString)(nil),                   // 103: spark.IntOrString
-	(*Ports)(nil),                         // 104: spark.Ports
-	(*ExecutorSpec)(nil),                  // 105: spark.ExecutorSpec
-	(*Volume)(nil),                        // 106: spark.Volume
-	(*VolumeMount)(nil),                   // 107: spark.VolumeMount
-	(*SparkApplicationStatus)(nil),        // 108: spark.SparkApplicationStatus
-	(*SparkApplication)(nil),              // 109: spark.SparkApplication
-	(*RunAltSparkSubmitRequest)(nil),      // 110: spark.RunAltSparkSubmitRequest
-	(*RunAltSparkSubmitResponse)(nil),     // 111: spark.RunAltSparkSubmitResponse
-	(*Dependencies)(nil),                  // 112: spark.Dependencies
-	(*DynamicAllocation)(nil),             // 113: spark.DynamicAllocation
-	nil,                                   // 114: spark.SparkApplicationSpec.SparkConfEntry
-	nil,                                   // 115: spark.SparkApplicationSpec.HadoopConfEntry
-	nil,                                   // 116: spark.ObjectMeta.LabelsEntry
-	nil,                                   // 117: spark.ObjectMeta.AnnotationsEntry
-	nil,                                   // 118: spark.DriverIngressConfiguration.ServiceAnnotationsEntry
-	nil,                                   // 119: spark.DriverIngressConfiguration.ServiceLabelsEntry
-	nil,                                   // 120: spark.DriverIngressConfiguration.IngressAnnotationsEntry
-	nil,                                   // 121: spark.SparkUIConfiguration.ServiceAnnotationsEntry
-	nil,                                   // 122: spark.SparkUIConfiguration.ServiceLabelsEntry
-	nil,                                   // 123: spark.SparkUIConfiguration.IngressAnnotationsEntry
-	nil,                                   // 124: spark.BatchSchedulerConfiguration.ResourcesEntry
-	nil,                                   // 125: spark.DriverSpec.ServiceAnnotationsEntry
-	nil,                                   // 126: spark.DriverSpec.ServiceLabelsEntry
-	nil,                                   // 127: spark.SparkPodSpec.EnvVarsEntry
-	nil,                                   // 128: spark.SparkPodSpec.LabelsEntry
-	nil,                                   // 129: spark.SparkPodSpec.AnnotationsEntry
-	nil,                                   // 130: spark.SparkPodSpec.NodeSelectorEntry
-	nil,                                   // 131: spark.PodSpec.NodeSelectorEntry
-	nil,                                   // 132: spark.PodSpec.OverheadEntry
-	nil,                                   // 133: spark.LabelSelector.MatchLabelsEntry
-	nil,                                   // 134: spark.ResourceRequirements.LimitsEntry
-	nil,                                   // 135: spark.ResourceRequirements.RequestsEntry
-	(*wrapperspb.StringValue)(nil),        // 136: google.protobuf.StringValue
-	(*wrapperspb.Int32Value)(nil),         // 137: google.protobuf.Int32Value
-	(*wrapperspb.Int64Value)(nil),         // 138: google.protobuf.Int64Value
-	(*timestamppb.Timestamp)(nil),         // 139: google.protobuf.Timestamp
-	(*wrapperspb.BoolValue)(nil),          // 140: google.protobuf.BoolValue
+	(ManagedFieldsOperationType)(0),          // 0: spark.ManagedFieldsOperationType
+	(SparkApplicationType)(0),                // 1: spark.SparkApplicationType
+	(DeployMode)(0),                          // 2: spark.DeployMode
+	(ServiceType)(0),                         // 3: spark.ServiceType
+	(DNSPolicy)(0),                           // 4: spark.DNSPolicy
+	(PodConditionType)(0),                    // 5: spark.PodConditionType
+	(UnsatisfiableConstraintAction)(0),       // 6: spark.UnsatisfiableConstraintAction
+	(NodeInclusionPolicy)(0),                 // 7: spark.NodeInclusionPolicy
+	(SecretType)(0),                          // 8: spark.SecretType
+	(LabelSelectorOperator)(0),               // 9: spark.LabelSelectorOperator
+	(NodeSelectorOperator)(0),                // 10: spark.NodeSelectorOperator
+	(TaintEffect)(0),                         // 11: spark.TaintEffect
+	(TolerationOperator)(0),                  // 12: spark.TolerationOperator
+	(PodFSGroupChangePolicy)(0),              // 13: spark.PodFSGroupChangePolicy
+	(Protocol)(0),                            // 14: spark.Protocol
+	(Format)(0),                              // 15: spark.Format
+	(ResourceResizeRestartPolicy)(0),         // 16: spark.ResourceResizeRestartPolicy
+	(ContainerRestartPolicy)(0),              // 17: spark.ContainerRestartPolicy
+	(TerminationMessagePolicy)(0),            // 18: spark.TerminationMessagePolicy
+	(PullPolicy)(0),                          // 19: spark.PullPolicy
+	(ProcMountType)(0),                       // 20: spark.ProcMountType
+	(SeccompProfileType)(0),                  // 21: spark.SeccompProfileType
+	(URIScheme)(0),                           // 22: spark.URIScheme
+	(AdmissionState)(0),                      // 23: spark.AdmissionState
+	(*SparkApplicationSpec)(nil),             // 24: spark.SparkApplicationSpec
+	(*ObjectMeta)(nil),                       // 25: spark.ObjectMeta
+	(*FieldsV1)(nil),                         // 26: spark.FieldsV1
+	(*ManagedFieldsEntry)(nil),               // 27: spark.ManagedFieldsEntry
+	(*OwnerReference)(nil),                   // 28: spark.OwnerReference
+	(*DriverIngressConfiguration)(nil),       // 29: spark.DriverIngressConfiguration
+	(*SparkUIConfiguration)(nil),             // 30: spark.SparkUIConfiguration
+	(*IngressTLS)(nil),                       // 31: spark.IngressTLS
+	(*BatchSchedulerConfiguration)(nil),      // 32: spark.BatchSchedulerConfiguration
+	(*MonitoringSpec)(nil),                   // 33: spark.MonitoringSpec
+	(*PrometheusSpec)(nil),                   // 34: spark.PrometheusSpec
+	(*RestartPolicy)(nil),                    // 35: spark.RestartPolicy
+	(*DriverSpec)(nil),                       // 36: spark.DriverSpec
+	(*SparkPodSpec)(nil),                     // 37: spark.SparkPodSpec
+	(*PodTemplateSpec)(nil),                  // 38: spark.PodTemplateSpec
+	(*PodSpec)(nil),                          // 39: spark.PodSpec
+	(*EphemeralContainer)(nil),               // 40: spark.EphemeralContainer
+	(*EphemeralContainerCommon)(nil),         // 41: spark.EphemeralContainerCommon
+	(*PodReadinessGate)(nil),                 // 42: spark.PodReadinessGate
+	(*TopologySpreadConstraint)(nil),         // 43: spark.TopologySpreadConstraint
+	(*PodSchedulingGate)(nil),                // 44: spark.PodSchedulingGate
+	(*PodOS)(nil),                            // 45: spark.PodOS
+	(*PodResourceClaim)(nil),                 // 46: spark.PodResourceClaim
+	(*ClaimSource)(nil),                      // 47: spark.ClaimSource
+	(*GPUSpec)(nil),                          // 48: spark.GPUSpec
+	(*NamePath)(nil),                         // 49: spark.NamePath
+	(*SecretInfo)(nil),                       // 50: spark.SecretInfo
+	(*Affinity)(nil),                         // 51: spark.Affinity
+	(*PodAntiAffinity)(nil),                  // 52: spark.PodAntiAffinity
+	(*PodAffinity)(nil),                      // 53: spark.PodAffinity
+	(*WeightedPodAffinityTerm)(nil),          // 54: spark.WeightedPodAffinityTerm
+	(*PodAffinityTerm)(nil),                  // 55: spark.PodAffinityTerm
+	(*LabelSelector)(nil),                    // 56: spark.LabelSelector
+	(*LabelSelectorRequirement)(nil),         // 57: spark.LabelSelectorRequirement
+	(*NodeAffinity)(nil),                     // 58: spark.NodeAffinity
+	(*PreferredSchedulingTerm)(nil),          // 59: spark.PreferredSchedulingTerm
+	(*NodeSelector)(nil),                     // 60: spark.NodeSelector
+	(*NodeSelectorTerm)(nil),                 // 61: spark.NodeSelectorTerm
+	(*NodeSelectorRequirement)(nil),          // 62: spark.NodeSelectorRequirement
+	(*Toleration)(nil),                       // 63: spark.Toleration
+	(*PodSecurityContext)(nil),               // 64: spark.PodSecurityContext
+	(*Sysctl)(nil),                           // 65: spark.Sysctl
+	(*Container)(nil),                        // 66: spark.Container
+	(*ContainerPort)(nil),                    // 67: spark.ContainerPort
+	(*ConfigMapEnvSource)(nil),               // 68: spark.ConfigMapEnvSource
+	(*EnvFromSource)(nil),                    // 69: spark.EnvFromSource
+	(*SecretEnvSource)(nil),                  // 70: spark.SecretEnvSource
+	(*EnvVar)(nil),                           // 71: spark.EnvVar
+	(*EnvVarSource)(nil),                     // 72: spark.EnvVarSource
+	(*SecretKeySelector)(nil),                // 73: spark.SecretKeySelector
+	(*ConfigMapKeySelector)(nil),             // 74: spark.ConfigMapKeySelector
+	(*LocalObjectReference)(nil),             // 75: spark.LocalObjectReference
+	(*ResourceFieldSelector)(nil),            // 76: spark.ResourceFieldSelector
+	(*ObjectFieldSelector)(nil),              // 77: spark.ObjectFieldSelector
+	(*ResourceRequirements)(nil),             // 78: spark.ResourceRequirements
+	(*ResourceClaim)(nil),                    // 79: spark.ResourceClaim
+	(*ResourceListEntry)(nil),                // 80: spark.ResourceListEntry
+	(*Quantity)(nil),                         // 81: spark.Quantity
+	(*InfDecAmount)(nil),                     // 82: spark.InfDecAmount
+	(*Int64Amount)(nil),                      // 83: spark.Int64Amount
+	(*Scale)(nil),                            // 84: spark.Scale
+	(*ContainerResizePolicy)(nil),            // 85: spark.ContainerResizePolicy
+	(*VolumeDevice)(nil),                     // 86: spark.VolumeDevice
+	(*ProbeHandler)(nil),                     // 87: spark.ProbeHandler
+	(*Probe)(nil),                            // 88: spark.Probe
+	(*SecurityContext)(nil),                  // 89: spark.SecurityContext
+	(*Capabilities)(nil),                     // 90: spark.Capabilities
+	(*SELinuxOptions)(nil),                   // 91: spark.SELinuxOptions
+	(*WindowsSecurityContextOptions)(nil),    // 92: spark.WindowsSecurityContextOptions
+	(*SeccompProfile)(nil),                   // 93: spark.SeccompProfile
+	(*PodDNSConfig)(nil),                     // 94: spark.PodDNSConfig
+	(*PodDNSConfigOption)(nil),               // 95: spark.PodDNSConfigOption
+	(*HostAlias)(nil),                        // 96: spark.HostAlias
+	(*Lifecycle)(nil),                        // 97: spark.Lifecycle
+	(*LifecycleHandler)(nil),                 // 98: spark.LifecycleHandler
+	(*SleepAction)(nil),                      // 99: spark.SleepAction
+	(*TCPSocketAction)(nil),                  // 100: spark.TCPSocketAction
+	(*ExecAction)(nil),                       // 101: spark.ExecAction
+	(*HTTPGetAction)(nil),                    // 102: spark.HTTPGetAction
+	(*HTTPHeader)(nil),                       // 103: spark.HTTPHeader
+	(*IntOrString)(nil),                      // 104: spark.IntOrString
+	(*Ports)(nil),                            // 105: spark.Ports
+	(*ExecutorSpec)(nil),                     // 106: spark.ExecutorSpec
+	(*Volume)(nil),                           // 107: spark.Volume
+	(*VolumeMount)(nil),                      // 108: spark.VolumeMount
+	(*SparkApplicationStatus)(nil),           // 109: spark.SparkApplicationStatus
+	(*SparkApplication)(nil),                 // 110: spark.SparkApplication
+	(*RunAltSparkSubmitRequest)(nil),         // 111: spark.RunAltSparkSubmitRequest
+	(*RunAltSparkSubmitResponse)(nil),        // 112: spark.RunAltSparkSubmitResponse
+	(*ValidateSparkApplicationRequest)(nil),  // 113: spark.ValidateSparkApplicationRequest
+	(*ValidateSparkApplicationResponse)(nil), // 114: spark.ValidateSparkApplicationResponse
+	(*FieldError)(nil),                       // 115: spark.FieldError
+	(*ExplainSparkApplicationRequest)(nil),   // 116: spark.ExplainSparkApplicationRequest
+	(*ExplainSparkApplicationResponse)(nil),  // 117: spark.ExplainSparkApplicationResponse
+	(*ExplainedSetting)(nil),                 // 118: spark.ExplainedSetting
+	(*Dependencies)(nil),                     // 119: spark.Dependencies
+	(*DynamicAllocation)(nil),                // 120: spark.DynamicAllocation
+	nil,                                      // 121: spark.SparkApplicationSpec.SparkConfEntry
+	nil,                                      // 122: spark.SparkApplicationSpec.HadoopConfEntry
+	nil,                                      // 123: spark.ObjectMeta.LabelsEntry
+	nil,                                      // 124: spark.ObjectMeta.AnnotationsEntry
+	nil,                                      // 125: spark.DriverIngressConfiguration.ServiceAnnotationsEntry
+	nil,                                      // 126: spark.DriverIngressConfiguration.ServiceLabelsEntry
+	nil,                                      // 127: spark.DriverIngressConfiguration.IngressAnnotationsEntry
+	nil,                                      // 128: spark.SparkUIConfiguration.ServiceAnnotationsEntry
+	nil,                                      // 129: spark.SparkUIConfiguration.ServiceLabelsEntry
+	nil,                                      // 130: spark.SparkUIConfiguration.IngressAnnotationsEntry
+	nil,                                      // 131: spark.BatchSchedulerConfiguration.ResourcesEntry
+	nil,                                      // 132: spark.DriverSpec.ServiceAnnotationsEntry
+	nil,                                      // 133: spark.DriverSpec.ServiceLabelsEntry
+	nil,                                      // 134: spark.SparkPodSpec.EnvVarsEntry
+	nil,                                      // 135: spark.SparkPodSpec.LabelsEntry
+	nil,                                      // 136: spark.SparkPodSpec.AnnotationsEntry
+	nil,                                      // 137: spark.SparkPodSpec.NodeSelectorEntry
+	nil,                                      // 138: spark.PodSpec.NodeSelectorEntry
+	nil,                                      // 139: spark.PodSpec.OverheadEntry
+	nil,                                      // 140: spark.LabelSelector.MatchLabelsEntry
+	nil,                                      // 141: spark.ResourceRequirements.LimitsEntry
+	nil,                                      // 142: spark.ResourceRequirements.RequestsEntry
+	(*wrapperspb.StringValue)(nil),           // 143: google.protobuf.StringValue
+	(*wrapperspb.Int32Value)(nil),            // 144: google.protobuf.Int32Value
+	(*wrapperspb.Int64Value)(nil),            // 145: google.protobuf.Int64Value
+	(*timestamppb.Timestamp)(nil),            // 146: google.protobuf.Timestamp
+	(*wrapperspb.BoolValue)(nil),             // 147: google.protobuf.BoolValue
 }
 var file_proto_spark_submit_proto_depIdxs = []int32{
 	1,   // 0: spark.SparkApplicationSpec.type:type_name -> spark.SparkApplicationType
 	2,   // 1: spark.SparkApplicationSpec.mode:type_name -> spark.DeployMode
-	136, // 2: spark.SparkApplicationSpec.image:type_name -> google.protobuf.StringValue
-	136, // 3: spark.SparkApplicationSpec.image_pull_policy:type_name -> google.protobuf.StringValue
-	114, // 4: spark.SparkApplicationSpec.spark_conf:type_name -> spark.SparkApplicationSpec.SparkConfEntry
-	115, // 5: spark.SparkApplicationSpec.hadoop_conf:type_name -> spark.SparkApplicationSpec.HadoopConfEntry
-	136, // 6: spark.SparkApplicationSpec.spark_config_map:type_name -> google.protobuf.StringValue
-	136, // 7: spark.SparkApplicationSpec.hadoop_config_map:type_name -> google.protobuf.StringValue
-	136, // 8: spark.SparkApplicationSpec.main_class:type_name -> google.protobuf.StringValue
-	136, // 9: spark.SparkApplicationSpec.main_application_file:type_name -> google.protobuf.StringValue
-	136, // 10: spark.SparkApplicationSpec.proxy_user:type_name -> google.protobuf.StringValue
-	137, // 11: spark.SparkApplicationSpec.failure_retries:type_name -> google.protobuf.Int32Value
-	138, // 12: spark.SparkApplicationSpec.retry_interval:type_name -> google.protobuf.Int64Value
-	136, // 13: spark.SparkApplicationSpec.memory_overhead_factor:type_name -> google.protobuf.StringValue
-	32,  // 14: spark.SparkApplicationSpec.monitoring:type_name -> spark.MonitoringSpec
-	136, // 15: spark.SparkApplicationSpec.batch_scheduler:type_name -> google.protobuf.StringValue
-	138, // 16: spark.SparkApplicationSpec.time_to_live_seconds:type_name -> google.protobuf.Int64Value
-	31,  // 17: spark.SparkApplicationSpec.batch_scheduler_configuration:type_name -> spark.BatchSchedulerConfiguration
-	35,  // 18: spark.SparkApplicationSpec.driver:type_name -> spark.DriverSpec
-	105, // 19: spark.SparkApplicationSpec.executor:type_name -> spark.ExecutorSpec
-	106, // 20: spark.SparkApplicationSpec.volumes:type_name -> spark.Volume
-	112, // 21: spark.SparkApplicationSpec.deps:type_name -> spark.Dependencies
-	113, // 22: spark.SparkApplicationSpec.dynamic_allocation:type_name -> spark.DynamicAllocation
-	34,  // 23: spark.SparkApplicationSpec.restart_policy:type_name -> spark.RestartPolicy
-	29,  // 24: spark.SparkApplicationSpec.spark_ui_configuration:type_name -> spark.SparkUIConfiguration
-	28,  // 25: spark.SparkApplicationSpec.driver_ingress_configuration:type_name -> spark.DriverIngressConfiguration
-	139, // 26: spark.ObjectMeta.creation_timestamp:type_name -> google.protobuf.Timestamp
-	139, // 27: spark.ObjectMeta.deletion_timestamp:type_name -> google.protobuf.Timestamp
-	138, // 28: spark.ObjectMeta.deletion_grace_period_seconds:type_name -> google.protobuf.Int64Value
-	116, // 29: spark.ObjectMeta.labels:type_name -> spark.ObjectMeta.LabelsEntry
-	117, // 30: spark.ObjectMeta.annotations:type_name -> spark.ObjectMeta.AnnotationsEntry
-	27,  // 31: spark.ObjectMeta.owner_references:type_name -> spark.OwnerReference
-	26,  // 32: spark.ObjectMeta.managed_fields:type_name -> spark.ManagedFieldsEntry
+	143, // 2: spark.SparkApplicationSpec.image:type_name -> google.protobuf.StringValue
+	143, // 3: spark.SparkApplicationSpec.image_pull_policy:type_name -> google.protobuf.StringValue
+	121, // 4: spark.SparkApplicationSpec.spark_conf:type_name -> spark.SparkApplicationSpec.SparkConfEntry
+	122, // 5: spark.SparkApplicationSpec.hadoop_conf:type_name -> spark.SparkApplicationSpec.HadoopConfEntry
+	143, // 6: spark.SparkApplicationSpec.spark_config_map:type_name -> google.protobuf.StringValue
+	143, // 7: spark.SparkApplicationSpec.hadoop_config_map:type_name -> google.protobuf.StringValue
+	143, // 8: spark.SparkApplicationSpec.main_class:type_name -> google.protobuf.StringValue
+	143, // 9: spark.SparkApplicationSpec.main_application_file:type_name -> google.protobuf.StringValue
+	143, // 10: spark.SparkApplicationSpec.proxy_user:type_name -> google.protobuf.StringValue
+	144, // 11: spark.SparkApplicationSpec.failure_retries:type_name -> google.protobuf.Int32Value
+	145, // 12: spark.SparkApplicationSpec.retry_interval:type_name -> google.protobuf.Int64Value
+	143, // 13: spark.SparkApplicationSpec.memory_overhead_factor:type_name -> google.protobuf.StringValue
+	33,  // 14: spark.SparkApplicationSpec.monitoring:type_name -> spark.MonitoringSpec
+	143, // 15: spark.SparkApplicationSpec.batch_scheduler:type_name -> google.protobuf.StringValue
+	145, // 16: spark.SparkApplicationSpec.time_to_live_seconds:type_name -> google.protobuf.Int64Value
+	32,  // 17: spark.SparkApplicationSpec.batch_scheduler_configuration:type_name -> spark.BatchSchedulerConfiguration
+	36,  // 18: spark.SparkApplicationSpec.driver:type_name -> spark.DriverSpec
+	106, // 19: spark.SparkApplicationSpec.executor:type_name -> spark.ExecutorSpec
+	107, // 20: spark.SparkApplicationSpec.volumes:type_name -> spark.Volume
+	119, // 21: spark.SparkApplicationSpec.deps:type_name -> spark.Dependencies
+	120, // 22: spark.SparkApplicationSpec.dynamic_allocation:type_name -> spark.DynamicAllocation
+	35,  // 23: spark.SparkApplicationSpec.restart_policy:type_name -> spark.RestartPolicy
+	30,  // 24: spark.SparkApplicationSpec.spark_ui_configuration:type_name -> spark.SparkUIConfiguration
+	29,  // 25: spark.SparkApplicationSpec.driver_ingress_configuration:type_name -> spark.DriverIngressConfiguration
+	146, // 26: spark.ObjectMeta.creation_timestamp:type_name -> google.protobuf.Timestamp
+	146, // 27: spark.ObjectMeta.deletion_timestamp:type_name -> google.protobuf.Timestamp
+	145, // 28: spark.ObjectMeta.deletion_grace_period_seconds:type_name -> google.protobuf.Int64Value
+	123, // 29: spark.ObjectMeta.labels:type_name -> spark.ObjectMeta.LabelsEntry
+	124, // 30: spark.ObjectMeta.annotations:type_name -> spark.ObjectMeta.AnnotationsEntry
+	28,  // 31: spark.ObjectMeta.owner_references:type_name -> spark.OwnerReference
+	27,  // 32: spark.ObjectMeta.managed_fields:type_name -> spark.ManagedFieldsEntry
 	0,   // 33: spark.ManagedFieldsEntry.operation:type_name -> spark.ManagedFieldsOperationType
-	139, // 34: spark.ManagedFieldsEntry.my_time:type_name -> google.protobuf.Timestamp
-	25,  // 35: spark.ManagedFieldsEntry.fields_v1:type_name -> spark.FieldsV1
-	140, // 36: spark.OwnerReference.controller:type_name -> google.protobuf.BoolValue
-	140, // 37: spark.OwnerReference.block_owner_deletion:type_name -> google.protobuf.BoolValue
-	137, // 38: spark.DriverIngressConfiguration.service_port:type_name -> google.protobuf.Int32Value
-	136, // 39: spark.DriverIngressConfiguration.service_port_name:type_name -> google.protobuf.StringValue
+	146, // 34: spark.ManagedFieldsEntry.my_time:type_name -> google.protobuf.Timestamp
+	26,  // 35: spark.ManagedFieldsEntry.fields_v1:type_name -> spark.FieldsV1
+	147, // 36: spark.OwnerReference.controller:type_name -> google.protobuf.BoolValue
+	147, // 37: spark.OwnerReference.block_owner_deletion:type_name -> google.protobuf.BoolValue
+	144, // 38: spark.DriverIngressConfiguration.service_port:type_name -> google.protobuf.Int32Value
+	143, // 39: spark.DriverIngressConfiguration.service_port_name:type_name -> google.protobuf.StringValue
 	3,   // 40: spark.DriverIngressConfiguration.service_type:type_name -> spark.ServiceType
-	118, // 41: spark.DriverIngressConfiguration.service_annotations:type_name -> spark.DriverIngressConfiguration.ServiceAnnotationsEntry
-	119, // 42: spark.DriverIngressConfiguration.service_labels:type_name -> spark.DriverIngressConfiguration.ServiceLabelsEntry
-	120, // 43: spark.DriverIngressConfiguration.ingress_annotations:type_name -> spark.DriverIngressConfiguration.IngressAnnotationsEntry
-	30,  // 44: spark.DriverIngressConfiguration.ingress_tls:type_name -> spark.IngressTLS
-	137, // 45: spark.SparkUIConfiguration.service_port:type_name -> google.protobuf.Int32Value
-	136, // 46: spark.SparkUIConfiguration.service_port_name:type_name -> google.protobuf.StringValue
+	125, // 41: spark.DriverIngressConfiguration.service_annotations:type_name -> spark.DriverIngressConfiguration.ServiceAnnotationsEntry
+	126, // 42: spark.DriverIngressConfiguration.service_labels:type_name -> spark.DriverIngressConfiguration.ServiceLabelsEntry
+	127, // 43: spark.DriverIngressConfiguration.ingress_annotations:type_name -> spark.DriverIngressConfiguration.IngressAnnotationsEntry
+	31,  // 44: spark.DriverIngressConfiguration.ingress_tls:type_name -> spark.IngressTLS
+	144, // 45: spark.SparkUIConfiguration.service_port:type_name -> google.protobuf.Int32Value
+	143, // 46: spark.SparkUIConfiguration.service_port_name:type_name -> google.protobuf.StringValue
 	3,   // 47: spark.SparkUIConfiguration.service_type:type_name -> spark.ServiceType
-	121, // 48: spark.SparkUIConfiguration.service_annotations:type_name -> spark.SparkUIConfiguration.ServiceAnnotationsEntry
-	122, // 49: spark.SparkUIConfiguration.service_labels:type_name -> spark.SparkUIConfiguration.ServiceLabelsEntry
-	123, // 50: spark.SparkUIConfiguration.ingress_annotations:type_name -> spark.SparkUIConfiguration.IngressAnnotationsEntry
-	30,  // 51: spark.SparkUIConfiguration.ingress_tls:type_name -> spark.IngressTLS
-	136, // 52: spark.BatchSchedulerConfiguration.queue:type_name -> google.protobuf.StringValue
-	136, // 53: spark.BatchSchedulerConfiguration.priority_class_name:type_name -> google.protobuf.StringValue
-	124, // 54: spark.BatchSchedulerConfiguration.resources:type_name -> spark.BatchSchedulerConfiguration.ResourcesEntry
-	136, // 55: spark.MonitoringSpec.metrics_properties:type_name -> google.protobuf.StringValue
-	136, // 56: spark.MonitoringSpec.metrics_properties_file:type_name -> google.protobuf.StringValue
-	33,  // 57: spark.MonitoringSpec.prometheus:type_name -> spark.PrometheusSpec
-	137, // 58: spark.PrometheusSpec.port:type_name -> google.protobuf.Int32Value
-	136, // 59: spark.PrometheusSpec.port_name:type_name -> google.protobuf.StringValue
-	136, // 60: spark.PrometheusSpec.config_file:type_name -> google.protobuf.StringValue
-	136, // 61: spark.PrometheusSpec.configuration:type_name -> google.protobuf.StringValue
-	36,  // 62: spark.DriverSpec.spark_pod_spec:type_name -> spark.SparkPodSpec
-	136, // 63: spark.DriverSpec.pod_name:type_name -> google.protobuf.StringValue
-	136, // 64: spark.DriverSpec.core_request:type_name -> google.protobuf.StringValue
-	136, // 65: spark.DriverSpec.java_options:type_name -> google.protobuf.StringValue
-	96,  // 66: spark.DriverSpec.life_cycle:type_name -> spark.Lifecycle
-	136, // 67: spark.DriverSpec.kubernetes_master:type_name -> google.protobuf.StringValue
-	125, // 68: spark.DriverSpec.service_annotations:type_name -> spark.DriverSpec.ServiceAnnotationsEntry
-	126, // 69: spark.DriverSpec.service_labels:type_name -> spark.DriverSpec.ServiceLabelsEntry
-	104, // 70: spark.DriverSpec.ports:type_name -> spark.Ports
-	136, // 71: spark.DriverSpec.priority_class_name:type_name -> google.protobuf.StringValue
-	37,  // 72: spark.SparkPodSpec.template:type_name -> spark.PodTemplateSpec
-	137, // 73: spark.SparkPodSpec.cores:type_name -> google.protobuf.Int32Value
-	47,  // 74: spark.SparkPodSpec.gpu:type_name -> spark.GPUSpec
-	48,  // 75: spark.SparkPodSpec.configmaps:type_name -> spark.NamePath
-	49,  // 76: spark.SparkPodSpec.secrets:type_name -> spark.SecretInfo
-	70,  // 77: spark.SparkPodSpec.env:type_name -> spark.EnvVar
-	127, // 78: spark.SparkPodSpec.env_vars:type_name -> spark.SparkPodSpec.EnvVarsEntry
-	68,  // 79: spark.SparkPodSpec.env_from:type_name -> spark.EnvFromSource
-	128, // 80: spark.SparkPodSpec.labels:type_name -> spark.SparkPodSpec.LabelsEntry
-	129, // 81: spark.SparkPodSpec.annotations:type_name -> spark.SparkPodSpec.AnnotationsEntry
-	107, // 82: spark.SparkPodSpec.volume_mounts:type_name -> spark.VolumeMount
-	50,  // 83: spark.SparkPodSpec.affinity:type_name -> spark.Affinity
-	62,  // 84: spark.SparkPodSpec.tolerations:type_name -> spark.Toleration
-	63,  // 85: spark.SparkPodSpec.pod_security_context:type_name -> spark.PodSecurityContext
-	88,  // 86: spark.SparkPodSpec.security_context:type_name -> spark.SecurityContext
-	136, // 87: spark.SparkPodSpec.scheduler_name:type_name -> google.protobuf.StringValue
-	65,  // 88: spark.SparkPodSpec.sidecars:type_name -> spark.Container
-	65,  // 89: spark.SparkPodSpec.init_containers:type_name -> spark.Container
-	140, // 90: spark.SparkPodSpec.host_network:type_name -> google.protobuf.BoolValue
-	130, // 91: spark.SparkPodSpec.node_selector:type_name -> spark.SparkPodSpec.NodeSelectorEntry
-	93,  // 92: spark.SparkPodSpec.dns_config:type_name -> spark.PodDNSConfig
-	136, // 93: spark.SparkPodSpec.service_account:type_name -> google.protobuf.StringValue
-	95,  // 94: spark.SparkPodSpec.host_aliases:type_name -> spark.HostAlias
-	140, // 95: spark.SparkPodSpec.share_process_namespace:type_name -> google.protobuf.BoolValue
-	24,  // 96: spark.PodTemplateSpec.object_meta:type_name -> spark.ObjectMeta
-	38,  // 97: spark.PodTemplateSpec.pod_spec:type_name -> spark.PodSpec
-	106, // 98: spark.PodSpec.volumes:type_name -> spark.Volume
-	65,  // 99: spark.PodSpec.containers:type_name -> spark.Container
-	39,  // 100: spark.PodSpec.ephemeral_containers:type_name -> spark.EphemeralContainer
-	34,  // 101: spark.PodSpec.restart_policy:type_name -> spark.RestartPolicy
-	138, // 102: spark.PodSpec.termination_grace_period_seconds:type_name -> google.protobuf.Int64Value
-	138, // 103: spark.PodSpec.active_deadline_seconds:type_name -> google.protobuf.Int64Value
+	128, // 48: spark.SparkUIConfiguration.service_annotations:type_name -> spark.SparkUIConfiguration.ServiceAnnotationsEntry
+	129, // 49: spark.SparkUIConfiguration.service_labels:type_name -> spark.SparkUIConfiguration.ServiceLabelsEntry
+	130, // 50: spark.SparkUIConfiguration.ingress_annotations:type_name -> spark.SparkUIConfiguration.IngressAnnotationsEntry
+	31,  // 51: spark.SparkUIConfiguration.ingress_tls:type_name -> spark.IngressTLS
+	143, // 52: spark.BatchSchedulerConfiguration.queue:type_name -> google.protobuf.StringValue
+	143, // 53: spark.BatchSchedulerConfiguration.priority_class_name:type_name -> google.protobuf.StringValue
+	131, // 54: spark.BatchSchedulerConfiguration.resources:type_name -> spark.BatchSchedulerConfiguration.ResourcesEntry
+	143, // 55: spark.MonitoringSpec.metrics_properties:type_name -> google.protobuf.StringValue
+	143, // 56: spark.MonitoringSpec.metrics_properties_file:type_name -> google.protobuf.StringValue
+	34,  // 57: spark.MonitoringSpec.prometheus:type_name -> spark.PrometheusSpec
+	144, // 58: spark.PrometheusSpec.port:type_name -> google.protobuf.Int32Value
+	143, // 59: spark.PrometheusSpec.port_name:type_name -> google.protobuf.StringValue
+	143, // 60: spark.PrometheusSpec.config_file:type_name -> google.protobuf.StringValue
+	143, // 61: spark.PrometheusSpec.configuration:type_name -> google.protobuf.StringValue
+	37,  // 62: spark.DriverSpec.spark_pod_spec:type_name -> spark.SparkPodSpec
+	143, // 63: spark.DriverSpec.pod_name:type_name -> google.protobuf.StringValue
+	143, // 64: spark.DriverSpec.core_request:type_name -> google.protobuf.StringValue
+	143, // 65: spark.DriverSpec.java_options:type_name -> google.protobuf.StringValue
+	97,  // 66: spark.DriverSpec.life_cycle:type_name -> spark.Lifecycle
+	143, // 67: spark.DriverSpec.kubernetes_master:type_name -> google.protobuf.StringValue
+	132, // 68: spark.DriverSpec.service_annotations:type_name -> spark.DriverSpec.ServiceAnnotationsEntry
+	133, // 69: spark.DriverSpec.service_labels:type_name -> spark.DriverSpec.ServiceLabelsEntry
+	105, // 70: spark.DriverSpec.ports:type_name -> spark.Ports
+	143, // 71: spark.DriverSpec.priority_class_name:type_name -> google.protobuf.StringValue
+	38,  // 72: spark.SparkPodSpec.template:type_name -> spark.PodTemplateSpec
+	144, // 73: spark.SparkPodSpec.cores:type_name -> google.protobuf.Int32Value
+	48,  // 74: spark.SparkPodSpec.gpu:type_name -> spark.GPUSpec
+	49,  // 75: spark.SparkPodSpec.configmaps:type_name -> spark.NamePath
+	50,  // 76: spark.SparkPodSpec.secrets:type_name -> spark.SecretInfo
+	71,  // 77: spark.SparkPodSpec.env:type_name -> spark.EnvVar
+	134, // 78: spark.SparkPodSpec.env_vars:type_name -> spark.SparkPodSpec.EnvVarsEntry
+	69,  // 79: spark.SparkPodSpec.env_from:type_name -> spark.EnvFromSource
+	135, // 80: spark.SparkPodSpec.labels:type_name -> spark.SparkPodSpec.LabelsEntry
+	136, // 81: spark.SparkPodSpec.annotations:type_name -> spark.SparkPodSpec.AnnotationsEntry
+	108, // 82: spark.SparkPodSpec.volume_mounts:type_name -> spark.VolumeMount
+	51,  // 83: spark.SparkPodSpec.affinity:type_name -> spark.Affinity
+	63,  // 84: spark.SparkPodSpec.tolerations:type_name -> spark.Toleration
+	64,  // 85: spark.SparkPodSpec.pod_security_context:type_name -> spark.PodSecurityContext
+	89,  // 86: spark.SparkPodSpec.security_context:type_name -> spark.SecurityContext
+	143, // 87: spark.SparkPodSpec.scheduler_name:type_name -> google.protobuf.StringValue
+	66,  // 88: spark.SparkPodSpec.sidecars:type_name -> spark.Container
+	66,  // 89: spark.SparkPodSpec.init_containers:type_name -> spark.Container
+	147, // 90: spark.SparkPodSpec.host_network:type_name -> google.protobuf.BoolValue
+	137, // 91: spark.SparkPodSpec.node_selector:type_name -> spark.SparkPodSpec.NodeSelectorEntry
+	94,  // 92: spark.SparkPodSpec.dns_config:type_name -> spark.PodDNSConfig
+	143, // 93: spark.SparkPodSpec.service_account:type_name -> google.protobuf.StringValue
+	96,  // 94: spark.SparkPodSpec.host_aliases:type_name -> spark.HostAlias
+	147, // 95: spark.SparkPodSpec.share_process_namespace:type_name -> google.protobuf.BoolValue
+	25,  // 96: spark.PodTemplateSpec.object_meta:type_name -> spark.ObjectMeta
+	39,  // 97: spark.PodTemplateSpec.pod_spec:type_name -> spark.PodSpec
+	107, // 98: spark.PodSpec.volumes:type_name -> spark.Volume
+	66,  // 99: spark.PodSpec.containers:type_name -> spark.Container
+	40,  // 100: spark.PodSpec.ephemeral_containers:type_name -> spark.EphemeralContainer
+	35,  // 101: spark.PodSpec.restart_policy:type_name -> spark.RestartPolicy
+	145, // 102: spark.PodSpec.termination_grace_period_seconds:type_name -> google.protobuf.Int64Value
+	145, // 103: spark.PodSpec.active_deadline_seconds:type_name -> google.protobuf.Int64Value
 	4,   // 104: spark.PodSpec.dns_policy:type_name -> spark.DNSPolicy
-	131, // 105: spark.PodSpec.node_selector:type_name -> spark.PodSpec.NodeSelectorEntry
-	140, // 106: spark.PodSpec.auto_mount_service_account_token:type_name -> google.protobuf.BoolValue
-	140, // 107: spark.PodSpec.share_process_name:type_name -> google.protobuf.BoolValue
-	63,  // 108: spark.PodSpec.security_context:type_name -> spark.PodSecurityContext
-	74,  // 109: spark.PodSpec.image_pull_secrets:type_name -> spark.LocalObjectReference
-	50,  // 110: spark.PodSpec.affinity:type_name -> spark.Affinity
-	62,  // 111: spark.PodSpec.tolerations:type_name -> spark.Toleration
-	95,  // 112: spark.PodSpec.host_aliases:type_name -> spark.HostAlias
-	137, // 113: spark.PodSpec.priority:type_name -> google.protobuf.Int32Value
-	93,  // 114: spark.PodSpec.dns_config:type_name -> spark.PodDNSConfig
-	41,  // 115: spark.PodSpec.readiness_gates:type_name -> spark.PodReadinessGate
-	136, // 116: spark.PodSpec.runtime_class_name:type_name -> google.protobuf.StringValue
-	140, // 117: spark.PodSpec.enable_service_links:type_name -> google.protobuf.BoolValue
-	132, // 118: spark.PodSpec.overhead:type_name -> spark.PodSpec.OverheadEntry
-	42,  // 119: spark.PodSpec.topology_spread_constraints:type_name -> spark.TopologySpreadConstraint
-	140, // 120: spark.PodSpec.set_host_name_as_fqdn:type_name -> google.protobuf.BoolValue
-	44,  // 121: spark.PodSpec.os:type_name -> spark.PodOS
-	140, // 122: spark.PodSpec.host_users:type_name -> google.protobuf.BoolValue
-	43,  // 123: spark.PodSpec.scheduling_gates:type_name -> spark.PodSchedulingGate
-	45,  // 124: spark.PodSpec.resource_claims:type_name -> spark.PodResourceClaim
-	40,  // 125: spark.EphemeralContainer.ephemeral_container_common:type_name -> spark.EphemeralContainerCommon
-	66,  // 126: spark.EphemeralContainerCommon.ports:type_name -> spark.ContainerPort
-	68,  // 127: spark.EphemeralContainerCommon.env_from:type_name -> spark.EnvFromSource
-	70,  // 128: spark.EphemeralContainerCommon.env:type_name -> spark.EnvVar
-	77,  // 129: spark.EphemeralContainerCommon.resources:type_name -> spark.ResourceRequirements
-	84,  // 130: spark.EphemeralContainerCommon.resize_policy:type_name -> spark.ContainerResizePolicy
+	138, // 105: spark.PodSpec.node_selector:type_name -> spark.PodSpec.NodeSelectorEntry
+	147, // 106: spark.PodSpec.auto_mount_service_account_token:type_name -> google.protobuf.BoolValue
+	147, // 107: spark.PodSpec.share_process_name:type_name -> google.protobuf.BoolValue
+	64,  // 108: spark.PodSpec.security_context:type_name -> spark.PodSecurityContext
+	75,  // 109: spark.PodSpec.image_pull_secrets:type_name -> spark.LocalObjectReference
+	51,  // 110: spark.PodSpec.affinity:type_name -> spark.Affinity
+	63,  // 111: spark.PodSpec.tolerations:type_name -> spark.Toleration
+	96,  // 112: spark.PodSpec.host_aliases:type_name -> spark.HostAlias
+	144, // 113: spark.PodSpec.priority:type_name -> google.protobuf.Int32Value
+	94,  // 114: spark.PodSpec.dns_config:type_name -> spark.PodDNSConfig
+	42,  // 115: spark.PodSpec.readiness_gates:type_name -> spark.PodReadinessGate
+	143, // 116: spark.PodSpec.runtime_class_name:type_name -> google.protobuf.StringValue
+	147, // 117: spark.PodSpec.enable_service_links:type_name -> google.protobuf.BoolValue
+	139, // 118: spark.PodSpec.overhead:type_name -> spark.PodSpec.OverheadEntry
+	43,  // 119: spark.PodSpec.topology_spread_constraints:type_name -> spark.TopologySpreadConstraint
+	147, // 120: spark.PodSpec.set_host_name_as_fqdn:type_name -> google.protobuf.BoolValue
+	45,  // 121: spark.PodSpec.os:type_name -> spark.PodOS
+	147, // 122: spark.PodSpec.host_users:type_name -> google.protobuf.BoolValue
+	44,  // 123: spark.PodSpec.scheduling_gates:type_name -> spark.PodSchedulingGate
+	46,  // 124: spark.PodSpec.resource_claims:type_name -> spark.PodResourceClaim
+	41,  // 125: spark.EphemeralContainer.ephemeral_container_common:type_name -> spark.EphemeralContainerCommon
+	67,  // 126: spark.EphemeralContainerCommon.ports:type_name -> spark.ContainerPort
+	69,  // 127: spark.EphemeralContainerCommon.env_from:type_name -> spark.EnvFromSource
+	71,  // 128: spark.EphemeralContainerCommon.env:type_name -> spark.EnvVar
+	78,  // 129: spark.EphemeralContainerCommon.resources:type_name -> spark.ResourceRequirements
+	85,  // 130: spark.EphemeralContainerCommon.resize_policy:type_name -> spark.ContainerResizePolicy
 	17,  // 131: spark.EphemeralContainerCommon.restart_policy:type_name -> spark.ContainerRestartPolicy
-	107, // 132: spark.EphemeralContainerCommon.volume_mounts:type_name -> spark.VolumeMount
-	85,  // 133: spark.EphemeralContainerCommon.volume_devices:type_name -> spark.VolumeDevice
-	87,  // 134: spark.EphemeralContainerCommon.readiness_probe:type_name -> spark.Probe
-	96,  // 135: spark.EphemeralContainerCommon.life_cycle:type_name -> spark.Lifecycle
+	108, // 132: spark.EphemeralContainerCommon.volume_mounts:type_name -> spark.VolumeMount
+	86,  // 133: spark.EphemeralContainerCommon.volume_devices:type_name -> spark.VolumeDevice
+	88,  // 134: spark.EphemeralContainerCommon.readiness_probe:type_name -> spark.Probe
+	97,  // 135: spark.EphemeralContainerCommon.life_cycle:type_name -> spark.Lifecycle
 	18,  // 136: spark.EphemeralContainerCommon.termination_message_policy:type_name -> spark.TerminationMessagePolicy
 	19,  // 137: spark.EphemeralContainerCommon.image_pull_policy:type_name -> spark.PullPolicy
-	88,  // 138: spark.EphemeralContainerCommon.security_context:type_name -> spark.SecurityContext
+	89,  // 138: spark.EphemeralContainerCommon.security_context:type_name -> spark.SecurityContext
 	5,   // 139: spark.PodReadinessGate.condition_type:type_name -> spark.PodConditionType
 	6,   // 140: spark.TopologySpreadConstraint.when_unsatisfiable:type_name -> spark.UnsatisfiableConstraintAction
-	55,  // 141: spark.TopologySpreadConstraint.label_selector:type_name -> spark.LabelSelector
-	137, // 142: spark.TopologySpreadConstraint.min_domains:type_name -> google.protobuf.Int32Value
+	56,  // 141: spark.TopologySpreadConstraint.label_selector:type_name -> spark.LabelSelector
+	144, // 142: spark.TopologySpreadConstraint.min_domains:type_name -> google.protobuf.Int32Value
 	7,   // 143: spark.TopologySpreadConstraint.node_affinity_policy:type_name -> spark.NodeInclusionPolicy
 	7,   // 144: spark.TopologySpreadConstraint.node_taints_policy:type_name -> spark.NodeInclusionPolicy
-	46,  // 145: spark.PodResourceClaim.source:type_name -> spark.ClaimSource
-	136, // 146: spark.ClaimSource.resource_claim_name:type_name -> google.protobuf.StringValue
-	136, // 147: spark.ClaimSource.resource_claim_template_name:type_name -> google.protobuf.StringValue
+	47,  // 145: spark.PodResourceClaim.source:type_name -> spark.ClaimSource
+	143, // 146: spark.ClaimSource.resource_claim_name:type_name -> google.protobuf.StringValue
+	143, // 147: spark.ClaimSource.resource_claim_template_name:type_name -> google.protobuf.StringValue
 	8,   // 148: spark.SecretInfo.type:type_name -> spark.SecretType
-	57,  // 149: spark.Affinity.node_affinity:type_name -> spark.NodeAffinity
-	52,  // 150: spark.Affinity.pod_affinity:type_name -> spark.PodAffinity
-	51,  // 151: spark.Affinity.pod_anti_affinity:type_name -> spark.PodAntiAffinity
-	55,  // 152: spark.PodAntiAffinity.label_selector:type_name -> spark.LabelSelector
-	55,  // 153: spark.PodAntiAffinity.namespace_selector:type_name -> spark.LabelSelector
-	54,  // 154: spark.PodAffinity.required_during_scheduling_ignored_during_execution:type_name -> spark.PodAffinityTerm
-	53,  // 155: spark.PodAffinity.preferred_during_scheduling_ignored_during_execution:type_name -> spark.WeightedPodAffinityTerm
-	54,  // 156: spark.WeightedPodAffinityTerm.pod_affinity_term:type_name -> spark.PodAffinityTerm
-	55,  // 157: spark.PodAffinityTerm.label_selector:type_name -> spark.LabelSelector
-	55,  // 158: spark.PodAffinityTerm.namespace_selector:type_name -> spark.LabelSelector
-	133, // 159: spark.LabelSelector.match_labels:type_name -> spark.LabelSelector.MatchLabelsEntry
-	56,  // 160: spark.LabelSelector.match_expressions:type_name -> spark.LabelSelectorRequirement
+	58,  // 149: spark.Affinity.node_affinity:type_name -> spark.NodeAffinity
+	53,  // 150: spark.Affinity.pod_affinity:type_name -> spark.PodAffinity
+	52,  // 151: spark.Affinity.pod_anti_affinity:type_name -> spark.PodAntiAffinity
+	56,  // 152: spark.PodAntiAffinity.label_selector:type_name -> spark.LabelSelector
+	56,  // 153: spark.PodAntiAffinity.namespace_selector:type_name -> spark.LabelSelector
+	55,  // 154: spark.PodAffinity.required_during_scheduling_ignored_during_execution:type_name -> spark.PodAffinityTerm
+	54,  // 155: spark.PodAffinity.preferred_during_scheduling_ignored_during_execution:type_name -> spark.WeightedPodAffinityTerm
+	55,  // 156: spark.WeightedPodAffinityTerm.pod_affinity_term:type_name -> spark.PodAffinityTerm
+	56,  // 157: spark.PodAffinityTerm.label_selector:type_name -> spark.LabelSelector
+	56,  // 158: spark.PodAffinityTerm.namespace_selector:type_name -> spark.LabelSelector
+	140, // 159: spark.LabelSelector.match_labels:type_name -> spark.LabelSelector.MatchLabelsEntry
+	57,  // 160: spark.LabelSelector.match_expressions:type_name -> spark.LabelSelectorRequirement
 	9,   // 161: spark.LabelSelectorRequirement.operator:type_name -> spark.LabelSelectorOperator
-	59,  // 162: spark.NodeAffinity.required_during_scheduling_ignored_during_execution:type_name -> spark.NodeSelector
-	58,  // 163: spark.NodeAffinity.preferred_during_scheduling_ignored_during_execution:type_name -> spark.PreferredSchedulingTerm
-	60,  // 164: spark.PreferredSchedulingTerm.preference:type_name -> spark.NodeSelectorTerm
-	60,  // 165: spark.NodeSelector.node_selector_terms:type_name -> spark.NodeSelectorTerm
-	61,  // 166: spark.NodeSelectorTerm.match_expressions:type_name -> spark.NodeSelectorRequirement
-	61,  // 167: spark.NodeSelectorTerm.match_fields:type_name -> spark.NodeSelectorRequirement
+	60,  // 162: spark.NodeAffinity.required_during_scheduling_ignored_during_execution:type_name -> spark.NodeSelector
+	59,  // 163: spark.NodeAffinity.preferred_during_scheduling_ignored_during_execution:type_name -> spark.PreferredSchedulingTerm
+	61,  // 164: spark.PreferredSchedulingTerm.preference:type_name -> spark.NodeSelectorTerm
+	61,  // 165: spark.NodeSelector.node_selector_terms:type_name -> spark.NodeSelectorTerm
+	62,  // 166: spark.NodeSelectorTerm.match_expressions:type_name -> spark.NodeSelectorRequirement
+	62,  // 167: spark.NodeSelectorTerm.match_fields:type_name -> spark.NodeSelectorRequirement
 	10,  // 168: spark.NodeSelectorRequirement.operator:type_name -> spark.NodeSelectorOperator
 	12,  // 169: spark.Toleration.operator:type_name -> spark.TolerationOperator
 	11,  // 170: spark.Toleration.effect:type_name -> spark.TaintEffect
-	138, // 171: spark.Toleration.toleration_seconds:type_name -> google.protobuf.Int64Value
-	90,  // 172: spark.PodSecurityContext.se_linux_options:type_name -> spark.SELinuxOptions
-	91,  // 173: spark.PodSecurityContext.windows_security_context_options:type_name -> spark.WindowsSecurityContextOptions
-	138, // 174: spark.PodSecurityContext.run_as_user:type_name -> google.protobuf.Int64Value
-	138, // 175: spark.PodSecurityContext.run_as_group:type_name -> google.protobuf.Int64Value
-	140, // 176: spark.PodSecurityContext.run_as_nonroot:type_name -> google.protobuf.BoolValue
-	138, // 177: spark.PodSecurityContext.fs_group:type_name -> google.protobuf.Int64Value
-	64,  // 178: spark.PodSecurityContext.sys_ctl:type_name -> spark.Sysctl
+	145, // 171: spark.Toleration.toleration_seconds:type_name -> google.protobuf.Int64Value
+	91,  // 172: spark.PodSecurityContext.se_linux_options:type_name -> spark.SELinuxOptions
+	92,  // 173: spark.PodSecurityContext.windows_security_context_options:type_name -> spark.WindowsSecurityContextOptions
+	145, // 174: spark.PodSecurityContext.run_as_user:type_name -> google.protobuf.Int64Value
+	145, // 175: spark.PodSecurityContext.run_as_group:type_name -> google.protobuf.Int64Value
+	147, // 176: spark.PodSecurityContext.run_as_nonroot:type_name -> google.protobuf.BoolValue
+	145, // 177: spark.PodSecurityContext.fs_group:type_name -> google.protobuf.Int64Value
+	65,  // 178: spark.PodSecurityContext.sys_ctl:type_name -> spark.Sysctl
 	13,  // 179: spark.PodSecurityContext.fs_group_change_policy:type_name -> spark.PodFSGroupChangePolicy
-	92,  // 180: spark.PodSecurityContext.sec_comp_profile:type_name -> spark.SeccompProfile
-	66,  // 181: spark.Container.ports:type_name -> spark.ContainerPort
-	68,  // 182: spark.Container.env_from:type_name -> spark.EnvFromSource
-	70,  // 183: spark.Container.env:type_name -> spark.EnvVar
-	77,  // 184: spark.Container.resources:type_name -> spark.ResourceRequirements
-	84,  // 185: spark.Container.resize_policy:type_name -> spark.ContainerResizePolicy
+	93,  // 180: spark.PodSecurityContext.sec_comp_profile:type_name -> spark.SeccompProfile
+	67,  // 181: spark.Container.ports:type_name -> spark.ContainerPort
+	69,  // 182: spark.Container.env_from:type_name -> spark.EnvFromSource
+	71,  // 183: spark.Container.env:type_name -> spark.EnvVar
+	78,  // 184: spark.Container.resources:type_name -> spark.ResourceRequirements
+	85,  // 185: spark.Container.resize_policy:type_name -> spark.ContainerResizePolicy
 	17,  // 186: spark.Container.restart_policy:type_name -> spark.ContainerRestartPolicy
-	107, // 187: spark.Container.volume_mounts:type_name -> spark.VolumeMount
-	85,  // 188: spark.Container.volume_devices:type_name -> spark.VolumeDevice
-	87,  // 189: spark.Container.liveness_probe:type_name -> spark.Probe
-	87,  // 190: spark.Container.readiness_probe:type_name -> spark.Probe
-	87,  // 191: spark.Container.startup_probe:type_name -> spark.Probe
-	96,  // 192: spark.Container.life_cycle:type_name -> spark.Lifecycle
+	108, // 187: spark.Container.volume_mounts:type_name -> spark.VolumeMount
+	86,  // 188: spark.Container.volume_devices:type_name -> spark.VolumeDevice
+	88,  // 189: spark.Container.liveness_probe:type_name -> spark.Probe
+	88,  // 190: spark.Container.readiness_probe:type_name -> spark.Probe
+	88,  // 191: spark.Container.startup_probe:type_name -> spark.Probe
+	97,  // 192: spark.Container.life_cycle:type_name -> spark.Lifecycle
 	18,  // 193: spark.Container.termination_message_policy:type_name -> spark.TerminationMessagePolicy
 	19,  // 194: spark.Container.image_pull_policy:type_name -> spark.PullPolicy
-	88,  // 195: spark.Container.security_context:type_name -> spark.SecurityContext
+	89,  // 195: spark.Container.security_context:type_name -> spark.SecurityContext
 	14,  // 196: spark.ContainerPort.protocol:type_name -> spark.Protocol
-	74,  // 197: spark.ConfigMapEnvSource.local_object_reference:type_name -> spark.LocalObjectReference
-	140, // 198: spark.ConfigMapEnvSource.optional:type_name -> google.protobuf.BoolValue
-	67,  // 199: spark.EnvFromSource.config_map_ref:type_name -> spark.ConfigMapEnvSource
-	69,  // 200: spark.EnvFromSource.secret_ref:type_name -> spark.SecretEnvSource
-	74,  // 201: spark.SecretEnvSource.local_object_reference:type_name -> spark.LocalObjectReference
-	140, // 202: spark.SecretEnvSource.optional:type_name -> google.protobuf.BoolValue
-	71,  // 203: spark.EnvVar.value_from:type_name -> spark.EnvVarSource
-	76,  // 204: spark.EnvVarSource.field_ref:type_name -> spark.ObjectFieldSelector
-	75,  // 205: spark.EnvVarSource.resource_field_ref:type_name -> spark.ResourceFieldSelector
-	73,  // 206: spark.EnvVarSource.config_map_key_ref:type_name -> spark.ConfigMapKeySelector
-	72,  // 207: spark.EnvVarSource.secret_key_ref:type_name -> spark.SecretKeySelector
-	74,  // 208: spark.SecretKeySelector.local_object_reference:type_name -> spark.LocalObjectReference
-	140, // 209: spark.SecretKeySelector.optional:type_name -> google.protobuf.BoolValue
-	74,  // 210: spark.ConfigMapKeySelector.local_object_reference:type_name -> spark.LocalObjectReference
-	140, // 211: spark.ConfigMapKeySelector.optional:type_name -> google.protobuf.BoolValue
-	80,  // 212: spark.ResourceFieldSelector.divisor:type_name -> spark.Quantity
-	134, // 213: spark.ResourceRequirements.limits:type_name -> spark.ResourceRequirements.LimitsEntry
-	135, // 214: spark.ResourceRequirements.requests:type_name -> spark.ResourceRequirements.RequestsEntry
-	78,  // 215: spark.ResourceRequirements.claims:type_name -> spark.ResourceClaim
-	80,  // 216: spark.ResourceListEntry.quantity:type_name -> spark.Quantity
-	82,  // 217: spark.Quantity.i:type_name -> spark.Int64Amount
-	81,  // 218: spark.Quantity.d:type_name -> spark.InfDecAmount
+	75,  // 197: spark.ConfigMapEnvSource.local_object_reference:type_name -> spark.LocalObjectReference
+	147, // 198: spark.ConfigMapEnvSource.optional:type_name -> google.protobuf.BoolValue
+	68,  // 199: spark.EnvFromSource.config_map_ref:type_name -> spark.ConfigMapEnvSource
+	70,  // 200: spark.EnvFromSource.secret_ref:type_name -> spark.SecretEnvSource
+	75,  // 201: spark.SecretEnvSource.local_object_reference:type_name -> spark.LocalObjectReference
+	147, // 202: spark.SecretEnvSource.optional:type_name -> google.protobuf.BoolValue
+	72,  // 203: spark.EnvVar.value_from:type_name -> spark.EnvVarSource
+	77,  // 204: spark.EnvVarSource.field_ref:type_name -> spark.ObjectFieldSelector
+	76,  // 205: spark.EnvVarSource.resource_field_ref:type_name -> spark.ResourceFieldSelector
+	74,  // 206: spark.EnvVarSource.config_map_key_ref:type_name -> spark.ConfigMapKeySelector
+	73,  // 207: spark.EnvVarSource.secret_key_ref:type_name -> spark.SecretKeySelector
+	75,  // 208: spark.SecretKeySelector.local_object_reference:type_name -> spark.LocalObjectReference
+	147, // 209: spark.SecretKeySelector.optional:type_name -> google.protobuf.BoolValue
+	75,  // 210: spark.ConfigMapKeySelector.local_object_reference:type_name -> spark.LocalObjectReference
+	147, // 211: spark.ConfigMapKeySelector.optional:type_name -> google.protobuf.BoolValue
+	81,  // 212: spark.ResourceFieldSelector.divisor:type_name -> spark.Quantity
+	141, // 213: spark.ResourceRequirements.limits:type_name -> spark.ResourceRequirements.LimitsEntry
+	142, // 214: spark.ResourceRequirements.requests:type_name -> spark.ResourceRequirements.RequestsEntry
+	79,  // 215: spark.ResourceRequirements.claims:type_name -> spark.ResourceClaim
+	81,  // 216: spark.ResourceListEntry.quantity:type_name -> spark.Quantity
+	83,  // 217: spark.Quantity.i:type_name -> spark.Int64Amount
+	82,  // 218: spark.Quantity.d:type_name -> spark.InfDecAmount
 	15,  // 219: spark.Quantity.format:type_name -> spark.Format
-	83,  // 220: spark.Int64Amount.scale:type_name -> spark.Scale
+	84,  // 220: spark.Int64Amount.scale:type_name -> spark.Scale
 	16,  // 221: spark.ContainerResizePolicy.restart_policy:type_name -> spark.ResourceResizeRestartPolicy
-	100, // 222: spark.ProbeHandler.exec:type_name -> spark.ExecAction
-	101, // 223: spark.ProbeHandler.http_get:type_name -> spark.HTTPGetAction
-	99,  // 224: spark.ProbeHandler.tcp_socket:type_name -> spark.TCPSocketAction
-	86,  // 225: spark.Probe.probe_handler:type_name -> spark.ProbeHandler
-	138, // 226: spark.Probe.termination_grace_period_seconds:type_name -> google.protobuf.Int64Value
-	89,  // 227: spark.SecurityContext.capabilities:type_name -> spark.Capabilities
-	140, // 228: spark.SecurityContext.privileged:type_name -> google.protobuf.BoolValue
-	90,  // 229: spark.SecurityContext.se_linux_options:type_name -> spark.SELinuxOptions
-	91,  // 230: spark.SecurityContext.windows_security_context_options:type_name -> spark.WindowsSecurityContextOptions
-	138, // 231: spark.SecurityContext.run_as_user:type_name -> google.protobuf.Int64Value
-	138, // 232: spark.SecurityContext.run_as_group:type_name -> google.protobuf.Int64Value
-	140, // 233: spark.SecurityContext.run_as_non_root:type_name -> google.protobuf.BoolValue
-	140, // 234: spark.SecurityContext.read_only_file_system:type_name -> google.protobuf.BoolValue
-	140, // 235: spark.SecurityContext.allow_privilege_escalation:type_name -> google.protobuf.BoolValue
+	101, // 222: spark.ProbeHandler.exec:type_name -> spark.ExecAction
+	102, // 223: spark.ProbeHandler.http_get:type_name -> spark.HTTPGetAction
+	100, // 224: spark.ProbeHandler.tcp_socket:type_name -> spark.TCPSocketAction
+	87,  // 225: spark.Probe.probe_handler:type_name -> spark.ProbeHandler
+	145, // 226: spark.Probe.termination_grace_period_seconds:type_name -> google.protobuf.Int64Value
+	90,  // 227: spark.SecurityContext.capabilities:type_name -> spark.Capabilities
+	147, // 228: spark.SecurityContext.privileged:type_name -> google.protobuf.BoolValue
+	91,  // 229: spark.SecurityContext.se_linux_options:type_name -> spark.SELinuxOptions
+	92,  // 230: spark.SecurityContext.windows_security_context_options:type_name -> spark.WindowsSecurityContextOptions
+	145, // 231: spark.SecurityContext.run_as_user:type_name -> google.protobuf.Int64Value
+	145, // 232: spark.SecurityContext.run_as_group:type_name -> google.protobuf.Int64Value
+	147, // 233: spark.SecurityContext.run_as_non_root:type_name -> google.protobuf.BoolValue
+	147, // 234: spark.SecurityContext.read_only_file_system:type_name -> google.protobuf.BoolValue
+	147, // 235: spark.SecurityContext.allow_privilege_escalation:type_name -> google.protobuf.BoolValue
 	20,  // 236: spark.SecurityContext.proc_mount:type_name -> spark.ProcMountType
-	92,  // 237: spark.SecurityContext.sec_comp_profile:type_name -> spark.SeccompProfile
-	136, // 238: spark.WindowsSecurityContextOptions.gmsa_credential_spec_name:type_name -> google.protobuf.StringValue
-	136, // 239: spark.WindowsSecurityContextOptions.gmsa_credential_spec:type_name -> google.protobuf.StringValue
-	136, // 240: spark.WindowsSecurityContextOptions.run_as_user_name:type_name -> google.protobuf.StringValue
-	140, // 241: spark.WindowsSecurityContextOptions.host_process:type_name -> google.protobuf.BoolValue
+	93,  // 237: spark.SecurityContext.sec_comp_profile:type_name -> spark.SeccompProfile
+	143, // 238: spark.WindowsSecurityContextOptions.gmsa_credential_spec_name:type_name -> google.protobuf.StringValue
+	143, // 239: spark.WindowsSecurityContextOptions.gmsa_credential_spec:type_name -> google.protobuf.StringValue
+	143, // 240: spark.WindowsSecurityContextOptions.run_as_user_name:type_name -> google.protobuf.StringValue
+	147, // 241: spark.WindowsSecurityContextOptions.host_process:type_name -> google.protobuf.BoolValue
 	21,  // 242: spark.SeccompProfile.type:type_name -> spark.SeccompProfileType
-	136, // 243: spark.SeccompProfile.local_host_profile:type_name -> google.protobuf.StringValue
-	94,  // 244: spark.PodDNSConfig.options:type_name -> spark.PodDNSConfigOption
-	97,  // 245: spark.Lifecycle.post_start:type_name -> spark.LifecycleHandler
-	97,  // 246: spark.Lifecycle.pre_stop:type_name -> spark.LifecycleHandler
-	100, // 247: spark.LifecycleHandler.exec:type_name -> spark.ExecAction
-	101, // 248: spark.LifecycleHandler.http_get:type_name -> spark.HTTPGetAction
-	99,  // 249: spark.LifecycleHandler.tcp_socket:type_name -> spark.TCPSocketAction
-	98,  // 250: spark.LifecycleHandler.sleep:type_name -> spark.SleepAction
-	103, // 251: spark.TCPSocketAction.port:type_name -> spark.IntOrString
-	103, // 252: spark.HTTPGetAction.port:type_name -> spark.IntOrString
+	143, // 243: spark.SeccompProfile.local_host_profile:type_name -> google.protobuf.StringValue
+	95,  // 244: spark.PodDNSConfig.options:type_name -> spark.PodDNSConfigOption
+	98,  // 245: spark.Lifecycle.post_start:type_name -> spark.LifecycleHandler
+	98,  // 246: spark.Lifecycle.pre_stop:type_name -> spark.LifecycleHandler
+	101, // 247: spark.LifecycleHandler.exec:type_name -> spark.ExecAction
+	102, // 248: spark.LifecycleHandler.http_get:type_name -> spark.HTTPGetAction
+	100, // 249: spark.LifecycleHandler.tcp_socket:type_name -> spark.TCPSocketAction
+	99,  // 250: spark.LifecycleHandler.sleep:type_name -> spark.SleepAction
+	104, // 251: spark.TCPSocketAction.port:type_name -> spark.IntOrString
+	104, // 252: spark.HTTPGetAction.port:type_name -> spark.IntOrString
 	22,  // 253: spark.HTTPGetAction.scheme:type_name -> spark.URIScheme
-	102, // 254: spark.HTTPGetAction.http_headers:type_name -> spark.HTTPHeader
-	36,  // 255: spark.ExecutorSpec.spark_pod_spec:type_name -> spark.SparkPodSpec
-	137, // 256: spark.ExecutorSpec.instances:type_name -> google.protobuf.Int32Value
-	136, // 257: spark.ExecutorSpec.core_request:type_name -> google.protobuf.StringValue
-	136, // 258: spark.ExecutorSpec.java_options:type_name -> google.protobuf.StringValue
-	96,  // 259: spark.ExecutorSpec.life_cycle:type_name -> spark.Lifecycle
-	140, // 260: spark.ExecutorSpec.delete_on_termination:type_name -> google.protobuf.BoolValue
-	104, // 261: spark.ExecutorSpec.ports:type_name -> spark.Ports
-	136, // 262: spark.ExecutorSpec.priority_class_name:type_name -> google.protobuf.StringValue
-	24,  // 263: spark.SparkApplication.metadata:type_name -> spark.ObjectMeta
-	23,  // 264: spark.SparkApplication.spec:type_name -> spark.SparkApplicationSpec
-	108, // 265: spark.SparkApplication.status:type_name -> spark.SparkApplicationStatus
-	109, // 266: spark.RunAltSparkSubmitRequest.spark_application:type_name -> spark.SparkApplication
-	80,  // 267: spark.BatchSchedulerConfiguration.ResourcesEntry.value:type_name -> spark.Quantity
-	80,  // 268: spark.PodSpec.OverheadEntry.value:type_name -> spark.Quantity
-	80,  // 269: spark.ResourceRequirements.LimitsEntry.value:type_name -> spark.Quantity
-	80,  // 270: spark.ResourceRequirements.RequestsEntry.value:type_name -> spark.Quantity
-	110, // 271: spark.SparkSubmitService.RunAltSparkSubmit:input_type -> spark.RunAltSparkSubmitRequest
-	111, // 272: spark.SparkSubmitService.RunAltSparkSubmit:output_type -> spark.RunAltSparkSubmitResponse
-	272, // [272:273] is the sub-list for method output_type
-	271, // [271:272] is the sub-list for method input_type
-	271, // [271:271] is the sub-list for extension type_name
-	271, // [271:271] is the sub-list for extension extendee
-	0,   // [0:271] is the sub-list for field type_name
+	103, // 254: spark.HTTPGetAction.http_headers:type_name -> spark.HTTPHeader
+	37,  // 255: spark.ExecutorSpec.spark_pod_spec:type_name -> spark.SparkPodSpec
+	144, // 256: spark.ExecutorSpec.instances:type_name -> google.protobuf.Int32Value
+	143, // 257: spark.ExecutorSpec.core_request:type_name -> google.protobuf.StringValue
+	143, // 258: spark.ExecutorSpec.java_options:type_name -> google.protobuf.StringValue
+	97,  // 259: spark.ExecutorSpec.life_cycle:type_name -> spark.Lifecycle
+	147, // 260: spark.ExecutorSpec.delete_on_termination:type_name -> google.protobuf.BoolValue
+	105, // 261: spark.ExecutorSpec.ports:type_name -> spark.Ports
+	143, // 262: spark.ExecutorSpec.priority_class_name:type_name -> google.protobuf.StringValue
+	25,  // 263: spark.SparkApplication.metadata:type_name -> spark.ObjectMeta
+	24,  // 264: spark.SparkApplication.spec:type_name -> spark.SparkApplicationSpec
+	109, // 265: spark.SparkApplication.status:type_name -> spark.SparkApplicationStatus
+	110, // 266: spark.RunAltSparkSubmitRequest.spark_application:type_name -> spark.SparkApplication
+	23,  // 267: spark.RunAltSparkSubmitResponse.admission_state:type_name -> spark.AdmissionState
+	110, // 268: spark.ValidateSparkApplicationRequest.spark_application:type_name -> spark.SparkApplication
+	115, // 269: spark.ValidateSparkApplicationResponse.errors:type_name -> spark.FieldError
+	110, // 270: spark.ExplainSparkApplicationRequest.spark_application:type_name -> spark.SparkApplication
+	118, // 271: spark.ExplainSparkApplicationResponse.settings:type_name -> spark.ExplainedSetting
+	118, // 272: spark.ExplainedSetting.overridden:type_name -> spark.ExplainedSetting
+	81,  // 273: spark.BatchSchedulerConfiguration.ResourcesEntry.value:type_name -> spark.Quantity
+	81,  // 274: spark.PodSpec.OverheadEntry.value:type_name -> spark.Quantity
+	81,  // 275: spark.ResourceRequirements.LimitsEntry.value:type_name -> spark.Quantity
+	81,  // 276: spark.ResourceRequirements.RequestsEntry.value:type_name -> spark.Quantity
+	111, // 277: spark.SparkSubmitService.RunAltSparkSubmit:input_type -> spark.RunAltSparkSubmitRequest
+	113, // 278: spark.SparkSubmitService.ValidateSparkApplication:input_type -> spark.ValidateSparkApplicationRequest
+	116, // 279: spark.SparkSubmitService.ExplainSparkApplication:input_type -> spark.ExplainSparkApplicationRequest
+	112, // 280: spark.SparkSubmitService.RunAltSparkSubmit:output_type -> spark.RunAltSparkSubmitResponse
+	114, // 281: spark.SparkSubmitService.ValidateSparkApplication:output_type -> spark.ValidateSparkApplicationResponse
+	117, // 282: spark.SparkSubmitService.ExplainSparkApplication:output_type -> spark.ExplainSparkApplicationResponse
+	280, // [280:283] is the sub-list for method output_type
+	277, // [277:280] is the sub-list for method input_type
+	277, // [277:277] is the sub-list for extension type_name
+	277, // [277:277] is the sub-list for extension extendee
+	0,   // [0:277] is the sub-list for field type_name
 }
 
 func init() { file_proto_spark_submit_proto_init() }
@@ -9279,8 +9745,8 @@ func file_proto_spark_submit_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_spark_submit_proto_rawDesc), len(file_proto_spark_submit_proto_rawDesc)),
-			NumEnums:      23,
-			NumMessages:   113,
+			NumEnums:      24,
+			NumMessages:   119,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
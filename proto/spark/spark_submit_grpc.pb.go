@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SparkSubmitService_RunAltSparkSubmit_FullMethodName = "/spark.SparkSubmitService/RunAltSparkSubmit"
+	SparkSubmitService_RunAltSparkSubmit_FullMethodName        = "/spark.SparkSubmitService/RunAltSparkSubmit"
+	SparkSubmitService_ValidateSparkApplication_FullMethodName = "/spark.SparkSubmitService/ValidateSparkApplication"
+	SparkSubmitService_ExplainSparkApplication_FullMethodName  = "/spark.SparkSubmitService/ExplainSparkApplication"
 )
 
 // SparkSubmitServiceClient is the client API for SparkSubmitService service.
@@ -29,6 +31,8 @@ const (
 // The Spark submit service definition.
 type SparkSubmitServiceClient interface {
 	RunAltSparkSubmit(ctx context.Context, in *RunAltSparkSubmitRequest, opts ...grpc.CallOption) (*RunAltSparkSubmitResponse, error)
+	ValidateSparkApplication(ctx context.Context, in *ValidateSparkApplicationRequest, opts ...grpc.CallOption) (*ValidateSparkApplicationResponse, error)
+	ExplainSparkApplication(ctx context.Context, in *ExplainSparkApplicationRequest, opts ...grpc.CallOption) (*ExplainSparkApplicationResponse, error)
 }
 
 type sparkSubmitServiceClient struct {
@@ -49,6 +53,26 @@ func (c *sparkSubmitServiceClient) RunAltSparkSubmit(ctx context.Context, in *Ru
 	return out, nil
 }
 
+func (c *sparkSubmitServiceClient) ValidateSparkApplication(ctx context.Context, in *ValidateSparkApplicationRequest, opts ...grpc.CallOption) (*ValidateSparkApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateSparkApplicationResponse)
+	err := c.cc.Invoke(ctx, SparkSubmitService_ValidateSparkApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sparkSubmitServiceClient) ExplainSparkApplication(ctx context.Context, in *ExplainSparkApplicationRequest, opts ...grpc.CallOption) (*ExplainSparkApplicationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainSparkApplicationResponse)
+	err := c.cc.Invoke(ctx, SparkSubmitService_ExplainSparkApplication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SparkSubmitServiceServer is the server API for SparkSubmitService service.
 // All implementations must embed UnimplementedSparkSubmitServiceServer
 // for forward compatibility.
@@ -56,6 +80,8 @@ func (c *sparkSubmitServiceClient) RunAltSparkSubmit(ctx context.Context, in *Ru
 // The Spark submit service definition.
 type SparkSubmitServiceServer interface {
 	RunAltSparkSubmit(context.Context, *RunAltSparkSubmitRequest) (*RunAltSparkSubmitResponse, error)
+	ValidateSparkApplication(context.Context, *ValidateSparkApplicationRequest) (*ValidateSparkApplicationResponse, error)
+	ExplainSparkApplication(context.Context, *ExplainSparkApplicationRequest) (*ExplainSparkApplicationResponse, error)
 	mustEmbedUnimplementedSparkSubmitServiceServer()
 }
 
@@ -69,6 +95,12 @@ type UnimplementedSparkSubmitServiceServer struct{}
 func (UnimplementedSparkSubmitServiceServer) RunAltSparkSubmit(context.Context, *RunAltSparkSubmitRequest) (*RunAltSparkSubmitResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RunAltSparkSubmit not implemented")
 }
+func (UnimplementedSparkSubmitServiceServer) ValidateSparkApplication(context.Context, *ValidateSparkApplicationRequest) (*ValidateSparkApplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateSparkApplication not implemented")
+}
+func (UnimplementedSparkSubmitServiceServer) ExplainSparkApplication(context.Context, *ExplainSparkApplicationRequest) (*ExplainSparkApplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainSparkApplication not implemented")
+}
 func (UnimplementedSparkSubmitServiceServer) mustEmbedUnimplementedSparkSubmitServiceServer() {}
 func (UnimplementedSparkSubmitServiceServer) testEmbeddedByValue()                            {}
 
@@ -108,6 +140,42 @@ func _SparkSubmitService_RunAltSparkSubmit_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SparkSubmitService_ValidateSparkApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateSparkApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SparkSubmitServiceServer).ValidateSparkApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SparkSubmitService_ValidateSparkApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SparkSubmitServiceServer).ValidateSparkApplication(ctx, req.(*ValidateSparkApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SparkSubmitService_ExplainSparkApplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainSparkApplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SparkSubmitServiceServer).ExplainSparkApplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SparkSubmitService_ExplainSparkApplication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SparkSubmitServiceServer).ExplainSparkApplication(ctx, req.(*ExplainSparkApplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SparkSubmitService_ServiceDesc is the grpc.ServiceDesc for SparkSubmitService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -119,6 +187,14 @@ var SparkSubmitService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RunAltSparkSubmit",
 			Handler:    _SparkSubmitService_RunAltSparkSubmit_Handler,
 		},
+		{
+			MethodName: "ValidateSparkApplication",
+			Handler:    _SparkSubmitService_ValidateSparkApplication_Handler,
+		},
+		{
+			MethodName: "ExplainSparkApplication",
+			Handler:    _SparkSubmitService_ExplainSparkApplication_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/spark_submit.proto",